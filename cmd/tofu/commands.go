@@ -210,6 +210,12 @@ func initCommands(
 			}, nil
 		},
 
+		"import discover": func() (cli.Command, error) {
+			return &command.ImportDiscoverCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"init": func() (cli.Command, error) {
 			return &command.InitCommand{
 				Meta: meta,
@@ -282,6 +288,18 @@ func initCommands(
 			}, nil
 		},
 
+		"refactor": func() (cli.Command, error) {
+			return &command.RefactorCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"refactor suggest": func() (cli.Command, error) {
+			return &command.RefactorSuggestCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"refresh": func() (cli.Command, error) {
 			return &command.RefreshCommand{
 				Meta: meta,
@@ -31,6 +31,7 @@ import (
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/targeting"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
 )
@@ -267,6 +268,11 @@ type Operation struct {
 	PlanOutPath    string // PlanOutPath is the path to save the plan
 	PlanOutBackend *plans.Backend
 
+	// PlanOutSchemas asks for the parts of the provider schemas needed to
+	// render the plan to be embedded in the plan file saved at PlanOutPath,
+	// so that the plan can be shown without the providers installed.
+	PlanOutSchemas bool
+
 	// ConfigDir is the path to the directory containing the configuration's
 	// root module.
 	ConfigDir string
@@ -300,6 +306,11 @@ type Operation struct {
 	Targets      []addrs.Targetable
 	Excludes     []addrs.Targetable
 	ForceReplace []addrs.AbsResourceInstance
+	// TargetSelectors and ExcludeSelectors must be resolved against the
+	// configuration and prior state by the backend, and the results added
+	// to Targets and Excludes respectively.
+	TargetSelectors  []targeting.Selector
+	ExcludeSelectors []targeting.Selector
 	// Injected by the command creating the operation (plan/apply/refresh/etc...)
 	Variables map[string]UnparsedVariableValue
 	RootCall  configs.StaticModuleCall
@@ -10,11 +10,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
@@ -22,6 +24,7 @@ import (
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/targeting"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
 	"github.com/opentofu/opentofu/internal/tofumigrate"
@@ -241,6 +244,14 @@ func (b *Local) localRunDirect(ctx context.Context, stopCtx context.Context, op
 	}
 	run.InputState = state
 
+	targets, excludes, selectorDiags := resolveTargetSelectors(op, config, state)
+	diags = diags.Append(selectorDiags)
+	if selectorDiags.HasErrors() {
+		return nil, nil, diags
+	}
+	planOpts.Targets = targets
+	planOpts.Excludes = excludes
+
 	tfCtx, moreDiags := tofu.NewContext(coreOpts)
 	diags = diags.Append(moreDiags)
 	if moreDiags.HasErrors() {
@@ -250,6 +261,33 @@ func (b *Local) localRunDirect(ctx context.Context, stopCtx context.Context, op
 	return run, configSnap, diags
 }
 
+// resolveTargetSelectors resolves any target and exclude selectors in the
+// given operation against the given configuration and state, returning the
+// complete lists of targets and excludes to use for planning.
+func resolveTargetSelectors(op *backend.Operation, config *configs.Config, state *states.State) ([]addrs.Targetable, []addrs.Targetable, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	selectedTargets, moreDiags := targeting.Resolve(op.TargetSelectors, config, state, "target")
+	diags = diags.Append(moreDiags)
+	if len(op.TargetSelectors) != 0 && len(selectedTargets) == 0 && len(op.Targets) == 0 {
+		// If we were to continue here then the plan would not be targeted
+		// at all, which is the opposite of what the user asked for.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No resources match the target selectors",
+			"None of the selectors given in the -target options match any resource in the configuration or the current state, so there is nothing to plan.",
+		))
+		return nil, nil, diags
+	}
+
+	selectedExcludes, moreDiags := targeting.Resolve(op.ExcludeSelectors, config, state, "exclude")
+	diags = diags.Append(moreDiags)
+
+	targets := append(slices.Clip(op.Targets), selectedTargets...)
+	excludes := append(slices.Clip(op.Excludes), selectedExcludes...)
+	return targets, excludes, diags
+}
+
 func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation, pf *planfile.Reader, run *backend.LocalRun, coreOpts *tofu.ContextOpts, currentStateMeta *statemgr.SnapshotMeta) (*backend.LocalRun, *configload.Snapshot, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -29,6 +29,7 @@ import (
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/targeting"
 	"github.com/opentofu/opentofu/internal/terminal"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -127,6 +128,49 @@ func TestLocalRun_error(t *testing.T) {
 	assertBackendStateUnlocked(t, b)
 }
 
+func TestLocalRun_resolveTargetSelectors(t *testing.T) {
+	config, _ := initwd.MustLoadConfigForTests(t, "./testdata/plan", "tests")
+
+	t.Run("match", func(t *testing.T) {
+		selector, diags := targeting.ParseSelector("type=test_instance")
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		op := &backend.Operation{
+			TargetSelectors: []targeting.Selector{selector},
+		}
+
+		targets, excludes, diags := resolveTargetSelectors(op, config, states.NewState())
+		if diags.HasErrors() {
+			t.Fatalf("unexpected error: %s", diags.Err())
+		}
+		if len(excludes) != 0 {
+			t.Errorf("unexpected excludes: %s", excludes)
+		}
+		if len(targets) != 1 || targets[0].String() != "test_instance.foo" {
+			t.Errorf("wrong targets: %s", targets)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		selector, diags := targeting.ParseSelector("type=other_instance")
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		op := &backend.Operation{
+			TargetSelectors: []targeting.Selector{selector},
+		}
+
+		_, _, diags = resolveTargetSelectors(op, config, states.NewState())
+		if !diags.HasErrors() {
+			t.Fatal("unexpected success")
+		}
+		if got, want := diags.Err().Error(), "No resources match the target selectors"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+}
+
 func TestLocalRun_cloudPlan(t *testing.T) {
 	configDir := "./testdata/apply"
 	b := TestLocal(t)
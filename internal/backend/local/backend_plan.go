@@ -11,11 +11,16 @@ import (
 	"io"
 	"log"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/genconfig"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -175,6 +180,21 @@ func (b *Local) opPlan(
 			State: plan.PrevRunState,
 		}
 
+		// If requested, we embed the subset of the schemas needed to render
+		// the plan, so that the saved plan can be shown without the providers
+		// installed.
+		var providerSchemas map[addrs.Provider]providers.ProviderSchema
+		var provisionerSchemas map[string]*configschema.Block
+		if op.PlanOutSchemas {
+			schemas, moreDiags := lr.Core.Schemas(ctx, lr.Config, lr.InputState)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				op.ReportResult(runningOp, diags)
+				return
+			}
+			providerSchemas, provisionerSchemas = planSchemas(schemas, lr.Config, plan)
+		}
+
 		log.Printf("[INFO] backend/local: writing plan output to: %s", path)
 		err := planfile.Create(path, planfile.CreateArgs{
 			ConfigSnapshot:       configSnap,
@@ -182,6 +202,8 @@ func (b *Local) opPlan(
 			StateFile:            plannedStateFile,
 			Plan:                 plan,
 			DependencyLocks:      op.DependencyLocks,
+			ProviderSchemas:      providerSchemas,
+			ProvisionerSchemas:   provisionerSchemas,
 		}, op.Encryption.Plan())
 		if err != nil {
 			diags = diags.Append(tfdiags.Sourceless(
@@ -263,3 +285,105 @@ func maybeWriteGeneratedConfig(plan *plans.Plan, out string) (wroteConfig bool,
 
 	return wroteConfig, diags
 }
+
+// planSchemas returns the parts of the given schemas that are needed to
+// render the given plan along with the configuration it was created from,
+// which is far smaller than the complete schemas for most providers.
+func planSchemas(schemas *tofu.Schemas, config *configs.Config, plan *plans.Plan) (map[addrs.Provider]providers.ProviderSchema, map[string]*configschema.Block) {
+	type resourceType struct {
+		mode addrs.ResourceMode
+		name string
+	}
+	used := make(map[addrs.Provider]map[resourceType]struct{})
+	provisioners := make(map[string]struct{})
+	use := func(provider addrs.Provider, mode addrs.ResourceMode, name string) {
+		if used[provider] == nil {
+			used[provider] = make(map[resourceType]struct{})
+		}
+		used[provider][resourceType{mode, name}] = struct{}{}
+	}
+
+	if config != nil {
+		config.DeepEach(func(c *configs.Config) {
+			for _, pc := range c.Module.ProviderConfigs {
+				provider := c.ProviderForConfigAddr(pc.Addr())
+				if used[provider] == nil {
+					used[provider] = make(map[resourceType]struct{})
+				}
+			}
+			for _, rc := range c.Module.ManagedResources {
+				use(rc.Provider, rc.Mode, rc.Type)
+				if rc.Managed != nil {
+					for _, p := range rc.Managed.Provisioners {
+						provisioners[p.Type] = struct{}{}
+					}
+				}
+			}
+			for _, rc := range c.Module.DataResources {
+				use(rc.Provider, rc.Mode, rc.Type)
+			}
+			for _, rc := range c.Module.EphemeralResources {
+				use(rc.Provider, rc.Mode, rc.Type)
+			}
+		})
+	}
+	for _, state := range []*states.State{plan.PrevRunState, plan.PriorState} {
+		if state == nil {
+			continue
+		}
+		for _, ms := range state.Modules {
+			for _, rs := range ms.Resources {
+				use(rs.ProviderConfig.Provider, rs.Addr.Resource.Mode, rs.Addr.Resource.Type)
+			}
+		}
+	}
+	if plan.Changes != nil {
+		for _, rc := range plan.Changes.Resources {
+			use(rc.ProviderAddr.Provider, rc.Addr.Resource.Resource.Mode, rc.Addr.Resource.Resource.Type)
+		}
+	}
+	for _, rc := range plan.DriftedResources {
+		use(rc.ProviderAddr.Provider, rc.Addr.Resource.Resource.Mode, rc.Addr.Resource.Resource.Type)
+	}
+
+	providerSchemas := make(map[addrs.Provider]providers.ProviderSchema, len(used))
+	for provider, types := range used {
+		full, ok := schemas.Providers[provider]
+		if !ok {
+			continue
+		}
+		trimmed := providers.ProviderSchema{
+			Provider:           full.Provider,
+			ProviderMeta:       full.ProviderMeta,
+			ResourceTypes:      make(map[string]providers.Schema),
+			DataSources:        make(map[string]providers.Schema),
+			EphemeralResources: make(map[string]providers.Schema),
+		}
+		for rt := range types {
+			var src, dst map[string]providers.Schema
+			switch rt.mode {
+			case addrs.ManagedResourceMode:
+				src, dst = full.ResourceTypes, trimmed.ResourceTypes
+			case addrs.DataResourceMode:
+				src, dst = full.DataSources, trimmed.DataSources
+			case addrs.EphemeralResourceMode:
+				src, dst = full.EphemeralResources, trimmed.EphemeralResources
+			default:
+				continue
+			}
+			if schema, ok := src[rt.name]; ok {
+				dst[rt.name] = schema
+			}
+		}
+		providerSchemas[provider] = trimmed
+	}
+
+	provisionerSchemas := make(map[string]*configschema.Block, len(provisioners))
+	for name := range provisioners {
+		if schema, ok := schemas.Provisioners[name]; ok {
+			provisionerSchemas[name] = schema
+		}
+	}
+
+	return providerSchemas, provisionerSchemas
+}
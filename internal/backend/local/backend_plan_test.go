@@ -7,6 +7,7 @@ package local
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -587,6 +588,76 @@ func TestLocal_planDestroy(t *testing.T) {
 	}
 }
 
+func TestLocal_planEmbedsSchemas(t *testing.T) {
+	tests := map[string]bool{
+		"embedded":     true,
+		"not embedded": false,
+	}
+	for name, embed := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := TestLocal(t)
+
+			TestLocalProvider(t, b, "test", planFixtureSchema())
+
+			outDir := t.TempDir()
+			planPath := filepath.Join(outDir, "plan.tfplan")
+
+			op, done := testOperationPlan(t, "./testdata/plan")
+			op.PlanOutPath = planPath
+			op.PlanOutSchemas = embed
+			cfg := cty.ObjectVal(map[string]cty.Value{
+				"path": cty.StringVal(b.StatePath),
+			})
+			cfgRaw, err := plans.NewDynamicValue(cfg, cfg.Type())
+			if err != nil {
+				t.Fatal(err)
+			}
+			op.PlanOutBackend = &plans.Backend{
+				// Just a placeholder so that we can generate a valid plan file.
+				Type:   "local",
+				Config: cfgRaw,
+			}
+
+			run, err := b.Operation(context.Background(), op)
+			if err != nil {
+				t.Fatalf("bad: %s", err)
+			}
+			<-run.Done()
+			if run.Result != backend.OperationSuccess {
+				t.Fatalf("plan operation failed")
+			}
+			done(t)
+
+			p, err := planfile.Open(planPath, encryption.PlanEncryptionDisabled())
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			schemas, err := p.ReadProviderSchemas()
+			if !embed {
+				if !errors.Is(err, planfile.ErrNoSchemas) {
+					t.Fatalf("expected no embedded schemas, got error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			schema, ok := schemas[addrs.NewDefaultProvider("test")]
+			if !ok {
+				t.Fatalf("no schema embedded for the test provider")
+			}
+			if _, ok := schema.ResourceTypes["test_instance"]; !ok {
+				t.Errorf("no schema embedded for test_instance")
+			}
+			// The configuration doesn't use this data source, so its schema
+			// should not have been included.
+			if _, ok := schema.DataSources["test_ds"]; ok {
+				t.Errorf("unexpected schema embedded for test_ds")
+			}
+		})
+	}
+}
+
 func TestLocal_planDestroy_withDataSources(t *testing.T) {
 	b := TestLocal(t)
 
@@ -195,6 +195,13 @@ func (p *Provider) ImportResourceState(_ context.Context, req providers.ImportRe
 	panic("unimplemented - terraform_remote_state has no resources")
 }
 
+// ListResource is used to enumerate existing remote objects, which this
+// provider doesn't support for any of its resource types.
+func (p *Provider) ListResource(_ context.Context, req providers.ListResourceRequest) (resp providers.ListResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("resource type %q does not support listing", req.TypeName))
+	return resp
+}
+
 // MoveResourceState is called when the state loader encounters an instance state
 // that has been moved to a new type, and the state should be updated to reflect the change.
 // This is used to move the old state to the new schema.
@@ -87,6 +87,14 @@ func (b *Cloud) opApply(ctx, stopCtx, cancelCtx context.Context, op *backend.Ope
 		))
 	}
 
+	if len(op.TargetSelectors) != 0 || len(op.ExcludeSelectors) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Target selectors are not supported",
+			"Glob, provider, type and tag selectors in the -target and -exclude options are not currently supported for remote plans. Use resource addresses instead.",
+		))
+	}
+
 	// Return if there are any errors.
 	if diags.HasErrors() {
 		return nil, diags.Err()
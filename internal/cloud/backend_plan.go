@@ -87,6 +87,14 @@ func (b *Cloud) opPlan(ctx, stopCtx, cancelCtx context.Context, op *backend.Oper
 		))
 	}
 
+	if len(op.TargetSelectors) != 0 || len(op.ExcludeSelectors) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Target selectors are not supported",
+			"Glob, provider, type and tag selectors in the -target and -exclude options are not currently supported for remote plans. Use resource addresses instead.",
+		))
+	}
+
 	if len(op.GenerateConfigOut) > 0 {
 		diags = diags.Append(genconfig.ValidateTargetFile(op.GenerateConfigOut))
 	}
@@ -8,13 +8,16 @@ package command
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/timeline"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -111,6 +114,12 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	opReq, opDiags := c.OperationRequest(ctx, be, view, args, planFile, enc)
 	diags = diags.Append(opDiags)
 
+	var timelineRecorder *timeline.Recorder
+	if args.TimelinePath != "" && opReq != nil {
+		timelineRecorder = timeline.NewRecorder()
+		opReq.Hooks = append(opReq.Hooks, timelineRecorder)
+	}
+
 	// Before we delegate to the backend, we'll print any warning diagnostics
 	// we've accumulated here, since the backend will start fresh with its own
 	// diagnostics.
@@ -122,6 +131,22 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 
 	// Run the operation
 	op, diags := c.RunOperation(ctx, be, opReq)
+	var criticalPath []timeline.Span
+	timelineWritten := false
+	if timelineRecorder != nil && op != nil {
+		// We write the timeline even if the operation failed, because it
+		// can be useful for understanding what happened before the failure.
+		// Failing to write it doesn't change the outcome of the apply, so
+		// any problems are reported separately as warnings and don't
+		// affect the exit status.
+		var timelineDiags tfdiags.Diagnostics
+		criticalPath, timelineDiags = writeTimeline(args.TimelinePath, timelineRecorder, op.State)
+		view.Diagnostics(timelineDiags)
+		timelineWritten = len(timelineDiags) == 0
+		if timelineWritten && (diags.HasErrors() || op.Result != backend.OperationSuccess) {
+			view.Timeline(args.TimelinePath, criticalPath)
+		}
+	}
 	view.Diagnostics(diags)
 	if diags.HasErrors() {
 		return 1
@@ -140,6 +165,10 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		}
 	}
 
+	if timelineWritten {
+		view.Timeline(args.TimelinePath, criticalPath)
+	}
+
 	view.Diagnostics(diags)
 
 	if diags.HasErrors() {
@@ -149,6 +178,46 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	return 0
 }
 
+// writeTimeline writes the spans recorded by the given recorder to the file
+// at the given path, returning the critical path through the apply based on
+// the dependencies recorded in the given state.
+//
+// Problems writing the file are returned as warnings, because the timeline is
+// only a diagnostic aid for an operation that has already completed.
+func writeTimeline(path string, recorder *timeline.Recorder, state *states.State) ([]timeline.Span, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	spans := recorder.Spans()
+	criticalPath := timeline.CriticalPath(spans, state)
+
+	f, err := os.Create(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to write timeline",
+			fmt.Sprintf("Cannot create the timeline file %s: %s.", path, err),
+		))
+		return nil, diags
+	}
+
+	// Closing the file is where some filesystems report a failed write, so
+	// we must check its error too rather than deferring it.
+	err = timeline.WriteChromeTrace(f, spans, criticalPath)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to write timeline",
+			fmt.Sprintf("Cannot write the timeline file %s: %s.", path, err),
+		))
+		return nil, diags
+	}
+
+	return criticalPath, diags
+}
+
 func (c *ApplyCommand) LoadPlanFile(path string, enc encryption.Encryption) (*planfile.WrappedPlanFile, tfdiags.Diagnostics) {
 	var planFile *planfile.WrappedPlanFile
 	var diags tfdiags.Diagnostics
@@ -271,6 +340,8 @@ func (c *ApplyCommand) OperationRequest(
 	opReq.PlanRefresh = applyArgs.Operation.Refresh
 	opReq.Targets = applyArgs.Operation.Targets
 	opReq.Excludes = applyArgs.Operation.Excludes
+	opReq.TargetSelectors = applyArgs.Operation.TargetSelectors
+	opReq.ExcludeSelectors = applyArgs.Operation.ExcludeSelectors
 	opReq.ForceReplace = applyArgs.Operation.ForceReplace
 	opReq.Type = backend.OperationTypeApply
 	opReq.View = view.Operation()
@@ -366,6 +437,11 @@ Options:
                                operation completes successfully but leaves
                                forgotten instances behind.
 
+  -timeline=path               Write the start and finish times of each action
+                               taken during the operation to the given file in
+                               the Chrome trace event format, and summarize
+                               the critical path through the apply.
+
   -var 'foo=bar'               Set a variable in the OpenTofu configuration.
                                This flag can be set multiple times.
 
@@ -8,6 +8,7 @@ package command
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -73,6 +74,112 @@ func TestApply(t *testing.T) {
 		t.Fatal("state should not be nil")
 	}
 }
+func TestApply_timeline(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	t.Chdir(td)
+
+	statePath := testTempFile(t)
+	timelinePath := filepath.Join(td, "timeline.json")
+
+	p := applyFixtureProvider()
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-timeline", timelinePath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if got, want := output.Stdout(), "Timeline written to "+timelinePath; !strings.Contains(got, want) {
+		t.Errorf("output does not contain %q:\n%s", want, got)
+	}
+	if got, want := output.Stdout(), "Critical path"; !strings.Contains(got, want) {
+		t.Errorf("output does not contain %q:\n%s", want, got)
+	}
+
+	raw, err := os.ReadFile(timelinePath)
+	if err != nil {
+		t.Fatalf("failed to read timeline: %s", err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name     string `json:"name"`
+			Category string `json:"cat"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(raw, &trace); err != nil {
+		t.Fatalf("timeline is not valid JSON: %s", err)
+	}
+	categories := map[string]bool{}
+	for _, event := range trace.TraceEvents {
+		if event.Name == "test_instance.foo" {
+			categories[event.Category] = true
+		}
+	}
+	for _, want := range []string{"plan", "apply"} {
+		if !categories[want] {
+			t.Errorf("timeline has no %q event for test_instance.foo", want)
+		}
+	}
+}
+
+func TestApply_timelineWriteFailure(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	t.Chdir(td)
+
+	statePath := testTempFile(t)
+	timelinePath := filepath.Join(td, "missing", "timeline.json")
+
+	p := applyFixtureProvider()
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-timeline", timelinePath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("failing to write the timeline should not fail the apply, got %d\n\n%s", code, output.Stderr())
+	}
+
+	if got, want := output.All(), "Failed to write timeline"; !strings.Contains(got, want) {
+		t.Errorf("output does not contain %q:\n%s", want, got)
+	}
+	if got, notWant := output.Stdout(), "Timeline written to"; strings.Contains(got, notWant) {
+		t.Errorf("output should not contain %q:\n%s", notWant, got)
+	}
+	if !p.ApplyResourceChangeCalled {
+		t.Error("apply should have been called")
+	}
+}
+
 func TestApply_conditionalSensitive(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -34,6 +34,10 @@ type Apply struct {
 	// SuppressForgetErrorsDuringDestroy suppresses the error that occurs when a
 	// destroy operation completes successfully but leaves forgotten instances behind.
 	SuppressForgetErrorsDuringDestroy bool
+
+	// TimelinePath is an optional path to write a timeline of the operation
+	// to, in the Chrome trace event format.
+	TimelinePath string
 }
 
 // ParseApply processes CLI arguments, returning an Apply value, a closer function, and errors.
@@ -51,6 +55,7 @@ func ParseApply(args []string) (*Apply, func(), tfdiags.Diagnostics) {
 	cmdFlags.BoolVar(&apply.AutoApprove, "auto-approve", false, "auto-approve")
 	cmdFlags.BoolVar(&apply.ShowSensitive, "show-sensitive", false, "displays sensitive values")
 	cmdFlags.BoolVar(&apply.SuppressForgetErrorsDuringDestroy, "suppress-forget-errors", false, "suppress errors in destroy mode due to resources being forgotten")
+	cmdFlags.StringVar(&apply.TimelinePath, "timeline", "", "timeline")
 
 	apply.State.addFlags(cmdFlags, stateFlagAll)
 	apply.ViewOptions.AddFlags(cmdFlags, true)
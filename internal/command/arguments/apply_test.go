@@ -60,6 +60,25 @@ func TestParseApply_basicValid(t *testing.T) {
 				},
 			},
 		},
+		"timeline": {
+			[]string{"-timeline=timeline.json"},
+			&Apply{
+				AutoApprove: false,
+				ViewOptions: ViewOptions{
+					InputEnabled: true,
+					ViewType:     ViewHuman,
+				},
+				PlanPath:     "",
+				TimelinePath: "timeline.json",
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
 		"destroy mode": {
 			[]string{"-destroy"},
 			&Apply{
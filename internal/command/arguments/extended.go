@@ -19,6 +19,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/targeting"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -116,6 +117,14 @@ type Operation struct {
 	// than a set of excluded resource addresses and resources dependent on them.
 	Excludes []addrs.Targetable
 
+	// TargetSelectors and ExcludeSelectors are the glob, provider, type and
+	// tag selectors given to -target and -exclude in place of addresses.
+	// These can only be resolved into addresses once configuration and state
+	// are available, at which point the results are added to Targets or
+	// Excludes respectively.
+	TargetSelectors  []targeting.Selector
+	ExcludeSelectors []targeting.Selector
+
 	// ForceReplace addresses cause OpenTofu to force a particular set of
 	// resource instances to generate "replace" actions in any plan where they
 	// would normally have generated "no-op" or "update" actions.
@@ -141,13 +150,24 @@ type Operation struct {
 }
 
 // parseDirectTargetables gets a list of strings passed from directly from the CLI
-// with each representing a targetable object, and returns a list of addrs.Targetable
+// with each representing a targetable object or a selector, and returns a list
+// of addrs.Targetable and a list of targeting.Selector.
 // This is used for parsing the input of -target and -exclude flags
-func parseDirectTargetables(rawTargetables []string, flag string) ([]addrs.Targetable, tfdiags.Diagnostics) {
+func parseDirectTargetables(rawTargetables []string, flag string) ([]addrs.Targetable, []targeting.Selector, tfdiags.Diagnostics) {
 	var targetables []addrs.Targetable
+	var selectors []targeting.Selector
 	var diags tfdiags.Diagnostics
 
 	for _, tr := range rawTargetables {
+		if targeting.IsSelector(tr) {
+			selector, selectorDiags := targeting.ParseSelector(tr)
+			diags = diags.Append(selectorDiags)
+			if !selectorDiags.HasErrors() {
+				selectors = append(selectors, selector)
+			}
+			continue
+		}
+
 		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(tr), "", hcl.Pos{Line: 1, Column: 1})
 		if syntaxDiags.HasErrors() {
 			diags = diags.Append(tfdiags.Sourceless(
@@ -170,20 +190,21 @@ func parseDirectTargetables(rawTargetables []string, flag string) ([]addrs.Targe
 
 		targetables = append(targetables, target.Subject)
 	}
-	return targetables, diags
+	return targetables, selectors, diags
 }
 
 // parseFile gets a filePath and reads the file, which contains a list of targets
-// with each line in the file representating a targeted object, and returns
-// a list of addrs.Targetable. This is used for parsing the input of -target-file
-// and -exclude-file flags
-func parseFileTargetables(filePaths []string, flag string) ([]addrs.Targetable, tfdiags.Diagnostics) {
+// with each line in the file representating a targeted object or a selector,
+// and returns a list of addrs.Targetable and a list of targeting.Selector.
+// This is used for parsing the input of -target-file and -exclude-file flags
+func parseFileTargetables(filePaths []string, flag string) ([]addrs.Targetable, []targeting.Selector, tfdiags.Diagnostics) {
 
 	// If no file passed, no targets
 	if len(filePaths) <= 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 	var targetables []addrs.Targetable
+	var selectors []targeting.Selector
 	var diags tfdiags.Diagnostics
 
 	for _, filePath := range filePaths {
@@ -200,6 +221,14 @@ func parseFileTargetables(filePaths []string, flag string) ([]addrs.Targetable,
 			if isComment(lineBytes) {
 				continue
 			}
+			if line := string(lineBytes); targeting.IsSelector(line) {
+				selector, selectorDiags := targeting.ParseSelector(line)
+				diags = diags.Append(selectorDiags)
+				if !selectorDiags.HasErrors() {
+					selectors = append(selectors, selector)
+				}
+				continue
+			}
 			traversal, syntaxDiags := hclsyntax.ParseTraversalAbs(lineBytes, lineRange.Filename, lineRange.Start)
 			diags = diags.Append(syntaxDiags)
 			if syntaxDiags.HasErrors() {
@@ -214,15 +243,25 @@ func parseFileTargetables(filePaths []string, flag string) ([]addrs.Targetable,
 		}
 
 	}
-	return targetables, diags
+	return targetables, selectors, diags
 }
 
 func isComment(b []byte) bool {
 	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("#"))
 }
 
-func parseRawTargetsAndExcludes(targetsDirect, excludesDirect []string, targetFiles, excludeFiles []string) ([]addrs.Targetable, []addrs.Targetable, tfdiags.Diagnostics) {
-	var allParsedTargets, allParsedExcludes, parsedTargets []addrs.Targetable
+// targetsAndExcludes is the result of parseRawTargetsAndExcludes.
+type targetsAndExcludes struct {
+	Targets          []addrs.Targetable
+	Excludes         []addrs.Targetable
+	TargetSelectors  []targeting.Selector
+	ExcludeSelectors []targeting.Selector
+}
+
+func parseRawTargetsAndExcludes(targetsDirect, excludesDirect []string, targetFiles, excludeFiles []string) (targetsAndExcludes, tfdiags.Diagnostics) {
+	var ret targetsAndExcludes
+	var parsedTargets []addrs.Targetable
+	var parsedSelectors []targeting.Selector
 	var parseDiags, diags tfdiags.Diagnostics
 
 	// Cannot exclude and target in same command
@@ -232,24 +271,28 @@ func parseRawTargetsAndExcludes(targetsDirect, excludesDirect []string, targetFi
 			"Invalid combination of arguments",
 			"The target and exclude planning options are mutually-exclusive. Each plan must use either only the target options or only the exclude options.",
 		))
-		return allParsedTargets, allParsedExcludes, diags
+		return ret, diags
 	}
 
-	parsedTargets, parseDiags = parseDirectTargetables(targetsDirect, "target")
+	parsedTargets, parsedSelectors, parseDiags = parseDirectTargetables(targetsDirect, "target")
 	diags = diags.Append(parseDiags)
-	allParsedTargets = append(allParsedTargets, parsedTargets...)
-	parsedTargets, parseDiags = parseFileTargetables(targetFiles, "target")
+	ret.Targets = append(ret.Targets, parsedTargets...)
+	ret.TargetSelectors = append(ret.TargetSelectors, parsedSelectors...)
+	parsedTargets, parsedSelectors, parseDiags = parseFileTargetables(targetFiles, "target")
 	diags = diags.Append(parseDiags)
-	allParsedTargets = append(allParsedTargets, parsedTargets...)
+	ret.Targets = append(ret.Targets, parsedTargets...)
+	ret.TargetSelectors = append(ret.TargetSelectors, parsedSelectors...)
 
-	parsedTargets, parseDiags = parseDirectTargetables(excludesDirect, "exclude")
+	parsedTargets, parsedSelectors, parseDiags = parseDirectTargetables(excludesDirect, "exclude")
 	diags = diags.Append(parseDiags)
-	allParsedExcludes = append(allParsedExcludes, parsedTargets...)
-	parsedTargets, parseDiags = parseFileTargetables(excludeFiles, "exclude")
+	ret.Excludes = append(ret.Excludes, parsedTargets...)
+	ret.ExcludeSelectors = append(ret.ExcludeSelectors, parsedSelectors...)
+	parsedTargets, parsedSelectors, parseDiags = parseFileTargetables(excludeFiles, "exclude")
 	diags = diags.Append(parseDiags)
-	allParsedExcludes = append(allParsedExcludes, parsedTargets...)
+	ret.Excludes = append(ret.Excludes, parsedTargets...)
+	ret.ExcludeSelectors = append(ret.ExcludeSelectors, parsedSelectors...)
 
-	return allParsedTargets, allParsedExcludes, diags
+	return ret, diags
 }
 
 // Parse must be called on Operation after initial flag parse. This processes
@@ -258,9 +301,10 @@ func parseRawTargetsAndExcludes(targetsDirect, excludesDirect []string, targetFi
 func (o *Operation) Parse() tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
-	var parseDiags tfdiags.Diagnostics
-	o.Targets, o.Excludes, parseDiags = parseRawTargetsAndExcludes(o.targetsRaw, o.excludesRaw, o.targetsFilesRaw, o.excludesFilesRaw)
+	parsed, parseDiags := parseRawTargetsAndExcludes(o.targetsRaw, o.excludesRaw, o.targetsFilesRaw, o.excludesFilesRaw)
 	diags = diags.Append(parseDiags)
+	o.Targets, o.Excludes = parsed.Targets, parsed.Excludes
+	o.TargetSelectors, o.ExcludeSelectors = parsed.TargetSelectors, parsed.ExcludeSelectors
 
 	for _, raw := range o.forceReplaceRaw {
 		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(raw), "", hcl.Pos{Line: 1, Column: 1})
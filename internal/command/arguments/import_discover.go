@@ -0,0 +1,150 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/flags"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// DefaultImportDiscoverOut is the file that the import discover command
+// writes its import blocks and generated configuration into when the -out
+// option isn't set.
+const DefaultImportDiscoverOut = "discovered.tf"
+
+// ImportDiscover represents the command-line arguments for the import
+// discover command.
+type ImportDiscover struct {
+	// ResourceType is the managed resource type whose existing objects
+	// should be listed.
+	ResourceType string
+	// Provider is the provider configuration in the root module to list the
+	// objects with. LocalName is empty if the provider should be implied from
+	// the resource type.
+	Provider addrs.LocalProviderConfig
+	// Filters are the filter arguments for the provider, keyed by name.
+	Filters map[string]string
+	// Limit is the maximum number of objects to list, or zero for no limit.
+	Limit int64
+	// Out is the path of the new file to write the import blocks and
+	// generated configuration into, or "-" to print them instead.
+	Out string
+
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+	// Vars holds and provides information for the flags related to variables that a user can give into the process
+	Vars *Vars
+	// State is used for the state related flags
+	State *State
+}
+
+// ParseImportDiscover processes CLI arguments, returning an ImportDiscover value, a closer function, and errors.
+// If errors are encountered, an ImportDiscover value is still returned representing
+// the best effort interpretation of the arguments.
+func ParseImportDiscover(args []string) (*ImportDiscover, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	discover := &ImportDiscover{
+		Filters: make(map[string]string),
+		Vars:    &Vars{},
+		State:   &State{},
+	}
+
+	var provider string
+	var filters flags.FlagStringSlice
+	cmdFlags := extendedFlagSet("import discover", nil, discover.Vars)
+	cmdFlags.StringVar(&provider, "provider", "", "provider")
+	cmdFlags.Var(&filters, "filter", "filter")
+	cmdFlags.Int64Var(&discover.Limit, "limit", 0, "limit")
+	cmdFlags.StringVar(&discover.Out, "out", DefaultImportDiscoverOut, "out")
+	discover.State.addFlags(cmdFlags, stateFlagLock)
+	discover.State.AddStateInFlag(cmdFlags, DefaultStateFilename)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	// we only parse but do not register the views flags since this command does not need it
+	closer, moreDiags := discover.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return discover, closer, diags
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid number of arguments",
+			"The import discover command expects exactly one argument: the resource type to discover.",
+		))
+		return discover, closer, diags
+	}
+	discover.ResourceType = args[0]
+
+	if provider != "" {
+		localName, alias, _ := strings.Cut(provider, ".")
+		if !hclsyntax.ValidIdentifier(localName) || (alias != "" && !hclsyntax.ValidIdentifier(alias)) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider configuration",
+				fmt.Sprintf("The -provider option requires a provider configuration address such as \"aws\" or \"aws.west\", but got %q.", provider),
+			))
+		}
+		discover.Provider = addrs.LocalProviderConfig{
+			LocalName: localName,
+			Alias:     alias,
+		}
+	}
+
+	for _, filter := range filters {
+		name, value, ok := strings.Cut(filter, "=")
+		if !ok || name == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid filter",
+				fmt.Sprintf("The -filter option requires an argument of the form name=value, but got %q.", filter),
+			))
+			continue
+		}
+		if _, exists := discover.Filters[name]; exists {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Duplicate filter",
+				fmt.Sprintf("The filter %q was set more than once.", name),
+			))
+			continue
+		}
+		discover.Filters[name] = value
+	}
+
+	if discover.Limit < 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid limit",
+			fmt.Sprintf("The -limit option must be zero or a positive number, but got %d.", discover.Limit),
+		))
+	}
+
+	if discover.Out == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output file",
+			`The -out option requires a file path, or "-" to print the import blocks instead of writing them to a file.`,
+		))
+	}
+
+	return discover, closer, diags
+}
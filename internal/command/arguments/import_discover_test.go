@@ -0,0 +1,160 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestParseImportDiscover_basicValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want *ImportDiscover
+	}{
+		"defaults": {
+			args: []string{"test_instance"},
+			want: importDiscoverArgsWithDefaults(nil),
+		},
+		"provider flag": {
+			args: []string{"-provider=test", "test_instance"},
+			want: importDiscoverArgsWithDefaults(func(discover *ImportDiscover) {
+				discover.Provider = addrs.LocalProviderConfig{LocalName: "test"}
+			}),
+		},
+		"provider flag with alias": {
+			args: []string{"-provider=test.west", "test_instance"},
+			want: importDiscoverArgsWithDefaults(func(discover *ImportDiscover) {
+				discover.Provider = addrs.LocalProviderConfig{LocalName: "test", Alias: "west"}
+			}),
+		},
+		"filter flags": {
+			args: []string{"-filter=name=web", "-filter", "tags={env=\"prod\"}", "test_instance"},
+			want: importDiscoverArgsWithDefaults(func(discover *ImportDiscover) {
+				discover.Filters = map[string]string{
+					"name": "web",
+					"tags": `{env="prod"}`,
+				}
+			}),
+		},
+		"limit flag": {
+			args: []string{"-limit=10", "test_instance"},
+			want: importDiscoverArgsWithDefaults(func(discover *ImportDiscover) {
+				discover.Limit = 10
+			}),
+		},
+		"out flag": {
+			args: []string{"-out=-", "test_instance"},
+			want: importDiscoverArgsWithDefaults(func(discover *ImportDiscover) {
+				discover.Out = "-"
+			}),
+		},
+		"lock flags": {
+			args: []string{"-lock=false", "-lock-timeout=10s", "test_instance"},
+			want: importDiscoverArgsWithDefaults(func(discover *ImportDiscover) {
+				discover.State.Lock = false
+				discover.State.LockTimeout = 10 * time.Second
+			}),
+		},
+	}
+
+	cmpOpts := cmpopts.IgnoreUnexported(Vars{}, ViewOptions{})
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, closer, diags := ParseImportDiscover(tc.args)
+			defer closer()
+
+			if len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseImportDiscover_invalid(t *testing.T) {
+	testCases := map[string]struct {
+		args     []string
+		wantDiag string
+	}{
+		"unknown flag": {
+			args:     []string{"-boop", "test_instance"},
+			wantDiag: "Failed to parse command-line flags",
+		},
+		"no arguments": {
+			args:     nil,
+			wantDiag: "Invalid number of arguments",
+		},
+		"too many arguments": {
+			args:     []string{"test_instance", "test_other"},
+			wantDiag: "Invalid number of arguments",
+		},
+		"invalid provider": {
+			args:     []string{"-provider=test.west.east", "test_instance"},
+			wantDiag: "Invalid provider configuration",
+		},
+		"filter without value": {
+			args:     []string{"-filter=name", "test_instance"},
+			wantDiag: "Invalid filter",
+		},
+		"duplicate filter": {
+			args:     []string{"-filter=name=a", "-filter=name=b", "test_instance"},
+			wantDiag: "Duplicate filter",
+		},
+		"negative limit": {
+			args:     []string{"-limit=-1", "test_instance"},
+			wantDiag: "Invalid limit",
+		},
+		"empty out": {
+			args:     []string{"-out=", "test_instance"},
+			wantDiag: "Invalid output file",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, closer, diags := ParseImportDiscover(tc.args)
+			defer closer()
+
+			if !diags.HasErrors() {
+				t.Fatal("expected errors, got none")
+			}
+			if got := diags[0].Description().Summary; got != tc.wantDiag {
+				t.Errorf("wrong diagnostic\ngot:  %s\nwant: %s", got, tc.wantDiag)
+			}
+		})
+	}
+}
+
+func importDiscoverArgsWithDefaults(mutate func(discover *ImportDiscover)) *ImportDiscover {
+	ret := &ImportDiscover{
+		ResourceType: "test_instance",
+		Filters:      map[string]string{},
+		Out:          DefaultImportDiscoverOut,
+		ViewOptions: ViewOptions{
+			ViewType:     ViewHuman,
+			InputEnabled: false,
+		},
+		Vars: &Vars{},
+		State: &State{
+			Lock: true,
+			// Because the state flag is registered with a different default value
+			StatePath: DefaultStateFilename,
+		},
+	}
+	if mutate != nil {
+		mutate(ret)
+	}
+	return ret
+}
@@ -23,6 +23,10 @@ type Plan struct {
 	// OutPath contains an optional path to store the plan file
 	OutPath string
 
+	// EmbedSchemas requests that the plan file saved at OutPath include the
+	// provider schemas needed to show the plan without the providers.
+	EmbedSchemas bool
+
 	// GenerateConfigPath tells OpenTofu that config should be generated for
 	// unmatched import target paths and which path the generated file should
 	// be written to.
@@ -50,6 +54,7 @@ func ParsePlan(args []string) (*Plan, func(), tfdiags.Diagnostics) {
 	plan.State.addFlags(cmdFlags, stateFlagAll)
 	cmdFlags.BoolVar(&plan.DetailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
 	cmdFlags.StringVar(&plan.OutPath, "out", "", "out")
+	cmdFlags.BoolVar(&plan.EmbedSchemas, "embed-schemas", false, "embed-schemas")
 	cmdFlags.StringVar(&plan.GenerateConfigPath, "generate-config-out", "", "generate-config-out")
 	cmdFlags.BoolVar(&plan.ShowSensitive, "show-sensitive", false, "displays sensitive values")
 
@@ -73,6 +78,14 @@ func ParsePlan(args []string) (*Plan, func(), tfdiags.Diagnostics) {
 		))
 	}
 
+	if plan.EmbedSchemas && plan.OutPath == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid -embed-schemas option",
+			"The -embed-schemas option can only be used together with -out, to embed the provider schemas in the saved plan file.",
+		))
+	}
+
 	diags = diags.Append(plan.Operation.Parse())
 	closer, moreDiags := plan.ViewOptions.Parse()
 	diags = diags.Append(moreDiags)
@@ -43,16 +43,17 @@ func TestParsePlan_basicValid(t *testing.T) {
 			},
 		},
 		"setting all options": {
-			[]string{"-destroy", "-detailed-exitcode", "-input=false", "-out=saved.tfplan"},
+			[]string{"-destroy", "-detailed-exitcode", "-input=false", "-out=saved.tfplan", "-embed-schemas"},
 			&Plan{
 				DetailedExitCode: true,
 				ViewOptions: ViewOptions{
 					InputEnabled: false,
 					ViewType:     ViewHuman,
 				},
-				OutPath: "saved.tfplan",
-				State:   &State{Lock: true},
-				Vars:    &Vars{},
+				OutPath:      "saved.tfplan",
+				EmbedSchemas: true,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -121,6 +122,16 @@ func TestParsePlan_tooManyArguments(t *testing.T) {
 	}
 }
 
+func TestParsePlan_embedSchemasWithoutOut(t *testing.T) {
+	_, _, diags := ParsePlan([]string{"-embed-schemas"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "can only be used together with -out"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestParsePlan_targets(t *testing.T) {
 	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
 	boop, _ := addrs.ParseTargetStr("module.boop")
@@ -178,6 +189,65 @@ func TestParsePlan_targets(t *testing.T) {
 	}
 }
 
+func TestParsePlan_targetSelectors(t *testing.T) {
+	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
+	testCases := map[string]struct {
+		args          []string
+		wantTargets   []addrs.Targetable
+		wantSelectors []string
+		wantErr       string
+	}{
+		"glob": {
+			args:          []string{"-target=module.app[*].aws_*"},
+			wantSelectors: []string{"module.app[*].aws_*"},
+		},
+		"provider and type alongside an address": {
+			args:          []string{"-target=provider=hashicorp/aws", "-target=foo_bar.baz", "-target=type=data.aws_ami"},
+			wantTargets:   []addrs.Targetable{foobarbaz.Subject},
+			wantSelectors: []string{"provider=hashicorp/aws", "type=data.aws_ami"},
+		},
+		"tag": {
+			args:          []string{"-target=tag=Team=payments"},
+			wantSelectors: []string{"tag=Team=payments"},
+		},
+		"invalid provider": {
+			args:    []string{"-target=provider=not/a/valid/provider"},
+			wantErr: `Invalid target selector "provider=not/a/valid/provider"`,
+		},
+		"invalid glob": {
+			args:    []string{"-target=aws_*..foo"},
+			wantErr: `Invalid target selector "aws_*..foo"`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, _, diags := ParsePlan(tc.args)
+			if tc.wantErr == "" && len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			} else if tc.wantErr != "" {
+				if len(diags) == 0 {
+					t.Fatalf("expected diags but got none")
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+				return
+			}
+
+			if !cmp.Equal(got.Operation.Targets, tc.wantTargets) {
+				t.Fatalf("unexpected targets\n%s", cmp.Diff(got.Operation.Targets, tc.wantTargets))
+			}
+			var gotSelectors []string
+			for _, selector := range got.Operation.TargetSelectors {
+				gotSelectors = append(gotSelectors, selector.String())
+			}
+			if !cmp.Equal(gotSelectors, tc.wantSelectors) {
+				t.Fatalf("unexpected selectors\n%s", cmp.Diff(gotSelectors, tc.wantSelectors))
+			}
+		})
+	}
+}
+
 func TestParsePlan_targetFile(t *testing.T) {
 	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
 	boop, _ := addrs.ParseTargetStr("module.boop")
@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// DefaultRefactorSuggestOut is the file that the refactor suggest command
+// writes its moved blocks into when the -out option isn't set.
+const DefaultRefactorSuggestOut = "moved.tf"
+
+// DefaultRefactorSuggestMinConfidence is the lowest confidence score of a
+// suggested move that the refactor suggest command reports by default.
+const DefaultRefactorSuggestMinConfidence = 0.5
+
+// RefactorSuggest represents the command-line arguments for the refactor
+// suggest command.
+type RefactorSuggest struct {
+	// Out is the path of the new file to write the suggested moved blocks
+	// into, or "-" to print them instead.
+	Out string
+	// MinConfidence is the lowest confidence score, between zero and one,
+	// of the moves to suggest.
+	MinConfidence float64
+
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+	// Vars holds and provides information for the flags related to variables that a user can give into the process
+	Vars *Vars
+	// State is used for the state related flags
+	State *State
+}
+
+// ParseRefactorSuggest processes CLI arguments, returning a RefactorSuggest value, a closer function, and errors.
+// If errors are encountered, a RefactorSuggest value is still returned representing
+// the best effort interpretation of the arguments.
+func ParseRefactorSuggest(args []string) (*RefactorSuggest, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	suggest := &RefactorSuggest{
+		Vars:  &Vars{},
+		State: &State{},
+	}
+
+	cmdFlags := extendedFlagSet("refactor suggest", nil, suggest.Vars)
+	cmdFlags.StringVar(&suggest.Out, "out", DefaultRefactorSuggestOut, "out")
+	cmdFlags.Float64Var(&suggest.MinConfidence, "min-confidence", DefaultRefactorSuggestMinConfidence, "min-confidence")
+	suggest.State.addFlags(cmdFlags, stateFlagLock)
+	suggest.State.AddStateInFlag(cmdFlags, DefaultStateFilename)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	// we only parse but do not register the views flags since this command does not need it
+	closer, moreDiags := suggest.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return suggest, closer, diags
+	}
+
+	if len(cmdFlags.Args()) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unexpected argument",
+			"Too many command line arguments. Did you mean to use -chdir?",
+		))
+	}
+
+	if suggest.Out == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output file",
+			`The -out option requires a file path, or "-" to print the moved blocks instead of writing them to a file.`,
+		))
+	}
+
+	if suggest.MinConfidence < 0 || suggest.MinConfidence > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid minimum confidence",
+			fmt.Sprintf("The -min-confidence option must be a number between 0 and 1, but got %g.", suggest.MinConfidence),
+		))
+	}
+
+	return suggest, closer, diags
+}
@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseRefactorSuggest_basicValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want *RefactorSuggest
+	}{
+		"defaults": {
+			args: nil,
+			want: refactorSuggestArgsWithDefaults(nil),
+		},
+		"out flag": {
+			args: []string{"-out=refactor.tf"},
+			want: refactorSuggestArgsWithDefaults(func(suggest *RefactorSuggest) {
+				suggest.Out = "refactor.tf"
+			}),
+		},
+		"out to stdout": {
+			args: []string{"-out=-"},
+			want: refactorSuggestArgsWithDefaults(func(suggest *RefactorSuggest) {
+				suggest.Out = "-"
+			}),
+		},
+		"min-confidence flag": {
+			args: []string{"-min-confidence=0.8"},
+			want: refactorSuggestArgsWithDefaults(func(suggest *RefactorSuggest) {
+				suggest.MinConfidence = 0.8
+			}),
+		},
+		"custom state path": {
+			args: []string{"-state=/path/to/state.tfstate"},
+			want: refactorSuggestArgsWithDefaults(func(suggest *RefactorSuggest) {
+				suggest.State.StatePath = "/path/to/state.tfstate"
+			}),
+		},
+		"lock flags": {
+			args: []string{"-lock=false", "-lock-timeout=10s"},
+			want: refactorSuggestArgsWithDefaults(func(suggest *RefactorSuggest) {
+				suggest.State.Lock = false
+				suggest.State.LockTimeout = 10 * time.Second
+			}),
+		},
+		"vars": {
+			args: []string{"-var=key=value", "-var-file=test.tfvars"},
+			want: refactorSuggestArgsWithDefaults(func(suggest *RefactorSuggest) {
+				// Vars would be updated, but we ignore it in cmp
+			}),
+		},
+	}
+
+	cmpOpts := cmpopts.IgnoreUnexported(Vars{}, ViewOptions{})
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, closer, diags := ParseRefactorSuggest(tc.args)
+			defer closer()
+
+			if len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseRefactorSuggest_invalid(t *testing.T) {
+	testCases := map[string]struct {
+		args     []string
+		wantDiag string
+	}{
+		"unknown flag": {
+			args:     []string{"-boop"},
+			wantDiag: "Failed to parse command-line flags",
+		},
+		"too many arguments": {
+			args:     []string{"foo"},
+			wantDiag: "Unexpected argument",
+		},
+		"empty out": {
+			args:     []string{"-out="},
+			wantDiag: "Invalid output file",
+		},
+		"min-confidence out of range": {
+			args:     []string{"-min-confidence=1.5"},
+			wantDiag: "Invalid minimum confidence",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, closer, diags := ParseRefactorSuggest(tc.args)
+			defer closer()
+
+			if !diags.HasErrors() {
+				t.Fatal("expected errors, got none")
+			}
+			if got := diags[0].Description().Summary; got != tc.wantDiag {
+				t.Errorf("wrong diagnostic\ngot:  %s\nwant: %s", got, tc.wantDiag)
+			}
+		})
+	}
+}
+
+func refactorSuggestArgsWithDefaults(mutate func(suggest *RefactorSuggest)) *RefactorSuggest {
+	ret := &RefactorSuggest{
+		Out:           DefaultRefactorSuggestOut,
+		MinConfidence: DefaultRefactorSuggestMinConfidence,
+		ViewOptions: ViewOptions{
+			ViewType:     ViewHuman,
+			InputEnabled: false,
+		},
+		Vars: &Vars{},
+		State: &State{
+			Lock: true,
+			// Because the state flag is registered with a different default value
+			StatePath: DefaultStateFilename,
+		},
+	}
+	if mutate != nil {
+		mutate(ret)
+	}
+	return ret
+}
@@ -33,6 +33,38 @@ type Test struct {
 	// human-readable format or JSON for each run step depending on the
 	// ViewType.
 	Verbose bool
+
+	// Parallelism is the number of test files that may be executed
+	// concurrently. Each test file has its own isolated state, so this only
+	// affects how long the whole suite takes to run.
+	Parallelism int
+
+	// UpdateSnapshots tells the test command to write the plan snapshots of
+	// any run blocks that set expect_plan_snapshot, instead of comparing the
+	// plans against them.
+	UpdateSnapshots bool
+
+	// JUnitXMLPath, if set, is the path of a file to write a JUnit XML report
+	// of the test results into once testing has completed.
+	JUnitXMLPath string
+
+	// TAPPath, if set, is the path of a file to write a report of the test
+	// results into using the Test Anything Protocol.
+	TAPPath string
+
+	// Coverage tells the test command to print a summary of which parts of
+	// the configuration under test were exercised by the run blocks.
+	Coverage bool
+
+	// CoverageJSONPath and CoverageLCOVPath, if set, are the paths of files
+	// to write the coverage report into, in JSON and LCOV formats. Setting
+	// either of them enables coverage collection even if Coverage is false.
+	CoverageJSONPath string
+	CoverageLCOVPath string
+
+	// CleanupLeftovers tells the test command to destroy the infrastructure
+	// left behind by previous executions, instead of executing the tests.
+	CleanupLeftovers bool
 }
 
 func ParseTest(args []string) (*Test, func(), tfdiags.Diagnostics) {
@@ -46,6 +78,14 @@ func ParseTest(args []string) (*Test, func(), tfdiags.Diagnostics) {
 	cmdFlags.Var((*flags.FlagStringSlice)(&test.Filter), "filter", "filter")
 	cmdFlags.StringVar(&test.TestDirectory, "test-directory", configs.DefaultTestDirectory, "test-directory")
 	cmdFlags.BoolVar(&test.Verbose, "verbose", false, "verbose")
+	cmdFlags.IntVar(&test.Parallelism, "parallelism", 1, "parallelism")
+	cmdFlags.BoolVar(&test.UpdateSnapshots, "update-snapshots", false, "update-snapshots")
+	cmdFlags.StringVar(&test.JUnitXMLPath, "junit-xml", "", "junit-xml")
+	cmdFlags.StringVar(&test.TAPPath, "tap", "", "tap")
+	cmdFlags.BoolVar(&test.Coverage, "coverage", false, "coverage")
+	cmdFlags.StringVar(&test.CoverageJSONPath, "coverage-json", "", "coverage-json")
+	cmdFlags.StringVar(&test.CoverageLCOVPath, "coverage-lcov", "", "coverage-lcov")
+	cmdFlags.BoolVar(&test.CleanupLeftovers, "cleanup-leftovers", false, "cleanup-leftovers")
 
 	test.ViewOptions.AddFlags(cmdFlags, false)
 
@@ -56,6 +96,13 @@ func ParseTest(args []string) (*Test, func(), tfdiags.Diagnostics) {
 			err.Error()))
 	}
 
+	if test.Parallelism < 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid parallelism",
+			"The -parallelism option must be a positive number of test files to execute concurrently."))
+	}
+
 	closer, moreDiags := test.ViewOptions.Parse()
 	diags = diags.Append(moreDiags)
 
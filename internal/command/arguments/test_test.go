@@ -81,6 +81,7 @@ func TestParseTest(t *testing.T) {
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
 				Vars:          &Vars{},
+				Parallelism:   1,
 			},
 			wantDiags: nil,
 		},
@@ -91,6 +92,7 @@ func TestParseTest(t *testing.T) {
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
 				Vars:          &Vars{},
+				Parallelism:   1,
 			},
 			wantDiags: nil,
 		},
@@ -101,6 +103,7 @@ func TestParseTest(t *testing.T) {
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewJSON},
 				Vars:          &Vars{},
+				Parallelism:   1,
 			},
 			wantDiags: nil,
 		},
@@ -111,6 +114,7 @@ func TestParseTest(t *testing.T) {
 				TestDirectory: "other",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
 				Vars:          &Vars{},
+				Parallelism:   1,
 			},
 			wantDiags: nil,
 		},
@@ -122,6 +126,81 @@ func TestParseTest(t *testing.T) {
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
 				Verbose:       true,
 				Vars:          &Vars{},
+				Parallelism:   1,
+			},
+		},
+		"reports": {
+			args: []string{"-junit-xml=results.xml", "-tap=results.tap"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Vars:          &Vars{},
+				Parallelism:   1,
+				JUnitXMLPath:  "results.xml",
+				TAPPath:       "results.tap",
+			},
+		},
+		"parallelism": {
+			args: []string{"-parallelism=4"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Vars:          &Vars{},
+				Parallelism:   4,
+			},
+		},
+		"update-snapshots": {
+			args: []string{"-update-snapshots"},
+			want: &Test{
+				Filter:          nil,
+				TestDirectory:   "tests",
+				ViewOptions:     ViewOptions{ViewType: ViewHuman},
+				Vars:            &Vars{},
+				Parallelism:     1,
+				UpdateSnapshots: true,
+			},
+		},
+		"cleanup-leftovers": {
+			args: []string{"-cleanup-leftovers"},
+			want: &Test{
+				Filter:           nil,
+				TestDirectory:    "tests",
+				ViewOptions:      ViewOptions{ViewType: ViewHuman},
+				Vars:             &Vars{},
+				Parallelism:      1,
+				CleanupLeftovers: true,
+			},
+		},
+		"coverage": {
+			args: []string{"-coverage", "-coverage-json=coverage.json", "-coverage-lcov=coverage.info"},
+			want: &Test{
+				Filter:           nil,
+				TestDirectory:    "tests",
+				ViewOptions:      ViewOptions{ViewType: ViewHuman},
+				Vars:             &Vars{},
+				Parallelism:      1,
+				Coverage:         true,
+				CoverageJSONPath: "coverage.json",
+				CoverageLCOVPath: "coverage.info",
+			},
+		},
+		"invalid parallelism": {
+			args: []string{"-parallelism=0"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Vars:          &Vars{},
+				Parallelism:   0,
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid parallelism",
+					"The -parallelism option must be a positive number of test files to execute concurrently.",
+				),
 			},
 		},
 		"unknown flag": {
@@ -131,6 +210,7 @@ func TestParseTest(t *testing.T) {
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
 				Vars:          &Vars{},
+				Parallelism:   1,
 			},
 			wantDiags: tfdiags.Diagnostics{
 				tfdiags.Sourceless(
@@ -0,0 +1,271 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/genconfig"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// ImportDiscoverCommand is a Command implementation that asks a provider to
+// list the existing remote objects of a resource type and generates import
+// blocks and configuration for the ones that aren't managed yet.
+type ImportDiscoverCommand struct {
+	Meta
+}
+
+func (c *ImportDiscoverCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+	// Because the legacy UI was using println to show diagnostics and the new view is using, by default, print,
+	// in order to keep functional parity, we setup the view to add a new line after each diagnostic.
+	c.View.DiagsWithNewline()
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseImportDiscover(rawArgs)
+	defer closer()
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewImportDiscover(c.View)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return cli.RunResultHelp
+	}
+	c.Meta.stateArgs = *args.State
+	c.Meta.variableArgs = args.Vars.All()
+
+	toStdout := args.Out == "-"
+	if !toStdout {
+		// We check this before doing any work so that we don't make the
+		// user wait for the provider only to find that we can't write the
+		// result.
+		if targetDiags := genconfig.ValidateTargetFile(args.Out); targetDiags.HasErrors() {
+			view.Diagnostics(diags.Append(targetDiags))
+			return 1
+		}
+	}
+
+	configPath := c.WorkingDir.NormalizePath(c.WorkingDir.RootModuleDir())
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plugins loading error",
+			fmt.Sprintf("Error loading plugin path: %s", err),
+		)))
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(ctx, configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(ctx, configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, &BackendOpts{
+		Config: backendConfig,
+		View:   view.Backend(),
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// We require a local backend
+	local, ok := b.(backend.Local)
+	if !ok {
+		view.Diagnostics(diags) // in case of any warnings in here
+		view.ErrorUnsupportedLocalOp()
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// Build the operation
+	opReq := c.Operation(ctx, b, view.Backend(), enc)
+	opReq.ConfigDir = configPath
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	{
+		// Setup required variables/call for operation (usually done in Meta.RunOperation)
+		var moreDiags, callDiags tfdiags.Diagnostics
+		opReq.Variables, moreDiags = c.collectVariableValues()
+		opReq.RootCall, callDiags = c.rootModuleCall(ctx, opReq.ConfigDir)
+		diags = diags.Append(moreDiags).Append(callDiags)
+		if moreDiags.HasErrors() || callDiags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
+	// Get the context
+	stopCtx, cancel := c.InterruptibleContext(ctx)
+	defer cancel()
+	lr, _, ctxDiags := local.LocalRun(ctx, stopCtx, opReq)
+	diags = diags.Append(ctxDiags)
+	if ctxDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Successfully creating the context can result in a lock, so ensure we release it
+	defer func() {
+		diags := opReq.StateLocker.Unlock()
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+		}
+	}()
+
+	result, moreDiags := lr.Core.DiscoverResources(ctx, lr.Config, lr.InputState, &tofu.DiscoverOpts{
+		ResourceType:   args.ResourceType,
+		ProviderConfig: args.Provider,
+		Filters:        args.Filters,
+		Limit:          args.Limit,
+		SetVariables:   lr.PlanOpts.SetVariables,
+	})
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	view.Diagnostics(diags)
+
+	if len(result.Resources) == 0 {
+		if !toStdout {
+			view.NothingDiscovered(result.Managed)
+		}
+		return 0
+	}
+
+	src := formatDiscoveredResources(result.Resources)
+	if toStdout {
+		view.Output(src)
+		return 0
+	}
+
+	view.Discovered(result.Resources, result.Managed)
+	if err := os.WriteFile(args.Out, []byte(src), 0644); err != nil {
+		view.Diagnostics(tfdiags.Diagnostics{}.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write generated configuration",
+			fmt.Sprintf("OpenTofu could not write the import blocks and generated configuration to %s: %s.", args.Out, err),
+		)))
+		return 1
+	}
+	view.WroteFile(args.Out)
+
+	return 0
+}
+
+// formatDiscoveredResources renders an import block for each of the given
+// resources, followed by a resource block with the generated configuration
+// when the provider returned the full object.
+func formatDiscoveredResources(resources []*tofu.DiscoveredResource) string {
+	var buf strings.Builder
+	buf.WriteString("# __generated__ by OpenTofu\n")
+	buf.WriteString("# Please review these import blocks and resources and move them into your main configuration files.\n")
+	for _, resource := range resources {
+		fmt.Fprintf(&buf, "\n# __generated__ by OpenTofu from %q\n", resource.DisplayName)
+		buf.WriteString(genconfig.GenerateImportBlock(resource.Addr, resource.Identity))
+		buf.WriteString("\n")
+		if resource.Config != "" {
+			buf.WriteString("\n")
+			buf.WriteString(genconfig.WrapResourceContents(resource.Addr, resource.Config))
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
+
+func (c *ImportDiscoverCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] import discover [options] TYPE
+
+  Asks the provider to list the existing remote objects of the given managed
+  resource type, and generates an import block and resource configuration
+  for each object that isn't already managed in the current state.
+
+  Only providers that support listing objects of the resource type can be
+  used with this command. The provider is configured as it would be for a
+  plan, using the provider configuration in the root module.
+
+  The result is written into a new file, which must not already exist. Review
+  it and run "tofu plan" to check what will be imported. Objects without a
+  generated resource block can be completed with "tofu plan
+  -generate-config-out=...".
+
+Options:
+
+  -filter=name=value     Only list the objects that match the given filter.
+                         The available filters depend on the resource type.
+                         Use this option more than once to set more than one
+                         filter.
+
+  -limit=n               List at most the given number of objects.
+
+  -provider=aws.west     Use the given provider configuration in the root
+                         module instead of the default one for the resource
+                         type.
+
+  -out=discovered.tf     Write the import blocks and generated configuration
+                         into the given new file. Set to "-" to print them
+                         instead. Defaults to "discovered.tf".
+
+  -lock=false            Don't hold a state lock during the operation. This
+                         is dangerous if others might concurrently run
+                         commands against the same workspace.
+
+  -lock-timeout=0s       Duration to retry a state lock.
+
+  -state=path            Legacy option for the local backend only. See the
+                         local backend's documentation for more information.
+
+  -var 'foo=bar'         Set a value for one of the input variables in the
+                         root module of the configuration. Use this option
+                         more than once to set more than one variable.
+
+  -var-file=filename     Load variable values from the given file, in
+                         addition to the default files terraform.tfvars and
+                         *.auto.tfvars. Use this option more than once to
+                         include more than one variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ImportDiscoverCommand) Synopsis() string {
+	return "Generate import blocks for existing remote objects"
+}
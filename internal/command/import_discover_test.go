@@ -0,0 +1,238 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// importDiscoverFixtureState returns a state in which test_instance.web
+// already manages the object with id "i-1".
+func importDiscoverFixtureState() *states.State {
+	return states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "web",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"i-1","ami":"bar"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+}
+
+// importDiscoverFixtureProvider returns a mock provider that can list
+// test_instance objects, which always lists the object managed in
+// importDiscoverFixtureState and one other object.
+func importDiscoverFixtureProvider() *tofu.MockProvider {
+	p := applyFixtureProvider()
+	resourceSchema := p.GetProviderSchemaResponse.ResourceTypes["test_instance"]
+	resourceSchema.IdentitySchema = &configschema.Object{
+		Attributes: map[string]*configschema.Attribute{
+			"id": {Type: cty.String, Required: true},
+		},
+		Nesting: configschema.NestingSingle,
+	}
+	p.GetProviderSchemaResponse.ResourceTypes["test_instance"] = resourceSchema
+	p.GetProviderSchemaResponse.ListResourceTypes = map[string]providers.Schema{
+		"test_instance": {
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"ami": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	}
+	p.ListResourceFn = func(req providers.ListResourceRequest) providers.ListResourceResponse {
+		return providers.ListResourceResponse{
+			Results: []providers.ListResourceResult{
+				{
+					DisplayName: "web",
+					Identity:    cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("i-1")}),
+					ResourceObject: cty.ObjectVal(map[string]cty.Value{
+						"id":  cty.StringVal("i-1"),
+						"ami": cty.StringVal("bar"),
+					}),
+				},
+				{
+					DisplayName: "Worker 1",
+					Identity:    cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("i-2")}),
+					ResourceObject: cty.ObjectVal(map[string]cty.Value{
+						"id":  cty.StringVal("i-2"),
+						"ami": cty.StringVal("baz"),
+					}),
+				},
+			},
+		}
+	}
+	return p
+}
+
+func TestImportDiscover(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("import-discover"), td)
+	t.Chdir(td)
+	testStateFileDefault(t, importDiscoverFixtureState())
+
+	p := importDiscoverFixtureProvider()
+	view, done := testView(t)
+	c := &ImportDiscoverCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-filter=ami=baz", "test_instance"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	wantFilter := cty.ObjectVal(map[string]cty.Value{"ami": cty.StringVal("baz")})
+	if got := p.ListResourceRequest.Config; !got.RawEquals(wantFilter) {
+		t.Errorf("wrong filter configuration\ngot:  %#v\nwant: %#v", got, wantFilter)
+	}
+
+	stdout := output.Stdout()
+	for _, want := range []string{
+		"test_instance.worker_1 (Worker 1)",
+		"Skipped 1 object already managed by OpenTofu",
+		"written to discovered.tf",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("output is missing expected text\nwant substring: %s\ngot:\n%s", want, stdout)
+		}
+	}
+
+	src, err := os.ReadFile("discovered.tf")
+	if err != nil {
+		t.Fatalf("failed to read discovered.tf: %s", err)
+	}
+	want := `# __generated__ by OpenTofu
+# Please review these import blocks and resources and move them into your main configuration files.
+
+# __generated__ by OpenTofu from "Worker 1"
+import {
+  to = test_instance.worker_1
+  identity = {
+    id = "i-2"
+  }
+}
+
+resource "test_instance" "worker_1" {
+  ami = "baz"
+}
+`
+	if got := string(src); got != want {
+		t.Errorf("wrong discovered.tf content\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestImportDiscover_stdout(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("import-discover"), td)
+	t.Chdir(td)
+	testStateFileDefault(t, importDiscoverFixtureState())
+
+	view, done := testView(t)
+	c := &ImportDiscoverCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(importDiscoverFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-out=-", "test_instance"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	if want := "to = test_instance.worker_1"; !strings.Contains(output.Stdout(), want) {
+		t.Errorf("output doesn't include the import block\nwant substring: %s\ngot:\n%s", want, output.Stdout())
+	}
+	if _, err := os.Stat("discovered.tf"); !os.IsNotExist(err) {
+		t.Errorf("discovered.tf should not have been written")
+	}
+}
+
+func TestImportDiscover_unsupported(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("import-discover"), td)
+	t.Chdir(td)
+
+	view, done := testView(t)
+	c := &ImportDiscoverCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"test_instance"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected failure, got %d\n%s", code, output.Stdout())
+	}
+	if want := "Resource type does not support discovery"; !strings.Contains(output.Stderr(), want) {
+		t.Errorf("wrong error\nwant substring: %s\ngot:\n%s", want, output.Stderr())
+	}
+}
+
+func TestImportDiscover_existingOutFile(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("import-discover"), td)
+	t.Chdir(td)
+
+	if err := os.WriteFile("discovered.tf", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := importDiscoverFixtureProvider()
+	view, done := testView(t)
+	c := &ImportDiscoverCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"test_instance"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected failure, got %d\n%s", code, output.Stdout())
+	}
+	if want := "Target generated file already exists"; !strings.Contains(output.Stderr(), want) {
+		t.Errorf("wrong error\nwant substring: %s\ngot:\n%s", want, output.Stderr())
+	}
+	if p.ListResourceCalled {
+		t.Error("ListResource should not have been called")
+	}
+}
@@ -114,12 +114,13 @@ type resource struct {
 }
 
 type output struct {
-	Sensitive   bool        `json:"sensitive,omitempty"`
-	Ephemeral   bool        `json:"ephemeral,omitempty"`
-	Deprecated  string      `json:"deprecated,omitempty"`
-	Expression  *expression `json:"expression,omitempty"`
-	DependsOn   []string    `json:"depends_on,omitempty"`
-	Description string      `json:"description,omitempty"`
+	Sensitive   bool            `json:"sensitive,omitempty"`
+	Ephemeral   bool            `json:"ephemeral,omitempty"`
+	Deprecated  string          `json:"deprecated,omitempty"`
+	Type        json.RawMessage `json:"type,omitempty"`
+	Expression  *expression     `json:"expression,omitempty"`
+	DependsOn   []string        `json:"depends_on,omitempty"`
+	Description string          `json:"description,omitempty"`
 }
 
 type provisioner struct {
@@ -372,6 +373,14 @@ func marshalModule(c *configs.Config, schemas *tofu.Schemas, addr string) (modul
 		if v.Description != "" {
 			o.Description = v.Description
 		}
+		// As for variables, we leave the "type" property unset when the
+		// output has no type constraint, or its constraint is "any".
+		if v.ConstraintType != cty.NilType && !v.ConstraintType.Equals(cty.DynamicPseudoType) {
+			o.Type, err = v.ConstraintType.MarshalJSON()
+			if err != nil {
+				return module, fmt.Errorf("failed to marshal %#v as JSON: %w", v.ConstraintType, err)
+			}
+		}
 		if len(v.DependsOn) > 0 {
 			dependencies := make([]string, len(v.DependsOn))
 			for i, d := range v.DependsOn {
@@ -408,6 +408,30 @@ func TestMarshalModule(t *testing.T) {
 				ModuleCalls: map[string]moduleCall{},
 			},
 		},
+		"output, typed": {
+			Input: &configs.Config{
+				Module: &configs.Module{
+					Outputs: map[string]*configs.Output{
+						"example": {
+							Name:           "example",
+							Expr:           &hclsyntax.LiteralValueExpr{Val: cty.StringVal("test")},
+							Type:           cty.String,
+							ConstraintType: cty.String,
+						},
+					},
+				},
+			},
+			Schemas: emptySchemas,
+			Want: module{
+				Outputs: map[string]output{
+					"example": {
+						Type:       json.RawMessage(`"string"`),
+						Expression: ptrTo(marshalExpression(&hclsyntax.LiteralValueExpr{Val: cty.StringVal("test")})),
+					},
+				},
+				ModuleCalls: map[string]moduleCall{},
+			},
+		},
 		// TODO: More test cases covering things other than input variables.
 		// (For now the other details are mainly tested in package command,
 		// as part of the tests for "tofu show".)
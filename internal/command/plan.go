@@ -89,7 +89,7 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	}
 
 	// Build the operation request
-	opReq, opDiags := c.OperationRequest(ctx, be, view, args.ViewOptions, args.Operation, args.OutPath, args.GenerateConfigPath, enc)
+	opReq, opDiags := c.OperationRequest(ctx, be, view, args.ViewOptions, args.Operation, args.OutPath, args.EmbedSchemas, args.GenerateConfigPath, enc)
 	diags = diags.Append(opDiags)
 	if diags.HasErrors() {
 		view.Diagnostics(diags)
@@ -147,6 +147,7 @@ func (c *PlanCommand) OperationRequest(
 	viewOptions arguments.ViewOptions,
 	args *arguments.Operation,
 	planOutPath string,
+	planOutSchemas bool,
 	generateConfigOut string,
 	enc encryption.Encryption,
 ) (*backend.Operation, tfdiags.Diagnostics) {
@@ -159,9 +160,12 @@ func (c *PlanCommand) OperationRequest(
 	opReq.Hooks = view.Hooks()
 	opReq.PlanRefresh = args.Refresh
 	opReq.PlanOutPath = planOutPath
+	opReq.PlanOutSchemas = planOutSchemas
 	opReq.GenerateConfigOut = generateConfigOut
 	opReq.Targets = args.Targets
 	opReq.Excludes = args.Excludes
+	opReq.TargetSelectors = args.TargetSelectors
+	opReq.ExcludeSelectors = args.ExcludeSelectors
 	opReq.ForceReplace = args.ForceReplace
 	opReq.Type = backend.OperationTypePlan
 	opReq.View = view.Operation()
@@ -220,7 +224,9 @@ Plan Customization Options:
                           dependencies. You can use this option multiple times
                           to include more than one object. This is for
                           exceptional use only. Cannot be used alongside the
-                          -exclude option.
+                          -exclude option. Also accepts glob patterns like
+                          'module.app[*].aws_*' and the selectors
+                          provider=SOURCE, type=TYPE and tag=KEY[=VALUE].
 
   -target-file=filename   Similar to -target, but specifies zero or more
                           resource addresses from a file.
@@ -272,6 +278,11 @@ Other Options:
                                OpenTofu may still attempt to write
                                configuration if planning fails with an error.
 
+  -embed-schemas               Embed the parts of the provider schemas needed
+                               to render the plan in the plan file saved with
+                               -out, so that "tofu show" can show it without
+                               the providers installed.
+
   -input=false                 Disable prompting for required input variables
                                that are not set some other way.
 
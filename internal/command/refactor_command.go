@@ -0,0 +1,37 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// RefactorCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type RefactorCommand struct {
+	Meta
+}
+
+func (c *RefactorCommand) Run(_ []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *RefactorCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] refactor <subcommand> [options] [args]
+
+  This command has subcommands that help with restructuring a configuration
+  without destroying and recreating the objects it manages.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RefactorCommand) Synopsis() string {
+	return "Tools for restructuring configuration"
+}
@@ -0,0 +1,234 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/genconfig"
+	"github.com/opentofu/opentofu/internal/refactoring"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// RefactorSuggestCommand is a Command implementation that compares the
+// current state with the configuration and proposes moved blocks for
+// objects that seem to have been moved without one.
+type RefactorSuggestCommand struct {
+	Meta
+}
+
+func (c *RefactorSuggestCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+	// Because the legacy UI was using println to show diagnostics and the new view is using, by default, print,
+	// in order to keep functional parity, we setup the view to add a new line after each diagnostic.
+	c.View.DiagsWithNewline()
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseRefactorSuggest(rawArgs)
+	defer closer()
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewRefactorSuggest(c.View)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return cli.RunResultHelp
+	}
+	c.Meta.stateArgs = *args.State
+	c.Meta.variableArgs = args.Vars.All()
+
+	toStdout := args.Out == "-"
+	if !toStdout {
+		// We check this before doing any work so that we don't make the
+		// user wait for a plan only to find that we can't write the result.
+		if targetDiags := genconfig.ValidateTargetFile(args.Out); targetDiags.HasErrors() {
+			view.Diagnostics(diags.Append(targetDiags))
+			return 1
+		}
+	}
+
+	configPath := c.WorkingDir.NormalizePath(c.WorkingDir.RootModuleDir())
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plugins loading error",
+			fmt.Sprintf("Error loading plugin path: %s", err),
+		)))
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(ctx, configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(ctx, configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, &BackendOpts{
+		Config: backendConfig,
+		View:   view.Backend(),
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// We require a local backend
+	local, ok := b.(backend.Local)
+	if !ok {
+		view.Diagnostics(diags) // in case of any warnings in here
+		view.ErrorUnsupportedLocalOp()
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// Build the operation
+	opReq := c.Operation(ctx, b, view.Backend(), enc)
+	opReq.ConfigDir = configPath
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	{
+		// Setup required variables/call for operation (usually done in Meta.RunOperation)
+		var moreDiags, callDiags tfdiags.Diagnostics
+		opReq.Variables, moreDiags = c.collectVariableValues()
+		opReq.RootCall, callDiags = c.rootModuleCall(ctx, opReq.ConfigDir)
+		diags = diags.Append(moreDiags).Append(callDiags)
+		if moreDiags.HasErrors() || callDiags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
+	// Get the context
+	stopCtx, cancel := c.InterruptibleContext(ctx)
+	defer cancel()
+	lr, _, ctxDiags := local.LocalRun(ctx, stopCtx, opReq)
+	diags = diags.Append(ctxDiags)
+	if ctxDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Successfully creating the context can result in a lock, so ensure we release it
+	defer func() {
+		diags := opReq.StateLocker.Unlock()
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+		}
+	}()
+
+	orphaned, added, moreDiags := lr.Core.MoveCandidates(ctx, lr.Config, lr.InputState, lr.PlanOpts)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	view.Diagnostics(diags)
+
+	moves := refactoring.SuggestMoves(orphaned, added, args.MinConfidence)
+	if len(moves) == 0 {
+		if !toStdout {
+			view.NoSuggestions()
+		}
+		return 0
+	}
+
+	src := refactoring.FormatSuggestedMoves(moves)
+	if toStdout {
+		view.Output(string(src))
+		return 0
+	}
+
+	view.Suggestions(moves)
+	if err := os.WriteFile(args.Out, src, 0644); err != nil {
+		view.Diagnostics(tfdiags.Diagnostics{}.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write suggested moved blocks",
+			fmt.Sprintf("OpenTofu could not write the suggested moved blocks to %s: %s.", args.Out, err),
+		)))
+		return 1
+	}
+	view.WroteFile(args.Out)
+
+	return 0
+}
+
+func (c *RefactorSuggestCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] refactor suggest [options]
+
+  Compares the current state with the configuration and proposes moved blocks
+  for objects that seem to have been moved to a new address without one.
+
+  OpenTofu creates a plan without refreshing, and then compares each resource
+  instance that the plan would destroy because it is no longer declared in
+  the configuration with each resource instance of the same type that the plan
+  would create. Pairs whose known attribute values match closely are written
+  as moved blocks into a new file, each with a comment explaining its
+  confidence score. Review the result before running "tofu plan".
+
+Options:
+
+  -out=moved.tf          Write the suggested moved blocks into the given new
+                         file. Set to "-" to print them instead. The file must
+                         not already exist. Defaults to "moved.tf".
+
+  -min-confidence=0.5    Only suggest moves with a confidence score of at
+                         least the given value, between 0 and 1.
+
+  -lock=false            Don't hold a state lock during the operation. This
+                         is dangerous if others might concurrently run
+                         commands against the same workspace.
+
+  -lock-timeout=0s       Duration to retry a state lock.
+
+  -state=path            Legacy option for the local backend only. See the
+                         local backend's documentation for more information.
+
+  -var 'foo=bar'         Set a value for one of the input variables in the
+                         root module of the configuration. Use this option
+                         more than once to set more than one variable.
+
+  -var-file=filename     Load variable values from the given file, in
+                         addition to the default files terraform.tfvars and
+                         *.auto.tfvars. Use this option more than once to
+                         include more than one variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RefactorSuggestCommand) Synopsis() string {
+	return "Suggest moved blocks for objects moved in the configuration"
+}
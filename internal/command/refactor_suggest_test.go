@@ -0,0 +1,191 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// refactorSuggestFixtureState returns a state containing an object that was
+// declared as test_instance.original, which the "refactor-suggest" fixture
+// has renamed to test_instance.renamed.
+func refactorSuggestFixtureState() *states.State {
+	return states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "original",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"foo","ami":"bar"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+}
+
+func TestRefactorSuggest(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-suggest"), td)
+	t.Chdir(td)
+	testStateFileDefault(t, refactorSuggestFixtureState())
+
+	view, done := testView(t)
+	c := &RefactorSuggestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	stdout := output.Stdout()
+	if want := "test_instance.original -> test_instance.renamed"; !strings.Contains(stdout, want) {
+		t.Errorf("output doesn't describe the move\nwant substring: %s\ngot:\n%s", want, stdout)
+	}
+	if want := "written to moved.tf"; !strings.Contains(stdout, want) {
+		t.Errorf("output doesn't mention the file\nwant substring: %s\ngot:\n%s", want, stdout)
+	}
+
+	src, err := os.ReadFile("moved.tf")
+	if err != nil {
+		t.Fatalf("failed to read moved.tf: %s", err)
+	}
+	want := `# __generated__ by OpenTofu
+# Please review these moved blocks before applying them.
+
+# Confidence: 90% (1 of 1 known attribute values match)
+moved {
+  from = test_instance.original
+  to   = test_instance.renamed
+}
+`
+	if got := string(src); got != want {
+		t.Errorf("wrong moved.tf content\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRefactorSuggest_stdout(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-suggest"), td)
+	t.Chdir(td)
+	testStateFileDefault(t, refactorSuggestFixtureState())
+
+	view, done := testView(t)
+	c := &RefactorSuggestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-out=-"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	if want := "from = test_instance.original"; !strings.Contains(output.Stdout(), want) {
+		t.Errorf("output doesn't include the moved block\nwant substring: %s\ngot:\n%s", want, output.Stdout())
+	}
+	if _, err := os.Stat("moved.tf"); !os.IsNotExist(err) {
+		t.Errorf("moved.tf should not have been written")
+	}
+}
+
+func TestRefactorSuggest_noSuggestions(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-suggest"), td)
+	t.Chdir(td)
+
+	view, done := testView(t)
+	c := &RefactorSuggestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	if want := "No moves to suggest."; !strings.Contains(output.Stdout(), want) {
+		t.Errorf("wrong output\nwant substring: %s\ngot:\n%s", want, output.Stdout())
+	}
+	if _, err := os.Stat("moved.tf"); !os.IsNotExist(err) {
+		t.Errorf("moved.tf should not have been written")
+	}
+}
+
+func TestRefactorSuggest_outExists(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-suggest"), td)
+	t.Chdir(td)
+	testStateFileDefault(t, refactorSuggestFixtureState())
+	if err := os.WriteFile("moved.tf", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view, done := testView(t)
+	c := &RefactorSuggestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n%s", code, output.All())
+	}
+	if want := "Target generated file already exists"; !strings.Contains(output.Stderr(), want) {
+		t.Errorf("wrong error\nwant substring: %s\ngot:\n%s", want, output.Stderr())
+	}
+}
+
+func TestRefactorSuggest_invalidArgs(t *testing.T) {
+	view, done := testView(t)
+	c := &RefactorSuggestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-min-confidence=2"})
+	output := done(t)
+	if code != cli.RunResultHelp {
+		t.Fatalf("wrong exit code %d\n%s", code, output.All())
+	}
+}
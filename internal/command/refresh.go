@@ -144,6 +144,8 @@ func (c *RefreshCommand) OperationRequest(ctx context.Context, be backend.Enhanc
 	opReq.Hooks = view.Hooks()
 	opReq.Targets = args.Targets
 	opReq.Excludes = args.Excludes
+	opReq.TargetSelectors = args.TargetSelectors
+	opReq.ExcludeSelectors = args.ExcludeSelectors
 	opReq.Type = backend.OperationTypeRefresh
 	opReq.View = view.Operation()
 
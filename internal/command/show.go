@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 
@@ -241,13 +242,13 @@ func (c *ShowCommand) showFromSavedPlanFile(ctx context.Context, filename string
 		return nil, diags
 	}
 
-	plan, jsonPlan, stateFile, config, err := c.getPlanFromPath(ctx, filename, enc, rootCall)
+	plan, jsonPlan, stateFile, config, embeddedSchemas, err := c.getPlanFromPath(ctx, filename, enc, rootCall)
 	if err != nil {
 		diags = diags.Append(err)
 		return nil, diags
 	}
 
-	schemas, schemaDiags := c.maybeGetSchemas(ctx, stateFile, config)
+	schemas, schemaDiags := c.maybeGetPlanSchemas(ctx, stateFile, config, embeddedSchemas)
 	diags = diags.Append(schemaDiags)
 	if schemaDiags.HasErrors() {
 		return nil, diags
@@ -265,6 +266,7 @@ func (c *ShowCommand) legacyShowFromPath(ctx context.Context, path string, enc e
 	var jsonPlan *cloudplan.RemotePlanJSON
 	var stateFile *statefile.File
 	var config *configs.Config
+	var embeddedSchemas *tofu.Schemas
 
 	ctx, span := tracing.Tracer().Start(ctx, "Show")
 	defer span.End()
@@ -279,7 +281,7 @@ func (c *ShowCommand) legacyShowFromPath(ctx context.Context, path string, enc e
 	// state file. First, try to get a plan and associated data from a local
 	// plan file. If that fails, try to get a json plan from the path argument.
 	// If that fails, try to get the statefile from the path argument.
-	plan, jsonPlan, stateFile, config, planErr = c.getPlanFromPath(ctx, path, enc, rootCall)
+	plan, jsonPlan, stateFile, config, embeddedSchemas, planErr = c.getPlanFromPath(ctx, path, enc, rootCall)
 	if planErr != nil {
 		stateFile, stateErr = getStateFromPath(path, enc)
 		if stateErr != nil {
@@ -339,7 +341,7 @@ func (c *ShowCommand) legacyShowFromPath(ctx context.Context, path string, enc e
 		}
 	}
 
-	schemas, schemaDiags := c.maybeGetSchemas(ctx, stateFile, config)
+	schemas, schemaDiags := c.maybeGetPlanSchemas(ctx, stateFile, config, embeddedSchemas)
 	diags = diags.Append(schemaDiags)
 	if schemaDiags.HasErrors() {
 		tracing.SetSpanError(span, diags)
@@ -362,32 +364,37 @@ func (c *ShowCommand) legacyShowFromPath(ctx context.Context, path string, enc e
 	}
 }
 
-// getPlanFromPath returns a plan, json plan, statefile, and config if the
-// user-supplied path points to either a local or cloud plan file. Note that
-// some of the return values will be nil no matter what; local plan files do not
-// yield a json plan, and cloud plans do not yield real plan/state/config
-// structs. An error generally suggests that the given path is either a
-// directory or a statefile.
-func (c *ShowCommand) getPlanFromPath(ctx context.Context, path string, enc encryption.Encryption, rootCall configs.StaticModuleCall) (*plans.Plan, *cloudplan.RemotePlanJSON, *statefile.File, *configs.Config, error) {
+// getPlanFromPath returns a plan, json plan, statefile, config, and any
+// embedded schemas if the user-supplied path points to either a local or
+// cloud plan file. Note that some of the return values will be nil no matter
+// what; local plan files do not yield a json plan, cloud plans do not yield
+// real plan/state/config structs, and only local plan files created with
+// "tofu plan -embed-schemas" yield schemas. An error generally suggests that
+// the given path is either a directory or a statefile.
+func (c *ShowCommand) getPlanFromPath(ctx context.Context, path string, enc encryption.Encryption, rootCall configs.StaticModuleCall) (*plans.Plan, *cloudplan.RemotePlanJSON, *statefile.File, *configs.Config, *tofu.Schemas, error) {
 	var err error
 	var plan *plans.Plan
 	var jsonPlan *cloudplan.RemotePlanJSON
 	var stateFile *statefile.File
 	var config *configs.Config
+	var schemas *tofu.Schemas
 
 	pf, err := planfile.OpenWrapped(path, enc.Plan())
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	if lp, ok := pf.Local(); ok {
 		plan, stateFile, config, err = getDataFromPlanfileReader(ctx, lp, rootCall)
+		if err == nil {
+			schemas, err = getSchemasFromPlanfileReader(lp)
+		}
 	} else if cp, ok := pf.Cloud(); ok {
 		redacted := c.viewType != arguments.ViewJSON
 		jsonPlan, err = c.getDataFromCloudPlan(ctx, cp, redacted, enc)
 	}
 
-	return plan, jsonPlan, stateFile, config, err
+	return plan, jsonPlan, stateFile, config, schemas, err
 }
 
 func (c *ShowCommand) getDataFromCloudPlan(ctx context.Context, plan *cloudplan.SavedPlanBookmark, redacted bool, enc encryption.Encryption) (*cloudplan.RemotePlanJSON, error) {
@@ -431,6 +438,18 @@ func (c *ShowCommand) maybeGetSchemas(ctx context.Context, stateFile *statefile.
 
 }
 
+// maybeGetPlanSchemas is like maybeGetSchemas, except that if the schemas
+// can't be loaded from the providers, such as when they aren't installed,
+// it falls back on the given schemas embedded in a saved plan file, if any.
+func (c *ShowCommand) maybeGetPlanSchemas(ctx context.Context, stateFile *statefile.File, config *configs.Config, embedded *tofu.Schemas) (*tofu.Schemas, tfdiags.Diagnostics) {
+	schemas, diags := c.maybeGetSchemas(ctx, stateFile, config)
+	if embedded != nil && (diags.HasErrors() || schemas == nil) {
+		log.Printf("[INFO] show: using the provider schemas embedded in the saved plan file")
+		return embedded, nil
+	}
+	return schemas, diags
+}
+
 // getDataFromPlanfileReader returns a plan, statefile, and config, extracted from a local plan file.
 func getDataFromPlanfileReader(ctx context.Context, planReader *planfile.Reader, rootCall configs.StaticModuleCall) (*plans.Plan, *statefile.File, *configs.Config, error) {
 	// Get plan
@@ -455,6 +474,26 @@ func getDataFromPlanfileReader(ctx context.Context, planReader *planfile.Reader,
 	return plan, stateFile, config, err
 }
 
+// getSchemasFromPlanfileReader returns the schemas embedded in a local plan
+// file, or nil if the plan file doesn't include any.
+func getSchemasFromPlanfileReader(planReader *planfile.Reader) (*tofu.Schemas, error) {
+	providerSchemas, err := planReader.ReadProviderSchemas()
+	if errors.Is(err, planfile.ErrNoSchemas) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errUnusable(err, "local plan")
+	}
+	provisionerSchemas, err := planReader.ReadProvisionerSchemas()
+	if err != nil && !errors.Is(err, planfile.ErrNoSchemas) {
+		return nil, errUnusable(err, "local plan")
+	}
+	return &tofu.Schemas{
+		Providers:    providerSchemas,
+		Provisioners: provisionerSchemas,
+	}, nil
+}
+
 // getStateFromPath returns a statefile if the user-supplied path points to a statefile.
 func getStateFromPath(path string, enc encryption.Encryption) (*statefile.File, error) {
 	file, err := os.Open(path)
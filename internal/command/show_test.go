@@ -21,9 +21,13 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tofu"
 	"github.com/opentofu/opentofu/version"
@@ -385,6 +389,130 @@ func TestShow_planWithChanges(t *testing.T) {
 	}
 }
 
+func TestShow_planEmbeddedSchemas(t *testing.T) {
+	// The plan is shown without any providers available, and from a
+	// working directory that contains nothing at all, so the schemas
+	// embedded in the plan file must be used.
+	td := t.TempDir()
+	t.Chdir(td)
+
+	withoutSchemas := showFixturePlanFile(t, plans.Create)
+	pr, err := planfile.Open(withoutSchemas, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := pr.ReadPlan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap, err := pr.ReadConfigSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	withSchemas := filepath.Join(td, "tfplan")
+	err = planfile.Create(withSchemas, planfile.CreateArgs{
+		ConfigSnapshot:       snap,
+		PreviousRunStateFile: &statefile.File{State: plan.PrevRunState, TerraformVersion: version.SemVer},
+		StateFile:            &statefile.File{State: plan.PriorState, TerraformVersion: version.SemVer},
+		Plan:                 plan,
+		DependencyLocks:      depsfile.NewLocks(),
+		ProviderSchemas: map[addrs.Provider]providers.ProviderSchema{
+			addrs.NewDefaultProvider("test"): *showFixtureSchema(),
+		},
+		ProvisionerSchemas: map[string]*configschema.Block{},
+	}, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		"human": {
+			args: []string{"-plan=" + withSchemas, "-no-color"},
+			want: "test_instance.foo will be created",
+		},
+		"json": {
+			args: []string{"-plan=" + withSchemas, "-json"},
+			want: `"resource_changes":[{"address":"test_instance.foo"`,
+		},
+		"legacy positional argument": {
+			args: []string{"-no-color", withSchemas},
+			want: "test_instance.foo will be created",
+		},
+		"no embedded schemas": {
+			args:     []string{"-plan=" + withoutSchemas, "-no-color"},
+			wantCode: 1,
+			want:     "Failed to load plugin schemas",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &ShowCommand{
+				Meta: Meta{
+					WorkingDir: workdir.NewDir("."),
+					View:       view,
+				},
+			}
+
+			code := c.Run(test.args)
+			output := done(t)
+
+			if code != test.wantCode {
+				t.Fatalf("unexpected exit status %d; want %d\nstdout: %s\nstderr: %s", code, test.wantCode, output.Stdout(), output.Stderr())
+			}
+			if got := output.All(); !strings.Contains(got, test.want) {
+				t.Fatalf("unexpected output\ngot: %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestShow_planEmbeddedSchemasRoundTrip(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	outPath := filepath.Join(td, "tfplan")
+
+	planView, planDone := testView(t)
+	planCmd := &PlanCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(planFixtureProvider()),
+			View:             planView,
+		},
+	}
+	code := planCmd.Run([]string{"-out", outPath, "-embed-schemas"})
+	output := planDone(t)
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d\n%s", code, output.Stderr())
+	}
+
+	// The plan is then shown from an empty directory without any providers
+	// available, so it can only be rendered using the embedded schemas.
+	t.Chdir(t.TempDir())
+
+	showView, showDone := testView(t)
+	showCmd := &ShowCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			View:       showView,
+		},
+	}
+	code = showCmd.Run([]string{"-plan=" + outPath, "-no-color"})
+	output = showDone(t)
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d\nstdout: %s\nstderr: %s", code, output.Stdout(), output.Stderr())
+	}
+	if got, want := output.Stdout(), "test_instance.foo will be created"; !strings.Contains(got, want) {
+		t.Fatalf("unexpected output\ngot: %s\nwant: %s", got, want)
+	}
+}
+
 func TestShow_planWithForceReplaceChange(t *testing.T) {
 	// The main goal of this test is to see that the "replace by request"
 	// resource instance action reason can round-trip through a plan file and
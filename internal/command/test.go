@@ -9,12 +9,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"maps"
+	"os"
 	"path"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/opentofu/opentofu/internal/lang"
@@ -25,6 +29,8 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/testreport"
+	"github.com/opentofu/opentofu/internal/command/testsnapshot"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
@@ -63,6 +69,11 @@ Usage: tofu [global options] test [options]
 
 Options:
 
+  -cleanup-leftovers    Instead of executing the tests, destroy the
+                        infrastructure left behind by previous executions,
+                        because destroying it failed or because the test file
+                        sets skip_cleanup in its cleanup block.
+
   -compact-warnings     If OpenTofu produces any warnings that are not
                         accompanied by errors, show them in a more compact
                         form that includes only the summary messages.
@@ -104,6 +115,29 @@ Options:
                         Use this option more than once to include more than one
                         variables file.
 
+  -junit-xml=path       Write a report of the test results to the given file
+                        in the JUnit XML format, which is understood by most
+                        continuous integration systems.
+
+  -tap=path             Write a report of the test results to the given file
+                        using the Test Anything Protocol.
+
+  -coverage             Print a summary of which resources, data sources,
+                        outputs, validations, checks and conditions of the
+                        configuration under test were exercised by the tests.
+
+  -coverage-json=path   Write the coverage report to the given file as JSON.
+
+  -coverage-lcov=path   Write the coverage report to the given file in the
+                        LCOV format.
+
+  -parallelism=n        Execute up to n test files at the same time. Defaults
+                        to 1.
+
+  -update-snapshots     Write the plan snapshot files named by the
+                        expect_plan_snapshot argument of run blocks, instead
+                        of comparing the plans against them.
+
   -verbose              Print the plan or state for each test run block as it
                         executes.
 
@@ -167,35 +201,41 @@ func (c *TestCommand) Run(rawArgs []string) int {
 			files := make(map[string]*moduletest.File)
 
 			if len(args.Filter) > 0 {
-				for _, name := range args.Filter {
-					file, ok := config.Module.Tests[name]
-					if !ok {
+				for _, filter := range args.Filter {
+					// Test files with a matrix block are expanded into one
+					// file for each combination, and the filter selects all
+					// of them.
+					names := testFileNames(config.Module.Tests, filter)
+					if len(names) == 0 {
 						// If the filter is invalid, we'll simply skip this
 						// entry and print a warning. But we could still execute
 						// any other tests within the filter.
 						fileDiags.Append(tfdiags.Sourceless(
 							tfdiags.Warning,
 							"Unknown test file",
-							fmt.Sprintf("The specified test file, %s, could not be found.", name)))
+							fmt.Sprintf("The specified test file, %s, could not be found.", filter)))
 						continue
 					}
 
-					fileCount++
-
-					var runs []*moduletest.Run
-					for ix, run := range file.Runs {
-						runs = append(runs, &moduletest.Run{
-							Config: run,
-							Index:  ix,
-							Name:   run.Name,
-						})
-					}
-
-					runCount += len(runs)
-					files[name] = &moduletest.File{
-						Config: file,
-						Name:   name,
-						Runs:   runs,
+					for _, name := range names {
+						file := config.Module.Tests[name]
+						fileCount++
+
+						var runs []*moduletest.Run
+						for ix, run := range file.Runs {
+							runs = append(runs, &moduletest.Run{
+								Config: run,
+								Index:  ix,
+								Name:   run.Name,
+							})
+						}
+
+						runCount += len(runs)
+						files[name] = &moduletest.File{
+							Config: file,
+							Name:   name,
+							Runs:   runs,
+						}
 					}
 				}
 
@@ -228,6 +268,10 @@ func (c *TestCommand) Run(rawArgs []string) int {
 
 	log.Printf("[DEBUG] TestCommand: found %d files with %d run blocks", fileCount, runCount)
 
+	if args.Coverage || args.CoverageJSONPath != "" || args.CoverageLCOVPath != "" {
+		suite.Coverage = moduletest.NewCoverage(config)
+	}
+
 	if len(args.Filter) > 0 && len(suite.Files) == 0 {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Warning,
@@ -252,6 +296,16 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	// Don't use encryption during testing
 	opts.Encryption = encryption.Disabled()
 
+	leftovers, err := loadTestLeftovers(filepath.Join(c.WorkingDir.DataDir(), testLeftoversDir))
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read leftover test states",
+			fmt.Sprintf("The record of the states left behind by previous test executions could not be read: %s.", err)))
+		view.Diagnostics(nil, nil, diags)
+		return 1
+	}
+
 	// Print out all the diagnostics we have from the setup. These will just be
 	// warnings, and we want them out of the way before we start the actual
 	// testing.
@@ -287,7 +341,23 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		Cancelled: false,
 		Stopped:   false,
 
-		Verbose: args.Verbose,
+		Verbose:         args.Verbose,
+		Parallelism:     args.Parallelism,
+		UpdateSnapshots: args.UpdateSnapshots,
+		Leftovers:       leftovers,
+	}
+
+	if args.CleanupLeftovers {
+		defer done()
+		defer stop()
+		defer cancel()
+
+		cleaned, remaining := runner.CleanupLeftovers(ctx)
+		view.CleanupLeftovers(cleaned, remaining)
+		if remaining > 0 {
+			return 1
+		}
+		return 0
 	}
 
 	view.Abstract(&suite)
@@ -340,6 +410,14 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	}
 
 	view.Conclusion(&suite)
+	if args.Coverage {
+		view.Coverage(suite.Coverage)
+	}
+
+	if reportDiags := c.writeTestReports(args, &suite); reportDiags.HasErrors() {
+		view.Diagnostics(nil, nil, reportDiags)
+		return 1
+	}
 
 	if suite.Status != moduletest.Pass {
 		return 1
@@ -347,6 +425,66 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	return 0
 }
 
+// testFileNames returns the names of the test files selected by the given
+// -filter argument. This is either the file with exactly that name, or all
+// the files that were expanded from the matrix block of that file.
+func testFileNames(tests map[string]*configs.TestFile, filter string) []string {
+	if _, exists := tests[filter]; exists {
+		return []string{filter}
+	}
+	var names []string
+	for name := range tests {
+		if strings.HasPrefix(name, filter+"[") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// writeTestReports writes the results of the given suite to any report files
+// that were requested on the command line.
+func (c *TestCommand) writeTestReports(args *arguments.Test, suite *moduletest.Suite) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	var sources map[string]*hcl.File
+	if c.configLoader != nil {
+		sources = c.configLoader.Sources()
+	}
+
+	reports := []struct {
+		path  string
+		kind  string
+		write func(io.Writer, *moduletest.Suite, map[string]*hcl.File) error
+	}{
+		{args.JUnitXMLPath, "JUnit XML", testreport.WriteJUnitXML},
+		{args.TAPPath, "TAP", testreport.WriteTAP},
+		{args.CoverageJSONPath, "JSON coverage", func(w io.Writer, suite *moduletest.Suite, _ map[string]*hcl.File) error {
+			return testreport.WriteCoverageJSON(w, suite.Coverage)
+		}},
+		{args.CoverageLCOVPath, "LCOV coverage", func(w io.Writer, suite *moduletest.Suite, _ map[string]*hcl.File) error {
+			return testreport.WriteCoverageLCOV(w, suite.Coverage)
+		}},
+	}
+	for _, report := range reports {
+		if report.path == "" {
+			continue
+		}
+		var buf bytes.Buffer
+		err := report.write(&buf, suite, sources)
+		if err == nil {
+			err = os.WriteFile(report.path, buf.Bytes(), 0644)
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Failed to write %s report", report.kind),
+				fmt.Sprintf("The test results could not be written to %s: %s.", report.path, err),
+			))
+		}
+	}
+	return diags
+}
+
 // test runner
 
 type TestSuiteRunner struct {
@@ -378,6 +516,17 @@ type TestSuiteRunner struct {
 
 	// Verbose tells the runner to print out plan files during each test run.
 	Verbose bool
+
+	// Parallelism is the maximum number of test files to execute at once.
+	Parallelism int
+
+	// UpdateSnapshots tells the runner to write the plan snapshots for run
+	// blocks instead of comparing against them.
+	UpdateSnapshots bool
+
+	// Leftovers records the states that could not be destroyed, so a later
+	// execution of tofu test -cleanup-leftovers can destroy them.
+	Leftovers *testLeftovers
 }
 
 func (runner *TestSuiteRunner) Start(ctx context.Context) {
@@ -388,50 +537,171 @@ func (runner *TestSuiteRunner) Start(ctx context.Context) {
 	sort.Strings(files) // execute the files in alphabetical order
 
 	runner.Suite.Status = moduletest.Pass
+	if runner.Parallelism > 1 {
+		runner.startParallel(ctx, files)
+		return
+	}
+
 	for _, name := range files {
 		if runner.Cancelled {
 			return
 		}
 
 		file := runner.Suite.Files[name]
+		runner.newFileRunner(runner.Config, runner.View).Execute(ctx, file)
+		runner.Suite.Status = runner.Suite.Status.Merge(file.Status)
+	}
+}
 
-		fileRunner := &TestFileRunner{
-			Suite: runner,
-			States: map[string]*TestFileState{
-				MainStateIdentifier: {
-					Run:   nil,
-					State: states.NewState(),
-				},
-			},
-		}
+// startParallel executes up to runner.Parallelism test files at once.
+//
+// Executing a run block temporarily modifies the configuration it is executed
+// against, so each test file gets its own copy of the main configuration. Each
+// test file also gets its own buffered view, which we flush in alphabetical
+// order so the output is the same regardless of which files finish first.
+func (runner *TestSuiteRunner) startParallel(ctx context.Context, files []string) {
+	buffers := make([]*views.TestBuffer, len(files))
+	finished := make([]chan struct{}, len(files))
+	for i := range files {
+		buffers[i] = views.NewTestBuffer(runner.View)
+		finished[i] = make(chan struct{})
+	}
+
+	// The config loader is not safe for concurrent use.
+	var loadLock sync.Mutex
+
+	next := make(chan int, len(files))
+	for i := range files {
+		next <- i
+	}
+	close(next)
+
+	for range min(runner.Parallelism, len(files)) {
+		go func() {
+			panicHandler := logging.PanicHandlerWithTraceFn()
+			defer panicHandler()
+
+			for i := range next {
+				file := runner.Suite.Files[files[i]]
+				if !runner.Cancelled {
+					loadLock.Lock()
+					config, diags := runner.command.loadConfig(ctx, ".")
+					loadLock.Unlock()
+
+					if diags.HasErrors() {
+						file.Status = moduletest.Error
+						file.Diagnostics = file.Diagnostics.Append(diags)
+						for _, run := range file.Runs {
+							run.Status = moduletest.Skip
+						}
+						buffers[i].File(file)
+						for _, run := range file.Runs {
+							buffers[i].Run(run, file)
+						}
+					} else {
+						runner.newFileRunner(config, buffers[i]).Execute(ctx, file)
+					}
+				}
+				close(finished[i])
+			}
+		}()
+	}
 
-		fileRunner.ExecuteTestFile(ctx, file)
-		fileRunner.Cleanup(ctx, file)
-		runner.Suite.Status = runner.Suite.Status.Merge(file.Status)
+	for i, name := range files {
+		<-finished[i]
+		buffers[i].Flush()
+		runner.Suite.Status = runner.Suite.Status.Merge(runner.Suite.Files[name].Status)
+	}
+}
+
+func (runner *TestSuiteRunner) newFileRunner(config *configs.Config, view views.Test) *TestFileRunner {
+	return &TestFileRunner{
+		Suite:  runner,
+		Config: config,
+		View:   view,
+		States: map[string]*TestFileState{
+			MainStateIdentifier: {
+				Run:   nil,
+				State: states.NewState(),
+			},
+		},
 	}
 }
 
 type TestFileRunner struct {
 	Suite *TestSuiteRunner
 
+	// Config is the main configuration under test. This is usually the same
+	// as Suite.Config, but test files that execute concurrently each have
+	// their own copy.
+	Config *configs.Config
+
+	// View is where the output for this test file is written.
+	View views.Test
+
 	States map[string]*TestFileState
 }
 
 type TestFileState struct {
 	Run   *moduletest.Run
 	State *states.State
+
+	// Expanded holds the outputs of the run blocks expanded from for_each
+	// that executed against this state, keyed by the name of the original
+	// run block and then by each.key. Every instance replaces Run and State
+	// in turn, so their outputs must be kept separately for later run blocks
+	// to refer to all of them.
+	Expanded map[string]map[string]cty.Value
+}
+
+// Execute executes all the run blocks within the given test file, and then
+// destroys any infrastructure they left behind.
+func (runner *TestFileRunner) Execute(ctx context.Context, file *moduletest.File) {
+	start := time.Now()
+	runner.ExecuteTestFile(ctx, file)
+	runner.Cleanup(ctx, file)
+	file.Duration = time.Since(start)
 }
 
 func (runner *TestFileRunner) ExecuteTestFile(ctx context.Context, file *moduletest.File) {
 	log.Printf("[TRACE] TestFileRunner: executing test file %s", file.Name)
 
 	file.Status = file.Status.Merge(moduletest.Pass)
+	for ix := 0; ix < len(file.Runs); {
+		group := runGroup(file, ix)
+		ix += len(group)
+
+		if !runner.executeRunGroup(ctx, file, group) {
+			return
+		}
+	}
+
+	runner.View.File(file)
 	for _, run := range file.Runs {
+		runner.View.Run(run, file)
+	}
+}
+
+// executeRunGroup executes the given run blocks concurrently, and then
+// records their results in order. It returns false if the test file should
+// not be executed any further.
+func (runner *TestFileRunner) executeRunGroup(ctx context.Context, file *moduletest.File, group []*moduletest.Run) bool {
+	type pendingRun struct {
+		run    *moduletest.Run
+		key    string
+		config *configs.Config
+
+		state   *states.State
+		updated bool
+	}
+
+	var pending []*pendingRun
+	for _, run := range group {
 		if runner.Suite.Cancelled {
 			// This means a hard stop has been requested, in this case we don't
 			// even stop to mark future tests as having been skipped. They'll
 			// just show up as pending in the printed summary.
-			return
+			return false
 		}
 
 		if runner.Suite.Stopped {
@@ -449,13 +719,12 @@ func (runner *TestFileRunner) ExecuteTestFile(ctx context.Context, file *modulet
 			continue
 		}
 
-		key := MainStateIdentifier
-		config := runner.Suite.Config
+		key := runStateKey(run)
+		config := runner.Config
 		if run.Config.ConfigUnderTest != nil {
 			config = run.Config.ConfigUnderTest
 			// Then we need to load an alternate state and not the main one.
 
-			key = run.Config.Module.Source.String()
 			if key == MainStateIdentifier {
 				// This is bad. It means somehow the module we're loading has
 				// the same key as main state and we're about to corrupt things.
@@ -480,43 +749,165 @@ func (runner *TestFileRunner) ExecuteTestFile(ctx context.Context, file *modulet
 			}
 		}
 
-		state, updatedState := runner.ExecuteTestRun(ctx, run, file, runner.States[key].State, config)
-		if updatedState {
-			var err error
+		pending = append(pending, &pendingRun{
+			run:    run,
+			key:    key,
+			config: config,
+		})
+	}
+
+	execute := func(p *pendingRun) {
+		start := time.Now()
+		p.state, p.updated = runner.ExecuteTestRun(ctx, p.run, file, runner.States[p.key].State, p.config)
+		p.run.Duration = time.Since(start)
+	}
+
+	if len(pending) == 1 {
+		execute(pending[0])
+	} else {
+		// The runs within a group never share a state or a configuration,
+		// and we don't update runner.States until they have all finished, so
+		// they can safely execute at the same time.
+		var wg sync.WaitGroup
+		for _, p := range pending {
+			wg.Go(func() {
+				panicHandler := logging.PanicHandlerWithTraceFn()
+				defer panicHandler()
+
+				execute(p)
+			})
+		}
+		wg.Wait()
+	}
 
+	for _, p := range pending {
+		if p.updated {
 			// We need to simulate state serialization between multiple runs
 			// due to its side effects. One of such side effects is removal
 			// of destroyed non-root module outputs. This is not handled
 			// during graph walk since those values are not stored in the
 			// state file. This is more of a weird workaround instead of a
 			// proper fix, unfortunately.
-			state, err = simulateStateSerialization(state)
+			state, err := simulateStateSerialization(p.state)
 			if err != nil {
-				run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				p.run.Diagnostics = p.run.Diagnostics.Append(&hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "Failure during state serialization",
 					Detail:   err.Error(),
 				})
 
 				// We cannot reuse state later so that's a hard stop.
-				return
+				return false
 			}
 
 			// Only update the most recent run and state if the state was
 			// actually updated by this change. We want to use the run that
 			// most recently updated the tracked state as the cleanup
 			// configuration.
-			runner.States[key].State = state
-			runner.States[key].Run = run
+			runner.States[p.key].State = state
+			runner.States[p.key].Run = p.run
+
+			if cfg := p.run.Config; cfg.ForEach != nil {
+				fileState := runner.States[p.key]
+				if fileState.Expanded == nil {
+					fileState.Expanded = make(map[string]map[string]cty.Value)
+				}
+				if fileState.Expanded[cfg.BaseName] == nil {
+					fileState.Expanded[cfg.BaseName] = make(map[string]cty.Value)
+				}
+				fileState.Expanded[cfg.BaseName][cfg.Each.EachKey.AsString()] = rootOutputValues(state)
+			}
 		}
 
-		file.Status = file.Status.Merge(run.Status)
+		file.Status = file.Status.Merge(p.run.Status)
 	}
 
-	runner.Suite.View.File(file)
-	for _, run := range file.Runs {
-		runner.Suite.View.Run(run, file)
+	return true
+}
+
+// runGroup returns the run blocks, starting at the given index, that should
+// be executed together.
+//
+// A run block is executed on its own unless it sets the parallel attribute,
+// in which case it is grouped with the directly following run blocks that
+// also set it. The group ends early at any run block that would share a state
+// with an earlier member of the group, or that refers to the outputs of an
+// earlier member of the group.
+func runGroup(file *moduletest.File, start int) []*moduletest.Run {
+	first := file.Runs[start]
+	group := []*moduletest.Run{first}
+	if !first.Config.Parallel {
+		return group
+	}
+
+	keys := map[string]bool{runStateKey(first): true}
+	names := map[string]bool{first.Name: true, first.Config.BaseName: true}
+	for _, run := range file.Runs[start+1:] {
+		if !run.Config.Parallel {
+			break
+		}
+
+		key := runStateKey(run)
+		if keys[key] || referencesRuns(run, file, names) {
+			break
+		}
+
+		keys[key] = true
+		names[run.Name] = true
+		names[run.Config.BaseName] = true
+		group = append(group, run)
 	}
+	return group
+}
+
+// runStateKey returns the key of the state within TestFileRunner.States that
+// the given run block executes against.
+func runStateKey(run *moduletest.Run) string {
+	if run.Config.ConfigUnderTest == nil {
+		return MainStateIdentifier
+	}
+	return run.Config.Module.Source.String()
+}
+
+// referencesRuns returns true if the variables, assertions or provider blocks
+// used by the given run block refer to the outputs of any of the named run
+// blocks, either as run.name or as run["name"].
+func referencesRuns(run *moduletest.Run, file *moduletest.File, names map[string]bool) bool {
+	var exprs []hcl.Expression
+	for _, expr := range run.Config.Variables {
+		exprs = append(exprs, expr)
+	}
+	for _, expr := range file.Config.Variables {
+		exprs = append(exprs, expr)
+	}
+	for _, rule := range run.Config.CheckRules {
+		exprs = append(exprs, rule.Condition, rule.ErrorMessage)
+	}
+
+	traversals := file.Config.ProviderTraversals()
+	for _, expr := range exprs {
+		if expr != nil {
+			traversals = append(traversals, expr.Variables()...)
+		}
+	}
+
+	for _, traversal := range traversals {
+		if traversal.RootName() != "run" || len(traversal) < 2 {
+			continue
+		}
+		switch step := traversal[1].(type) {
+		case hcl.TraverseAttr:
+			if names[step.Name] {
+				return true
+			}
+		case hcl.TraverseIndex:
+			key := step.Key
+			if key.Type() == cty.String && key.IsKnown() && !key.IsNull() && names[key.AsString()] {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletest.Run, file *moduletest.File, state *states.State, config *configs.Config) (*states.State, bool) {
@@ -535,6 +926,16 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		return state, false
 	}
 
+	expectedDiags := run.BuildExpectedDiagnostics()
+	defer func() {
+		// Expected diagnostics that were never produced are errors, no
+		// matter how the run block finished.
+		if missing := expectedDiags.Missing(); len(missing) > 0 {
+			run.Diagnostics = run.Diagnostics.Append(missing)
+			run.Status = run.Status.Merge(moduletest.Error)
+		}
+	}()
+
 	run.Diagnostics = run.Diagnostics.Append(file.Config.Validate())
 	if run.Diagnostics.HasErrors() {
 		run.Status = moduletest.Error
@@ -563,23 +964,34 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		return state, false
 	}
 
-	validateDiags := runner.validate(ctx, config, run, file)
+	validateDiags, expectedError := expectedDiags.Filter(runner.validate(ctx, config, run, file))
 	run.Diagnostics = run.Diagnostics.Append(validateDiags)
 	if validateDiags.HasErrors() {
 		run.Status = moduletest.Error
 		return state, false
 	}
+	if expectedError {
+		// The configuration was invalid in the way the run block expected,
+		// so there is nothing left to execute.
+		run.Status = moduletest.Pass
+		return state, false
+	}
 
 	planCtx, plan, planDiags := runner.plan(ctx, config, state, run, file)
 	if run.Config.Command == configs.PlanTestCommand {
 		expectedFailures, sourceRanges := run.BuildExpectedFailuresAndSourceMaps()
 		// Then we want to assess our conditions and diagnostics differently.
 		planDiags = run.ValidateExpectedFailures(expectedFailures, sourceRanges, planDiags)
+		planDiags, expectedError = expectedDiags.Filter(planDiags)
 		run.Diagnostics = run.Diagnostics.Append(planDiags)
 		if planDiags.HasErrors() {
 			run.Status = moduletest.Error
 			return state, false
 		}
+		if expectedError {
+			run.Status = moduletest.Pass
+			return state, false
+		}
 
 		variables, resetVariables, variableDiags := runner.prepareInputVariablesForAssertions(config, run, file, runner.Suite.GlobalVariables)
 		defer resetVariables()
@@ -616,12 +1028,15 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		}
 
 		planCtx.TestContext(config, plan.PlannedState, plan, variables).EvaluateAgainstPlan(run)
+		runner.checkPlanSnapshot(ctx, planCtx, config, plan, run, file)
+		runner.recordCoverage(run, plan, nil)
 		return state, false
 	}
 
 	expectedFailures, sourceRanges := run.BuildExpectedFailuresAndSourceMaps()
 
 	planDiags = checkProblematicPlanErrors(expectedFailures, planDiags)
+	planDiags, expectedError = expectedDiags.Filter(planDiags)
 
 	// Otherwise any error during the planning prevents our apply from
 	// continuing which is an error.
@@ -630,6 +1045,10 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		run.Status = moduletest.Error
 		return state, false
 	}
+	if expectedError {
+		run.Status = moduletest.Pass
+		return state, false
+	}
 
 	// Since we're carrying on an executing the apply operation as well, we're
 	// just going to do some post processing of the diagnostics. We remove the
@@ -649,6 +1068,7 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 
 	// Remove expected diagnostics, and add diagnostics in case anything that should have failed didn't.
 	applyDiags = run.ValidateExpectedFailures(expectedFailures, sourceRanges, applyDiags)
+	applyDiags, expectedError = expectedDiags.Filter(applyDiags)
 
 	run.Diagnostics = run.Diagnostics.Append(applyDiags)
 	if applyDiags.HasErrors() {
@@ -657,6 +1077,10 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		// partial updates and the returned state should reflect this.
 		return updated, true
 	}
+	if expectedError {
+		run.Status = moduletest.Pass
+		return updated, true
+	}
 
 	variables, resetVariables, variableDiags := runner.prepareInputVariablesForAssertions(config, run, file, runner.Suite.GlobalVariables)
 	defer resetVariables()
@@ -693,9 +1117,110 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 	}
 
 	applyCtx.TestContext(config, updated, plan, variables).EvaluateAgainstState(run)
+	runner.checkPlanSnapshot(ctx, planCtx, config, plan, run, file)
+	runner.recordCoverage(run, plan, updated)
 	return updated, true
 }
 
+// recordCoverage records the parts of the main configuration that were
+// exercised by the given run block, if coverage was requested. Run blocks
+// that load a different module are not included, as the coverage only
+// tracks the configuration under test.
+func (runner *TestFileRunner) recordCoverage(run *moduletest.Run, plan *plans.Plan, state *states.State) {
+	if runner.Suite.Suite.Coverage == nil || run.Config.ConfigUnderTest != nil {
+		return
+	}
+	runner.Suite.Suite.Coverage.Record(plan, state)
+}
+
+// checkPlanSnapshot compares the plan for the given run block against the
+// snapshot file named in its expect_plan_snapshot argument, if any, and marks
+// the run as failed if they differ. If the user requested that the snapshots
+// be updated, it writes the snapshot file instead.
+func (runner *TestFileRunner) checkPlanSnapshot(ctx context.Context, tfCtx *tofu.Context, config *configs.Config, plan *plans.Plan, run *moduletest.Run, file *moduletest.File) {
+	if run.Config.ExpectPlanSnapshot == "" {
+		return
+	}
+
+	// Snapshot paths are relative to the test file, so they keep working
+	// regardless of which directory the tests are executed from.
+	// We use the path of the original file rather than the name of the file,
+	// which includes the keys of its matrix block if it has one.
+	testFilename := file.Config.Filename
+	if testFilename == "" {
+		testFilename = file.Name
+	}
+	filename := filepath.Join(filepath.Dir(testFilename), filepath.FromSlash(run.Config.ExpectPlanSnapshot))
+	subject := run.Config.ExpectPlanSnapshotDeclRange.Ptr()
+
+	schemas, diags := tfCtx.Schemas(ctx, config, plan.PlannedState)
+	if diags.HasErrors() {
+		run.Diagnostics = run.Diagnostics.Append(diags)
+		run.Status = run.Status.Merge(moduletest.Error)
+		return
+	}
+
+	got, err := testsnapshot.Plan(plan, schemas)
+	if err != nil {
+		run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to create plan snapshot",
+			Detail:   fmt.Sprintf("OpenTofu could not create a snapshot of the plan for %s: %s.", path.Join(file.Name, run.Name), err),
+			Subject:  subject,
+		})
+		run.Status = run.Status.Merge(moduletest.Error)
+		return
+	}
+
+	if runner.Suite.UpdateSnapshots {
+		if want, err := os.ReadFile(filename); err == nil && testsnapshot.Diff(want, got) == "" {
+			return // Nothing to update.
+		}
+		err := os.MkdirAll(filepath.Dir(filename), 0755)
+		if err == nil {
+			err = os.WriteFile(filename, got, 0644)
+		}
+		if err != nil {
+			run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to write plan snapshot",
+				Detail:   fmt.Sprintf("OpenTofu could not write the plan snapshot to %s: %s.", filename, err),
+				Subject:  subject,
+			})
+			run.Status = run.Status.Merge(moduletest.Error)
+			return
+		}
+		log.Printf("[INFO] TestFileRunner: updated plan snapshot %s for %s", filename, path.Join(file.Name, run.Name))
+		return
+	}
+
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		detail := fmt.Sprintf("OpenTofu could not read the plan snapshot from %s: %s.", filename, err)
+		if os.IsNotExist(err) {
+			detail = fmt.Sprintf("The plan snapshot file %s does not exist. Run \"tofu test -update-snapshots\" to create it.", filename)
+		}
+		run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing plan snapshot",
+			Detail:   detail,
+			Subject:  subject,
+		})
+		run.Status = run.Status.Merge(moduletest.Fail)
+		return
+	}
+
+	if diff := testsnapshot.Diff(want, got); diff != "" {
+		run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Plan does not match snapshot",
+			Detail:   fmt.Sprintf("The plan differs from the snapshot in %s. If this change is expected, run \"tofu test -update-snapshots\" to update the snapshot.\n\n%s", filename, diff),
+			Subject:  subject,
+		})
+		run.Status = run.Status.Merge(moduletest.Fail)
+	}
+}
+
 func (runner *TestFileRunner) validate(ctx context.Context, config *configs.Config, run *moduletest.Run, file *moduletest.File) tfdiags.Diagnostics {
 	log.Printf("[TRACE] TestFileRunner: called validate for %s/%s", file.Name, run.Name)
 
@@ -810,7 +1335,7 @@ func (runner *TestFileRunner) plan(ctx context.Context, config *configs.Config,
 	references, referenceDiags := run.GetReferences()
 	diags = diags.Append(referenceDiags)
 
-	evalCtx, ctxDiags := getEvalContextForTest(runner.States, config, runner.Suite.GlobalVariables)
+	evalCtx, ctxDiags := getEvalContextForTest(runner.States, run, config, runner.Suite.GlobalVariables)
 	diags = diags.Append(ctxDiags)
 
 	variables, variableDiags := buildInputVariablesForTest(run, file, config, runner.Suite.GlobalVariables, evalCtx)
@@ -949,7 +1474,7 @@ func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context
 			}
 			states[module.Run] = module.State
 		}
-		runner.Suite.View.FatalInterruptSummary(run, file, states, created)
+		runner.View.FatalInterruptSummary(run, file, states, created)
 
 		cancelled = true
 		go ctx.Stop()
@@ -1022,7 +1547,7 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 
 			var diags tfdiags.Diagnostics
 			diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, "Inconsistent state", fmt.Sprintf("Found inconsistent state while cleaning up %s. This is a bug in OpenTofu - please report it", file.Name)))
-			runner.Suite.View.DestroySummary(diags, nil, file, state.State)
+			runner.View.DestroySummary(diags, nil, file, state.State)
 			continue
 		}
 
@@ -1046,37 +1571,181 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 			return
 		}
 
-		var diags tfdiags.Diagnostics
-		var runConfig *configs.Config
+		if file.Config.Cleanup != nil && file.Config.Cleanup.SkipCleanup {
+			if !state.State.HasManagedResourceInstanceObjects() {
+				continue
+			}
 
-		isMainState := state.Run.Config.Module == nil
-		if isMainState {
-			runConfig = runner.Suite.Config
-		} else {
-			runConfig = state.Run.Config.ConfigUnderTest
+			var diags tfdiags.Diagnostics
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Cleanup skipped",
+				fmt.Sprintf("The cleanup block of %s sets skip_cleanup, so the infrastructure created by run block %q has not been destroyed. Run tofu test -cleanup-leftovers to destroy it.", file.Name, state.Run.Name)))
+			runner.View.DestroySummary(diags, state.Run, file, state.State)
+			runner.saveLeftoverState(state.State, state.Run, file)
+			continue
 		}
 
-		evalCtx, evalDiags := buildEvalContextForProviderConfigTransform(runner.States, state.Run, file, runConfig, runner.Suite.GlobalVariables)
-		if evalDiags.HasErrors() {
-			return
+		updated, diags := runner.cleanupState(ctx, state, file)
+		runner.View.DestroySummary(diags, state.Run, file, updated)
+
+		if updated.HasManagedResourceInstanceObjects() {
+			runner.saveLeftoverState(updated, state.Run, file)
+		}
+	}
+}
+
+// cleanupState destroys the given state, retrying the destroy operation as
+// configured by the cleanup block of the test file. The delay between retries
+// doubles after each attempt.
+func (runner *TestFileRunner) cleanupState(ctx context.Context, state *TestFileState, file *moduletest.File) (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var runConfig *configs.Config
+
+	isMainState := state.Run.Config.Module == nil
+	if isMainState {
+		runConfig = runner.Config
+	} else {
+		runConfig = state.Run.Config.ConfigUnderTest
+	}
+
+	evalCtx, evalDiags := buildEvalContextForProviderConfigTransform(runner.States, state.Run, file, runConfig, runner.Suite.GlobalVariables)
+	if evalDiags.HasErrors() {
+		return state.State, diags.Append(evalDiags)
+	}
+
+	reset, configDiags := runConfig.TransformForTest(state.Run.Config, file.Config, evalCtx)
+	defer reset()
+	diags = diags.Append(configDiags)
+	if diags.HasErrors() {
+		return state.State, diags
+	}
+
+	retries, delay := 0, configs.DefaultTestCleanupRetryDelay
+	if file.Config.Cleanup != nil {
+		retries, delay = file.Config.Cleanup.Retries, file.Config.Cleanup.RetryDelay
+	}
+
+	updated := state.State
+	for attempt := 0; ; attempt++ {
+		var destroyDiags tfdiags.Diagnostics
+		updated, destroyDiags = runner.destroy(ctx, runConfig, updated, state.Run, file)
+		if !destroyDiags.HasErrors() || attempt >= retries || runner.Suite.Stopped || runner.Suite.Cancelled {
+			return updated, diags.Append(destroyDiags)
+		}
+
+		log.Printf("[DEBUG] TestFileRunner: cleanup of %s/%s failed, retrying in %s (%d of %d)", file.Name, state.Run.Name, delay, attempt+1, retries)
+		select {
+		case <-time.After(delay):
+		case <-runner.Suite.StoppedCtx.Done():
+			return updated, diags.Append(destroyDiags)
+		}
+		delay *= 2
+	}
+}
+
+// saveLeftoverState writes a state that could not be destroyed into the
+// errored_test.tfstate file, and records it so that a later execution of
+// tofu test -cleanup-leftovers can destroy it.
+func (runner *TestFileRunner) saveLeftoverState(state *states.State, run *moduletest.Run, file *moduletest.File) {
+	views.SaveErroredTestStateFile(state, run, file, runner.View)
+
+	if runner.Suite.Leftovers == nil {
+		return
+	}
+	if err := runner.Suite.Leftovers.Record(file.Name, run.Name, state); err != nil {
+		var diags tfdiags.Diagnostics
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to record leftover state",
+			fmt.Sprintf("The state left behind by run block %q in %s could not be recorded for tofu test -cleanup-leftovers: %s.", run.Name, file.Name, err)))
+		runner.View.Diagnostics(run, file, diags)
+	}
+}
+
+// CleanupLeftovers destroys the states recorded by previous executions of
+// tofu test that could not be destroyed at the time, or were kept because
+// the test file sets skip_cleanup. States that are destroyed are removed
+// from the record, and the others are kept for the next attempt.
+func (runner *TestSuiteRunner) CleanupLeftovers(ctx context.Context) (cleaned, remaining int) {
+	for _, entry := range runner.Leftovers.Entries() {
+		if runner.Cancelled {
+			return cleaned, remaining + 1
 		}
 
-		reset, configDiags := runConfig.TransformForTest(state.Run.Config, file.Config, evalCtx)
-		diags = diags.Append(configDiags)
+		file, run, diags := runner.leftoverRun(entry)
+		fileRunner := runner.newFileRunner(runner.Config, runner.View)
 
-		updated := state.State
+		updated := states.NewState()
 		if !diags.HasErrors() {
-			var destroyDiags tfdiags.Diagnostics
-			updated, destroyDiags = runner.destroy(ctx, runConfig, state.State, state.Run, file)
-			diags = diags.Append(destroyDiags)
+			state, err := runner.Leftovers.ReadState(entry)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to read leftover state",
+					fmt.Sprintf("The leftover state %s could not be read: %s.", entry.State, err)))
+			} else {
+				fileState := &TestFileState{Run: run, State: state}
+				fileRunner.States[runStateKey(run)] = fileState
+
+				var cleanupDiags tfdiags.Diagnostics
+				updated, cleanupDiags = fileRunner.cleanupState(ctx, fileState, file)
+				diags = diags.Append(cleanupDiags)
+			}
+		}
+		runner.View.DestroySummary(diags, run, file, updated)
+
+		var err error
+		switch {
+		case diags.HasErrors() && !updated.HasManagedResourceInstanceObjects():
+			// We couldn't even try to destroy the state, so we'll keep it
+			// exactly as it was.
+			remaining++
+			continue
+		case updated.HasManagedResourceInstanceObjects():
+			remaining++
+			err = runner.Leftovers.Update(entry, updated)
+		default:
+			cleaned++
+			err = runner.Leftovers.Remove(entry)
+		}
+		if err != nil {
+			runner.View.Diagnostics(run, file, tfdiags.Diagnostics{}.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to update leftover state",
+				fmt.Sprintf("The record of the leftover state %s could not be updated: %s.", entry.State, err))))
 		}
-		runner.Suite.View.DestroySummary(diags, state.Run, file, updated)
+	}
+	return cleaned, remaining
+}
 
-		if updated.HasManagedResourceInstanceObjects() {
-			views.SaveErroredTestStateFile(updated, state.Run, file, runner.Suite.View)
+// leftoverRun finds the test file and run block that a leftover state was
+// created by, so their configuration can be used to destroy it.
+func (runner *TestSuiteRunner) leftoverRun(entry testLeftover) (*moduletest.File, *moduletest.Run, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	file := &moduletest.File{Name: entry.File}
+	config, exists := runner.Config.Module.Tests[entry.File]
+	if !exists {
+		file.Config = &configs.TestFile{}
+		return file, nil, diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unknown test file for leftover state",
+			fmt.Sprintf("The leftover state %s was created by %s, which no longer exists. Restore the test file, or destroy the infrastructure manually.", entry.State, entry.File)))
+	}
+	file.Config = config
+
+	for ix, run := range config.Runs {
+		if run.Name == entry.Run {
+			moduleRun := &moduletest.Run{Config: run, Index: ix, Name: run.Name}
+			file.Runs = append(file.Runs, moduleRun)
+			return file, moduleRun, diags
 		}
-		reset()
 	}
+	return file, nil, diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Unknown run block for leftover state",
+		fmt.Sprintf("The leftover state %s was created by run block %q in %s, which no longer exists. Restore the run block, or destroy the infrastructure manually.", entry.State, entry.Run, entry.File)))
 }
 
 // helper functions
@@ -1092,7 +1761,7 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 //
 // The evalCtx returned from this, contains built-in functions for the same reason.
 func buildEvalContextForProviderConfigTransform(states map[string]*TestFileState, run *moduletest.Run, file *moduletest.File, config *configs.Config, globals map[string]backend.UnparsedVariableValue) (*hcl.EvalContext, tfdiags.Diagnostics) {
-	evalCtx, diags := getEvalContextForTest(states, config, globals)
+	evalCtx, diags := getEvalContextForTest(states, run, config, globals)
 	vars, varDiags := buildInputVariablesForTest(run, file, config, globals, evalCtx)
 	diags = diags.Append(varDiags)
 	if diags.HasErrors() {
@@ -1165,23 +1834,41 @@ func buildInputVariablesForTest(run *moduletest.Run, file *moduletest.File, conf
 	return backend.ParseVariableValues(variables, config.Module.Variables)
 }
 
+// rootOutputValues returns the output values of the root module within the
+// given state as an object, as referred to by later run blocks.
+func rootOutputValues(state *states.State) cty.Value {
+	outputs := make(map[string]cty.Value)
+	mod := state.Modules[""] // Empty string is what is used by the module in the test runner
+	for outName, out := range mod.OutputValues {
+		outputs[outName] = out.Value
+	}
+	return cty.ObjectVal(outputs)
+}
+
 // getEvalContextForTest constructs an hcl.EvalContext based on the provided map of
-// TestFileState instances, configuration and global variables.
+// TestFileState instances, run block, configuration and global variables.
 // It extracts the relevant information from the input parameters to create a
 // context suitable for HCL evaluation.
-func getEvalContextForTest(states map[string]*TestFileState, config *configs.Config, globals map[string]backend.UnparsedVariableValue) (*hcl.EvalContext, tfdiags.Diagnostics) {
+func getEvalContextForTest(states map[string]*TestFileState, run *moduletest.Run, config *configs.Config, globals map[string]backend.UnparsedVariableValue) (*hcl.EvalContext, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	runCtx := make(map[string]cty.Value)
+	// Run blocks expanded from for_each are grouped under the name of the
+	// original run block, keyed by each.key, as for resources.
+	expanded := make(map[string]map[string]cty.Value)
 	for _, state := range states {
-		if state.Run == nil {
-			continue
+		for name, instances := range state.Expanded {
+			if expanded[name] == nil {
+				expanded[name] = make(map[string]cty.Value)
+			}
+			maps.Copy(expanded[name], instances)
 		}
-		outputs := make(map[string]cty.Value)
-		mod := state.State.Modules[""] // Empty string is what is used by the module in the test runner
-		for outName, out := range mod.OutputValues {
-			outputs[outName] = out.Value
+		if state.Run == nil || state.Run.Config.ForEach != nil {
+			continue
 		}
-		runCtx[state.Run.Name] = cty.ObjectVal(outputs)
+		runCtx[state.Run.Name] = rootOutputValues(state.State)
+	}
+	for name, instances := range expanded {
+		runCtx[name] = cty.ObjectVal(instances)
 	}
 
 	// If the variable is referenced in the tfvars file or TF_VAR_ environment variable, then lookup the value
@@ -1202,6 +1889,15 @@ func getEvalContextForTest(states map[string]*TestFileState, config *configs.Con
 		},
 		Functions: scope.Functions(),
 	}
+
+	// Run blocks expanded from for_each or a matrix block can also refer to
+	// their own key and value.
+	if run != nil && run.Config.Each.EachKey != cty.NilVal {
+		ctx.Variables["each"] = cty.ObjectVal(map[string]cty.Value{
+			"key":   run.Config.Each.EachKey,
+			"value": run.Config.Each.EachValue,
+		})
+	}
 	return ctx, diags
 }
 
@@ -1284,7 +1980,7 @@ func parseAndApplyDefaultValues(unparsedVariables map[string]backend.UnparsedVar
 // the config which must be called so the config can be reused going forward.
 func (runner *TestFileRunner) prepareInputVariablesForAssertions(config *configs.Config, run *moduletest.Run, file *moduletest.File, globals map[string]backend.UnparsedVariableValue) (tofu.InputValues, func(), tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	ctx, ctxDiags := getEvalContextForTest(runner.States, config, globals)
+	ctx, ctxDiags := getEvalContextForTest(runner.States, run, config, globals)
 	diags = diags.Append(ctxDiags)
 
 	variables := make(map[string]backend.UnparsedVariableValue)
@@ -0,0 +1,165 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+const (
+	// testLeftoversDir is the directory, within the data directory, where
+	// tofu test records the states that it did not destroy.
+	testLeftoversDir = "test-leftovers"
+
+	// testLeftoversManifest is the name of the file within testLeftoversDir
+	// that lists the recorded states.
+	testLeftoversManifest = "leftovers.json"
+)
+
+// testLeftover describes a state that was left behind by a test file,
+// either because destroying it failed or because the test file sets
+// skip_cleanup.
+type testLeftover struct {
+	// File and Run identify the test file and the run block whose
+	// configuration should be used to destroy the state.
+	File string `json:"file"`
+	Run  string `json:"run"`
+
+	// State is the name of the state file, within the leftovers directory.
+	State string `json:"state"`
+}
+
+// testLeftovers is the record of leftover states kept in the data directory,
+// so that a later execution of tofu test -cleanup-leftovers can destroy
+// them. It is safe for concurrent use.
+type testLeftovers struct {
+	dir string
+
+	mu      sync.Mutex
+	entries []testLeftover
+}
+
+// loadTestLeftovers reads the record of leftover states from the given
+// directory. The record is empty if the directory does not exist.
+func loadTestLeftovers(dir string) (*testLeftovers, error) {
+	leftovers := &testLeftovers{dir: dir}
+
+	src, err := os.ReadFile(filepath.Join(dir, testLeftoversManifest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return leftovers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(src, &leftovers.entries); err != nil {
+		return nil, fmt.Errorf("invalid leftovers manifest: %w", err)
+	}
+	return leftovers, nil
+}
+
+// Entries returns a copy of the recorded leftover states.
+func (l *testLeftovers) Entries() []testLeftover {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.entries)
+}
+
+// Record writes the given state into the leftovers directory, and adds it to
+// the record under the given test file and run block.
+func (l *testLeftovers) Record(file, run string, state *states.State) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return err
+	}
+
+	entry := testLeftover{File: file, Run: run}
+	for n := len(l.entries); ; n++ {
+		entry.State = fmt.Sprintf("leftover-%d.tfstate", n)
+		if !slices.ContainsFunc(l.entries, func(e testLeftover) bool { return e.State == entry.State }) {
+			break
+		}
+	}
+	if err := l.writeState(entry, state); err != nil {
+		return err
+	}
+
+	l.entries = append(l.entries, entry)
+	return l.save()
+}
+
+// ReadState reads the state recorded by the given entry.
+func (l *testLeftovers) ReadState(entry testLeftover) (*states.State, error) {
+	f, err := os.Open(filepath.Join(l.dir, entry.State))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sf, err := statefile.Read(f, encryption.StateEncryptionDisabled())
+	if err != nil {
+		return nil, err
+	}
+	return sf.State, nil
+}
+
+// Update replaces the state recorded by the given entry, after some but not
+// all of its resources were destroyed.
+func (l *testLeftovers) Update(entry testLeftover, state *states.State) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.writeState(entry, state)
+}
+
+// Remove deletes the state recorded by the given entry, once all of its
+// resources have been destroyed. The leftovers directory is removed once it
+// no longer records any states.
+func (l *testLeftovers) Remove(entry testLeftover) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = slices.DeleteFunc(l.entries, func(e testLeftover) bool { return e == entry })
+	if err := os.Remove(filepath.Join(l.dir, entry.State)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return l.save()
+}
+
+func (l *testLeftovers) writeState(entry testLeftover, state *states.State) error {
+	f, err := os.Create(filepath.Join(l.dir, entry.State))
+	if err != nil {
+		return err
+	}
+	if err := statefile.Write(statefile.New(state, "", 0), f, encryption.StateEncryptionDisabled()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (l *testLeftovers) save() error {
+	if len(l.entries) == 0 {
+		return os.RemoveAll(l.dir)
+	}
+
+	src, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(l.dir, testLeftoversManifest), append(src, '\n'), 0644)
+}
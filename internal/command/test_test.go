@@ -7,19 +7,24 @@ package command
 
 import (
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/opentofu/opentofu/internal/command/workdir"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	testing_command "github.com/opentofu/opentofu/internal/command/testing"
 	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/moduletest"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/terminal"
 )
@@ -112,6 +117,44 @@ func TestTest(t *testing.T) {
 			expected: "2 passed, 0 failed",
 			code:     0,
 		},
+		"multiple_files_parallel": {
+			override: "multiple_files",
+			args:     []string{"-parallelism=2"},
+			expected: "2 passed, 0 failed",
+			code:     0,
+		},
+		"run_for_each": {
+			args:     []string{"-no-color"},
+			expected: "main.tftest.hcl... pass\n  run \"validate[first]\"... pass\n  run \"validate[second]\"... pass\n\nSuccess! 2 passed, 0 failed.",
+			code:     0,
+		},
+		"matrix": {
+			args:     []string{"-no-color"},
+			expected: "main.tftest.hcl[name=a,size=1]... pass\n  run \"validate\"... pass\nmain.tftest.hcl[name=a,size=2]... pass\n  run \"validate\"... pass\nmain.tftest.hcl[name=b,size=1]... pass\n  run \"validate\"... pass\nmain.tftest.hcl[name=b,size=2]... pass\n  run \"validate\"... pass\n\nSuccess! 4 passed, 0 failed.",
+			code:     0,
+		},
+		"matrix_filter": {
+			override: "matrix",
+			args:     []string{"-filter=main.tftest.hcl"},
+			expected: "4 passed, 0 failed.",
+			code:     0,
+		},
+		"mock_ephemeral": {
+			expected: "2 passed, 0 failed",
+			code:     0,
+		},
+		"mock_generators": {
+			expected: "2 passed, 0 failed",
+			code:     0,
+		},
+		"expect_diagnostics": {
+			expected: "2 passed, 0 failed",
+			code:     0,
+		},
+		"expect_diagnostics_missing": {
+			expected: "0 passed, 1 failed",
+			code:     1,
+		},
 		"multiple_files_with_filter": {
 			override: "multiple_files",
 			args:     []string{"-filter=one.tftest.hcl"},
@@ -600,6 +643,476 @@ variable.
 	}
 }
 
+func TestTest_Reports(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "simple_fail")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-junit-xml=results.xml", "-tap=results.tap", "-no-color"})
+	output := done(t)
+
+	if code != 1 {
+		t.Errorf("expected status code 1 but got %d\n%s", code, output.All())
+	}
+
+	junit, err := os.ReadFile("results.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<testsuites name="tofu test" tests="1" failures="1" errors="0" skipped="0"`,
+		`<testsuite name="main.tftest.hcl" tests="1" failures="1" errors="0" skipped="0"`,
+		`<testcase name="validate_test_resource" classname="main.tftest.hcl"`,
+		`<failure message="invalid value"><![CDATA[Error: Test assertion failed`,
+		`condition = test_resource.foo.value == "zap"`,
+	} {
+		if !strings.Contains(string(junit), want) {
+			t.Errorf("JUnit XML report does not contain %q\n%s", want, junit)
+		}
+	}
+
+	tap, err := os.ReadFile("results.tap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"TAP version 13\n1..1\nnot ok 1 - main.tftest.hcl/validate_test_resource\n",
+		`  message: "invalid value"`,
+	} {
+		if !strings.Contains(string(tap), want) {
+			t.Errorf("TAP report does not contain %q\n%s", want, tap)
+		}
+	}
+}
+
+func TestTest_Parallelism(t *testing.T) {
+	run := func(args ...string) string {
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath(path.Join("test", "multiple_files")), td)
+		t.Chdir(td)
+
+		provider := testing_command.NewProvider(nil)
+		view, done := testView(t)
+
+		c := &TestCommand{
+			Meta: Meta{
+				WorkingDir:       workdir.NewDir("."),
+				testingOverrides: metaOverridesForProvider(provider.Provider),
+				View:             view,
+			},
+		}
+
+		code := c.Run(append(args, "-no-color"))
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("expected status code 0 but got %d\n%s", code, output.All())
+		}
+		if provider.ResourceCount() > 0 {
+			t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+		}
+		return output.All()
+	}
+
+	// The output should be identical regardless of how many files are
+	// executed at once.
+	want := run()
+	got := run("-parallelism=2")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+}
+
+func TestTest_PlanSnapshot(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "plan_snapshot")), td)
+	t.Chdir(td)
+
+	run := func(args ...string) (int, string) {
+		provider := testing_command.NewProvider(nil)
+		view, done := testView(t)
+
+		c := &TestCommand{
+			Meta: Meta{
+				WorkingDir:       workdir.NewDir("."),
+				testingOverrides: metaOverridesForProvider(provider.Provider),
+				View:             view,
+			},
+		}
+
+		code := c.Run(append(args, "-no-color"))
+		output := done(t)
+		return code, output.All()
+	}
+
+	snapshot := filepath.Join("snapshots", "plan.json")
+	want, err := os.ReadFile(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The committed snapshot matches the plan.
+	if code, output := run(); code != 0 {
+		t.Fatalf("expected status code 0 but got %d\n%s", code, output)
+	}
+
+	// A changed plan no longer matches the snapshot.
+	code, output := run("-var=value=baz")
+	if code != 1 {
+		t.Fatalf("expected status code 1 but got %d\n%s", code, output)
+	}
+	for _, msg := range []string{
+		"Error: Plan does not match snapshot",
+		`-        "value": "bar"`,
+		`+        "value": "baz"`,
+	} {
+		if !strings.Contains(output, msg) {
+			t.Errorf("expected output to contain %q\n%s", msg, output)
+		}
+	}
+
+	// Updating the snapshots rewrites the file to match the new plan.
+	if code, output := run("-var=value=baz", "-update-snapshots"); code != 0 {
+		t.Fatalf("expected status code 0 but got %d\n%s", code, output)
+	}
+	got, err := os.ReadFile(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(strings.ReplaceAll(string(want), `"bar"`, `"baz"`), string(got)); diff != "" {
+		t.Errorf("wrong snapshot\n%s", diff)
+	}
+	if code, output := run("-var=value=baz"); code != 0 {
+		t.Fatalf("expected status code 0 but got %d\n%s", code, output)
+	}
+
+	// A missing snapshot is reported as a failure.
+	if err := os.Remove(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	code, output = run()
+	if code != 1 {
+		t.Fatalf("expected status code 1 but got %d\n%s", code, output)
+	}
+	if !strings.Contains(output, "Error: Missing plan snapshot") {
+		t.Errorf("expected missing snapshot error\n%s", output)
+	}
+}
+
+func TestTest_Coverage(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "coverage")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-coverage", "-coverage-json=coverage.json", "-coverage-lcov=coverage.info", "-no-color"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d\n%s", code, output.All())
+	}
+
+	want := `main.tftest.hcl... pass
+  run "enabled"... pass
+
+Success! 1 passed, 0 failed.
+
+Coverage: 8 of 11 items exercised (72.7%).
+  resources                2 of 2
+  data sources             0 of 1
+  outputs                  1 of 1
+  variable validations     1 of 1
+  check blocks             1 of 1
+  postconditions           1 of 1
+  count/for_each branches  2 of 4
+
+Not exercised:
+  - main.tf:27: test_resource.optional (count = 0)
+  - main.tf:32: data.test_data_source.unused
+  - main.tf:32: data.test_data_source.unused (count > 0)
+`
+	if diff := cmp.Diff(want, output.Stdout()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+
+	lcov, err := os.ReadFile("coverage.info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLCOV := `TN:
+SF:main.tf
+BRDA:27,0,0,-
+BRDA:27,0,1,1
+BRDA:32,1,0,1
+BRDA:32,1,1,-
+BRF:4
+BRH:2
+DA:10,1
+DA:16,1
+DA:20,1
+DA:27,1
+DA:32,0
+DA:37,1
+DA:44,1
+LF:7
+LH:6
+end_of_record
+`
+	if diff := cmp.Diff(wantLCOV, string(lcov)); diff != "" {
+		t.Errorf("wrong LCOV report\n%s", diff)
+	}
+
+	if _, err := os.Stat("coverage.json"); err != nil {
+		t.Errorf("expected JSON coverage report: %s", err)
+	}
+}
+
+func TestTest_CleanupLeftovers(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "cleanup_skip")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+
+	run := func(args ...string) (int, string) {
+		view, done := testView(t)
+		c := &TestCommand{
+			Meta: Meta{
+				WorkingDir:       workdir.NewDir("."),
+				testingOverrides: metaOverridesForProvider(provider.Provider),
+				View:             view,
+			},
+		}
+		code := c.Run(append(args, "-no-color"))
+		return code, done(t).All()
+	}
+
+	code, output := run()
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d\n%s", code, output)
+	}
+	if !strings.Contains(output, "Warning: Cleanup skipped") {
+		t.Errorf("expected the skipped cleanup to be reported\n%s", output)
+	}
+	if provider.ResourceCount() != 1 {
+		t.Fatalf("expected the resource to be left behind but found %v", provider.ResourceString())
+	}
+	leftovers := filepath.Join(workdir.DefaultDataDir, testLeftoversDir)
+	if _, err := os.Stat(filepath.Join(leftovers, testLeftoversManifest)); err != nil {
+		t.Fatalf("expected the leftover state to be recorded: %s", err)
+	}
+
+	code, output = run("-cleanup-leftovers")
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d\n%s", code, output)
+	}
+	if !strings.Contains(output, "Success! Destroyed the resources of 1 leftover state.") {
+		t.Errorf("wrong output\n%s", output)
+	}
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources but left %v", provider.ResourceString())
+	}
+	if _, err := os.Stat(leftovers); !os.IsNotExist(err) {
+		t.Errorf("expected the leftovers directory to be removed, but got %v", err)
+	}
+
+	code, output = run("-cleanup-leftovers")
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d\n%s", code, output)
+	}
+	if !strings.Contains(output, "No leftover test infrastructure to clean up.") {
+		t.Errorf("wrong output\n%s", output)
+	}
+}
+
+func TestTest_CleanupRetries(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "cleanup_retries")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	// The first attempt to destroy the resource fails, as it might if the
+	// remote API was eventually consistent.
+	failures := 1
+	apply := provider.Provider.ApplyResourceChangeFn
+	provider.Provider.ApplyResourceChangeFn = func(request providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		if request.PlannedState.IsNull() && failures > 0 {
+			failures--
+			var resp providers.ApplyResourceChangeResponse
+			resp.NewState = request.PriorState
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("resource is still in use"))
+			return resp
+		}
+		return apply(request)
+	}
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d\n%s", code, output.All())
+	}
+	if failures != 0 {
+		t.Errorf("expected the destroy operation to be attempted")
+	}
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+	if _, err := os.Stat("errored_test.tfstate"); !os.IsNotExist(err) {
+		t.Errorf("expected no errored_test.tfstate, but got %v", err)
+	}
+}
+
+func TestRunGroup(t *testing.T) {
+	module := func(source string) *configs.TestRunModuleCall {
+		return &configs.TestRunModuleCall{Source: addrs.ModuleSourceLocal(source)}
+	}
+	run := func(name string, parallel bool, mod *configs.TestRunModuleCall, vars map[string]string) *moduletest.Run {
+		cfg := &configs.TestRun{
+			Name:      name,
+			Parallel:  parallel,
+			Module:    mod,
+			Variables: make(map[string]hcl.Expression),
+		}
+		if mod != nil {
+			cfg.ConfigUnderTest = &configs.Config{}
+		}
+		for name, src := range vars {
+			expr, diags := hclsyntax.ParseExpression([]byte(src), "", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			cfg.Variables[name] = expr
+		}
+		return &moduletest.Run{Name: name, Config: cfg}
+	}
+
+	file := &moduletest.File{
+		Config: &configs.TestFile{},
+		Runs: []*moduletest.Run{
+			run("first", false, nil, nil),
+			run("main", true, nil, nil),
+			run("setup", true, module("./setup"), nil),
+			run("same_state", true, module("./setup"), nil),
+			run("other", true, module("./other"), nil),
+			run("dependent", true, module("./verify"), map[string]string{"value": "run.other.value"}),
+			run("last", false, nil, nil),
+		},
+	}
+
+	var got [][]string
+	for ix := 0; ix < len(file.Runs); {
+		var names []string
+		for _, run := range runGroup(file, ix) {
+			names = append(names, run.Name)
+		}
+		got = append(got, names)
+		ix += len(names)
+	}
+
+	want := [][]string{
+		{"first"},
+		{"main", "setup"},
+		{"same_state", "other"},
+		{"dependent"},
+		{"last"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong groups\n%s", diff)
+	}
+}
+
+func TestRunGroup_references(t *testing.T) {
+	module := func(source string) *configs.TestRunModuleCall {
+		return &configs.TestRunModuleCall{Source: addrs.ModuleSourceLocal(source)}
+	}
+	parse := func(src string) hcl.Expression {
+		expr, diags := hclsyntax.ParseExpression([]byte(src), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return expr
+	}
+	runs := func(verify map[string]hcl.Expression) []*moduletest.Run {
+		return []*moduletest.Run{
+			{Name: "setup", Config: &configs.TestRun{Name: "setup", BaseName: "setup", Parallel: true, Module: module("./setup"), ConfigUnderTest: &configs.Config{}}},
+			{Name: "verify", Config: &configs.TestRun{Name: "verify", BaseName: "verify", Parallel: true, Module: module("./verify"), ConfigUnderTest: &configs.Config{}, Variables: verify}},
+		}
+	}
+
+	tcs := map[string]struct {
+		file *moduletest.File
+		want int
+	}{
+		"independent": {
+			file: &moduletest.File{
+				Config: &configs.TestFile{},
+				Runs:   runs(map[string]hcl.Expression{"value": parse(`"static"`)}),
+			},
+			want: 2,
+		},
+		"index": {
+			file: &moduletest.File{
+				Config: &configs.TestFile{},
+				Runs:   runs(map[string]hcl.Expression{"value": parse(`run["setup"].value`)}),
+			},
+			want: 1,
+		},
+		"provider": {
+			file: func() *moduletest.File {
+				body, diags := hclsyntax.ParseConfig([]byte("value = run.setup.value\n"), "main.tftest.hcl", hcl.InitialPos)
+				if diags.HasErrors() {
+					t.Fatal(diags.Error())
+				}
+				return &moduletest.File{
+					Config: &configs.TestFile{
+						Providers: map[string]*configs.Provider{
+							"test": {Name: "test", Config: body.Body},
+						},
+					},
+					Runs: runs(nil),
+				}
+			}(),
+			want: 1,
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			if got := len(runGroup(tc.file, 0)); got != tc.want {
+				t.Errorf("expected %d run blocks in the group but got %d", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestTest_Verbose(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath(path.Join("test", "plan_then_apply")), td)
@@ -837,6 +1350,10 @@ func TestTest_Modules(t *testing.T) {
 			expected: "main.tftest.hcl... pass\n  run \"test\"... pass\n  run \"verify\"... pass\n\nSuccess! 2 passed, 0 failed.\n",
 			code:     0,
 		},
+		"parallel_runs": {
+			expected: "main.tftest.hcl... pass\n  run \"main\"... pass\n  run \"setup\"... pass\n  run \"verify\"... pass\n\nSuccess! 3 passed, 0 failed.\n",
+			code:     0,
+		},
 		"only_modules": {
 			expected: "main.tftest.hcl... pass\n  run \"first\"... pass\n  run \"second\"... pass\n\nSuccess! 2 passed, 0 failed.\n",
 			code:     0,
@@ -897,6 +1414,10 @@ func TestTest_Modules(t *testing.T) {
 		"run_mod_output_in_provider_undefined_ref": {
 			code: 1,
 		},
+		"run_for_each_reference": {
+			expected: "main.tftest.hcl... pass\n  run \"setup[first]\"... pass\n  run \"setup[second]\"... pass\n  run \"check\"... pass\n\nSuccess! 3 passed, 0 failed.\n",
+			code:     0,
+		},
 	}
 
 	for name, tc := range tcs {
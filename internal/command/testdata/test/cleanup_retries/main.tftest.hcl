@@ -0,0 +1,11 @@
+cleanup {
+  retries     = 2
+  retry_delay = "1ms"
+}
+
+run "validate_test_resource" {
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
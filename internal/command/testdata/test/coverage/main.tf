@@ -0,0 +1,46 @@
+variable "enabled" {
+  type    = bool
+  default = true
+}
+
+variable "value" {
+  type    = string
+  default = "bar"
+
+  validation {
+    condition     = length(var.value) > 0
+    error_message = "The value must not be empty."
+  }
+}
+
+resource "test_resource" "foo" {
+  value = var.value
+
+  lifecycle {
+    postcondition {
+      condition     = self.value == var.value
+      error_message = "The resource has the wrong value."
+    }
+  }
+}
+
+resource "test_resource" "optional" {
+  count = var.enabled ? 1 : 0
+  value = "optional"
+}
+
+data "test_data_source" "unused" {
+  count = 0
+  id    = "unused"
+}
+
+check "value" {
+  assert {
+    condition     = test_resource.foo.value != ""
+    error_message = "The value is empty."
+  }
+}
+
+output "value" {
+  value = test_resource.foo.value
+}
@@ -0,0 +1,3 @@
+run "enabled" {
+  command = plan
+}
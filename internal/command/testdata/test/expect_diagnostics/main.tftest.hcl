@@ -0,0 +1,20 @@
+run "invalid" {
+  command = plan
+
+  variables {
+    input = "ab"
+  }
+
+  expect_diagnostics {
+    summary  = "^Invalid value for variable$"
+    detail   = "longer than three characters"
+    filename = "main.tf"
+    line     = 1
+  }
+}
+
+run "valid" {
+  variables {
+    input = "abcd"
+  }
+}
@@ -0,0 +1,12 @@
+variable "input" {
+  type = string
+
+  validation {
+    condition     = length(var.input) > 3
+    error_message = "The input must be longer than three characters."
+  }
+}
+
+resource "test_resource" "resource" {
+  value = var.input
+}
@@ -0,0 +1,10 @@
+run "valid" {
+  variables {
+    input = "abcd"
+  }
+
+  expect_diagnostics {
+    severity = warning
+    summary  = "deprecated"
+  }
+}
@@ -0,0 +1,11 @@
+variable "name" {
+  type = string
+}
+
+variable "size" {
+  type = number
+}
+
+resource "test_resource" "foo" {
+  value = "${var.name}-${var.size}"
+}
@@ -0,0 +1,16 @@
+matrix {
+  name = ["a", "b"]
+  size = [1, 2]
+}
+
+variables {
+  name = each.value.name
+  size = each.value.size
+}
+
+run "validate" {
+  assert {
+    condition     = test_resource.foo.value == "${each.value.name}-${each.value.size}"
+    error_message = "invalid value for ${each.key}"
+  }
+}
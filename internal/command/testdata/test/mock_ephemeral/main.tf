@@ -0,0 +1,14 @@
+variable "expected_token" {
+  type = string
+}
+
+ephemeral "test_ephemeral_resource" "token" {
+  id = "token"
+
+  lifecycle {
+    postcondition {
+      condition     = self.value == var.expected_token
+      error_message = "The token did not have the expected value."
+    }
+  }
+}
@@ -0,0 +1,26 @@
+mock_provider "test" {
+  mock_ephemeral "test_ephemeral_resource" {
+    defaults = {
+      value = "mocked"
+    }
+  }
+}
+
+run "mocked" {
+  variables {
+    expected_token = "mocked"
+  }
+}
+
+run "overridden" {
+  variables {
+    expected_token = "overridden"
+  }
+
+  override_ephemeral {
+    target = ephemeral.test_ephemeral_resource.token
+    values = {
+      value = "overridden"
+    }
+  }
+}
@@ -0,0 +1,11 @@
+variable "prefix" {
+  type = string
+}
+
+resource "test_resource" "a" {
+  count = 2
+}
+
+data "test_data_source" "x" {
+  id = "x"
+}
@@ -0,0 +1,38 @@
+variables {
+  prefix = "arn:test"
+}
+
+mock_provider "test" {
+  generators = {
+    id = format("res-%03d", mock.sequence)
+  }
+
+  mock_data "test_data_source" {
+    generators = {
+      value = "${var.prefix}:${mock.config.id}"
+    }
+  }
+}
+
+run "generated" {
+  command = plan
+
+  assert {
+    condition     = join(",", sort(test_resource.a[*].id)) == "res-000,res-001"
+    error_message = "invalid generated ids"
+  }
+
+  assert {
+    condition     = data.test_data_source.x.value == "arn:test:x"
+    error_message = "invalid generated value"
+  }
+}
+
+run "stable" {
+  command = plan
+
+  assert {
+    condition     = join(",", sort(test_resource.a[*].id)) == "res-000,res-001"
+    error_message = "generated ids changed between runs"
+  }
+}
@@ -0,0 +1,42 @@
+run "main" {
+  parallel = true
+
+  assert {
+    condition     = test_resource.main.value == "main"
+    error_message = "bad main value"
+  }
+}
+
+run "setup" {
+  parallel = true
+
+  module {
+    source = "./setup"
+  }
+
+  variables {
+    value = "setup"
+  }
+
+  assert {
+    condition     = output.value == "setup"
+    error_message = "bad setup value"
+  }
+}
+
+run "verify" {
+  parallel = true
+
+  module {
+    source = "./verify"
+  }
+
+  variables {
+    expected = run.setup.value
+  }
+
+  assert {
+    condition     = var.expected == "setup"
+    error_message = "bad verify value"
+  }
+}
@@ -0,0 +1,11 @@
+variable "value" {
+  type = string
+}
+
+resource "test_resource" "setup" {
+  value = var.value
+}
+
+output "value" {
+  value = test_resource.setup.value
+}
@@ -0,0 +1,7 @@
+variable "expected" {
+  type = string
+}
+
+output "expected" {
+  value = var.expected
+}
@@ -0,0 +1,23 @@
+variable "value" {
+  type    = string
+  default = "bar"
+}
+
+variable "secret" {
+  type      = string
+  sensitive = true
+  default   = "hunter2"
+}
+
+resource "test_resource" "foo" {
+  value = var.value
+}
+
+output "id" {
+  value = test_resource.foo.id
+}
+
+output "secret" {
+  value     = var.secret
+  sensitive = true
+}
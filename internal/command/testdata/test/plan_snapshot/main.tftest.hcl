@@ -0,0 +1,5 @@
+run "plan" {
+  command = plan
+
+  expect_plan_snapshot = "snapshots/plan.json"
+}
@@ -0,0 +1,15 @@
+run "validate" {
+  for_each = {
+    first  = "one"
+    second = "two"
+  }
+
+  variables {
+    input = each.value
+  }
+
+  assert {
+    condition     = test_resource.foo.value == each.value
+    error_message = "invalid value for ${each.key}"
+  }
+}
@@ -0,0 +1,25 @@
+run "setup" {
+  for_each = {
+    first  = "one"
+    second = "two"
+  }
+
+  module {
+    source = "./setup"
+  }
+
+  variables {
+    input = each.value
+  }
+}
+
+run "check" {
+  variables {
+    input = "${run.setup["first"].value}-${run.setup["second"].value}"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "one-two"
+    error_message = "invalid value"
+  }
+}
@@ -0,0 +1,11 @@
+variable "input" {
+  type = string
+}
+
+resource "test_resource" "setup" {
+  value = var.input
+}
+
+output "value" {
+  value = test_resource.setup.value
+}
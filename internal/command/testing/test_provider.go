@@ -65,7 +65,16 @@ var (
 				},
 			},
 		},
-		// TODO ephemeral testing support - when implementing testing support for ephemeral resources, consider configuring ephemeral schema here
+		EphemeralResources: map[string]providers.Schema{
+			"test_ephemeral_resource": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":    {Type: cty.String, Required: true},
+						"value": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
 	}
 )
 
@@ -0,0 +1,137 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testreport
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/moduletest"
+)
+
+type coverageReport struct {
+	Exercised int                          `json:"exercised"`
+	Total     int                          `json:"total"`
+	Items     []viewsjson.TestCoverageItem `json:"items"`
+}
+
+// WriteCoverageJSON writes the given coverage to the given writer as a JSON
+// document listing every item in the configuration under test, along with
+// the number of run blocks that exercised it.
+func WriteCoverageJSON(w io.Writer, coverage *moduletest.Coverage) error {
+	overall, _ := coverage.Totals()
+	report := coverageReport{
+		Exercised: overall.Exercised,
+		Total:     overall.Total,
+		Items:     []viewsjson.TestCoverageItem{},
+	}
+	for _, item := range coverage.Items() {
+		report.Items = append(report.Items, viewsjson.NewTestCoverageItem(item))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// WriteCoverageLCOV writes the given coverage to the given writer in the
+// LCOV tracefile format, so that it can be processed by the many existing
+// tools that understand it.
+//
+// Each item is reported as a line in the file that declares it, with the
+// number of run blocks that exercised it as the hit count. If more than one
+// item is declared on the same line then the lowest count is used, so that
+// the line is only covered once all of its items have been exercised. The
+// count and for_each branches are reported as LCOV branches instead.
+func WriteCoverageLCOV(w io.Writer, coverage *moduletest.Coverage) error {
+	type branch struct {
+		line, block, index, runs int
+	}
+	type file struct {
+		lines    map[int]int
+		blocks   map[string]int
+		branches []branch
+	}
+
+	files := make(map[string]*file)
+	var names []string
+	for _, item := range coverage.Items() {
+		name := item.DeclRange.Filename
+		f, exists := files[name]
+		if !exists {
+			f = &file{lines: make(map[int]int), blocks: make(map[string]int)}
+			files[name] = f
+			names = append(names, name)
+		}
+
+		line := item.DeclRange.Start.Line
+		if item.Kind == moduletest.CoverageBranch {
+			// Both branches of an object share a block number, which is
+			// assigned in the order the objects appear in the file.
+			block, exists := f.blocks[item.Address]
+			if !exists {
+				block = len(f.blocks)
+				f.blocks[item.Address] = block
+			}
+			index := 0
+			for _, b := range f.branches {
+				if b.block == block {
+					index++
+				}
+			}
+			f.branches = append(f.branches, branch{line, block, index, item.Runs})
+			continue
+		}
+
+		if runs, exists := f.lines[line]; !exists || item.Runs < runs {
+			f.lines[line] = item.Runs
+		}
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		f := files[name]
+		fmt.Fprintln(bw, "TN:")
+		fmt.Fprintf(bw, "SF:%s\n", name)
+
+		hit := 0
+		for _, b := range f.branches {
+			taken := "-"
+			if b.runs > 0 {
+				taken = fmt.Sprint(b.runs)
+				hit++
+			}
+			fmt.Fprintf(bw, "BRDA:%d,%d,%d,%s\n", b.line, b.block, b.index, taken)
+		}
+		if len(f.branches) > 0 {
+			fmt.Fprintf(bw, "BRF:%d\n", len(f.branches))
+			fmt.Fprintf(bw, "BRH:%d\n", hit)
+		}
+
+		lines := make([]int, 0, len(f.lines))
+		for line := range f.lines {
+			lines = append(lines, line)
+		}
+		sort.Ints(lines)
+
+		hit = 0
+		for _, line := range lines {
+			fmt.Fprintf(bw, "DA:%d,%d\n", line, f.lines[line])
+			if f.lines[line] > 0 {
+				hit++
+			}
+		}
+		fmt.Fprintf(bw, "LF:%d\n", len(lines))
+		fmt.Fprintf(bw, "LH:%d\n", hit)
+		fmt.Fprintln(bw, "end_of_record")
+	}
+	return bw.Flush()
+}
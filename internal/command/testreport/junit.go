@@ -0,0 +1,153 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testreport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+	SystemErr *junitText      `xml:"system-err,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemErr *junitText    `xml:"system-err,omitempty"`
+}
+
+// We use CDATA sections for the text content so that the rendered
+// diagnostics remain readable in the raw XML.
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Body    string `xml:",cdata"`
+}
+
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+func newJUnitText(text string) *junitText {
+	if text == "" {
+		return nil
+	}
+	return &junitText{Text: text}
+}
+
+// WriteJUnitXML writes the results of the given test suite to the given
+// writer in the JUnit XML format.
+//
+// Each test file becomes a "testsuite" element and each run block within
+// it becomes a "testcase" element. Failed assertions are reported as
+// failures, other errors are reported as errors, and any diagnostics are
+// included as the standard error output of the relevant element. Run blocks
+// that were not executed, such as because testing was interrupted, are
+// reported as skipped.
+//
+// The given sources are used to include source code snippets in the
+// rendered diagnostics, and may be nil.
+func WriteJUnitXML(w io.Writer, suite *moduletest.Suite, sources map[string]*hcl.File) error {
+	report := junitTestSuites{
+		Name: "tofu test",
+	}
+
+	var total time.Duration
+	for _, file := range sortedFiles(suite) {
+		ts := junitTestSuite{
+			Name:      file.Name,
+			Time:      junitDuration(file.Duration),
+			SystemErr: newJUnitText(renderDiagnostics(file.Diagnostics, 0, sources)),
+		}
+		for _, run := range file.Runs {
+			tc := junitTestCase{
+				Name:      run.Name,
+				Classname: file.Name,
+				Time:      junitDuration(run.Duration),
+			}
+			switch run.Status {
+			case moduletest.Pass:
+				tc.SystemErr = newJUnitText(renderDiagnostics(run.Diagnostics, 0, sources))
+			case moduletest.Fail:
+				ts.Failures++
+				tc.Failure = &junitMessage{
+					Message: failureMessage(run.Diagnostics),
+					Body:    renderDiagnostics(run.Diagnostics, tfdiags.Error, sources),
+				}
+				tc.SystemErr = newJUnitText(renderDiagnostics(run.Diagnostics, tfdiags.Warning, sources))
+			case moduletest.Error:
+				ts.Errors++
+				tc.Error = &junitMessage{
+					Message: failureMessage(run.Diagnostics),
+					Body:    renderDiagnostics(run.Diagnostics, tfdiags.Error, sources),
+				}
+				tc.SystemErr = newJUnitText(renderDiagnostics(run.Diagnostics, tfdiags.Warning, sources))
+			case moduletest.Skip:
+				ts.Skipped++
+				tc.Skipped = &junitMessage{}
+			default:
+				ts.Skipped++
+				tc.Skipped = &junitMessage{Message: "Testing was interrupted before this run block was executed."}
+			}
+			ts.TestCases = append(ts.TestCases, tc)
+		}
+		ts.Tests = len(ts.TestCases)
+
+		report.Tests += ts.Tests
+		report.Failures += ts.Failures
+		report.Errors += ts.Errors
+		report.Skipped += ts.Skipped
+		total += file.Duration
+		report.Suites = append(report.Suites, ts)
+	}
+	report.Time = junitDuration(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitDuration formats a duration as a number of seconds, which is how
+// JUnit XML represents durations.
+func junitDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testreport
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// WriteTAP writes the results of the given test suite to the given writer
+// using version 13 of the Test Anything Protocol.
+//
+// Each run block becomes a single test point, named after the test file and
+// the run block. Test points for runs that did not pass include a YAML
+// block describing the failure and the diagnostics that caused it.
+//
+// The given sources are used to include source code snippets in the
+// rendered diagnostics, and may be nil.
+func WriteTAP(w io.Writer, suite *moduletest.Suite, sources map[string]*hcl.File) error {
+	files := sortedFiles(suite)
+
+	total := 0
+	for _, file := range files {
+		total += len(file.Runs)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "TAP version 13")
+	fmt.Fprintf(bw, "1..%d\n", total)
+
+	n := 0
+	for _, file := range files {
+		for _, run := range file.Runs {
+			n++
+			name := fmt.Sprintf("%s/%s", file.Name, run.Name)
+			switch run.Status {
+			case moduletest.Pass:
+				fmt.Fprintf(bw, "ok %d - %s\n", n, name)
+			case moduletest.Skip:
+				fmt.Fprintf(bw, "ok %d - %s # SKIP\n", n, name)
+			case moduletest.Fail, moduletest.Error:
+				fmt.Fprintf(bw, "not ok %d - %s\n", n, name)
+				severity := "fail"
+				if run.Status == moduletest.Error {
+					severity = "error"
+				}
+				writeTAPDetails(bw, severity, run, sources)
+			default:
+				fmt.Fprintf(bw, "ok %d - %s # SKIP testing was interrupted\n", n, name)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeTAPDetails(w io.Writer, severity string, run *moduletest.Run, sources map[string]*hcl.File) {
+	fmt.Fprintln(w, "  ---")
+	fmt.Fprintf(w, "  message: %s\n", tapQuote(failureMessage(run.Diagnostics)))
+	fmt.Fprintf(w, "  severity: %s\n", severity)
+	fmt.Fprintf(w, "  duration_ms: %d\n", run.Duration.Milliseconds())
+	if diags := renderDiagnostics(run.Diagnostics, tfdiags.Error, sources); diags != "" {
+		fmt.Fprintln(w, "  diagnostics: |-")
+		for _, line := range strings.Split(diags, "\n") {
+			if line == "" {
+				fmt.Fprintln(w)
+				continue
+			}
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	fmt.Fprintln(w, "  ...")
+}
+
+// tapQuote returns the given string as a double-quoted YAML scalar. JSON
+// strings are valid YAML, so we can just reuse the JSON encoder.
+func tapQuote(s string) string {
+	ret, _ := json.Marshal(s)
+	return string(ret)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package testreport writes the results of "tofu test" to files in formats
+// that are understood by other software, such as continuous integration
+// systems.
+package testreport
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// sortedFiles returns the files in the given suite in the same order that
+// the test command executes them.
+func sortedFiles(suite *moduletest.Suite) []*moduletest.File {
+	names := make([]string, 0, len(suite.Files))
+	for name := range suite.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]*moduletest.File, len(names))
+	for i, name := range names {
+		files[i] = suite.Files[name]
+	}
+	return files
+}
+
+// failureMessage returns a short description of why a run failed, based on
+// the first error in its diagnostics.
+func failureMessage(diags tfdiags.Diagnostics) string {
+	for _, diag := range diags {
+		if diag.Severity() != tfdiags.Error {
+			continue
+		}
+		desc := diag.Description()
+		if desc.Detail != "" {
+			return desc.Detail
+		}
+		return desc.Summary
+	}
+	return ""
+}
+
+// renderDiagnostics returns the given diagnostics as plain text, in the
+// same form as the human-readable output of the test command. If severity
+// is not zero then only diagnostics of that severity are included.
+func renderDiagnostics(diags tfdiags.Diagnostics, severity tfdiags.Severity, sources map[string]*hcl.File) string {
+	var parts []string
+	for _, diag := range diags {
+		if severity != 0 && diag.Severity() != severity {
+			continue
+		}
+		parts = append(parts, strings.TrimSpace(format.DiagnosticPlain(diag, sources, 0)))
+	}
+	return strings.Join(parts, "\n\n")
+}
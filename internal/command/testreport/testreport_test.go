@@ -0,0 +1,123 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testreport
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestWriteJUnitXML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnitXML(&buf, testSuite(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="tofu test" tests="5" failures="1" errors="1" skipped="2" time="4.500">
+  <testsuite name="main.tftest.hcl" tests="3" failures="1" errors="0" skipped="1" time="3.000">
+    <testcase name="setup" classname="main.tftest.hcl" time="1.250"></testcase>
+    <testcase name="check" classname="main.tftest.hcl" time="0.500">
+      <failure message="Expected &#34;a&#34; to be &#34;b&#34;."><![CDATA[Error: Test assertion failed
+
+Expected "a" to be "b".]]></failure>
+      <system-err><![CDATA[Warning: Deprecated
+
+Something is deprecated.]]></system-err>
+    </testcase>
+    <testcase name="skipped" classname="main.tftest.hcl" time="0.000">
+      <skipped></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="other.tftest.hcl" tests="2" failures="0" errors="1" skipped="1" time="1.500">
+    <testcase name="broken" classname="other.tftest.hcl" time="0.100">
+      <error message="Invalid thing"><![CDATA[Error: Invalid thing]]></error>
+    </testcase>
+    <testcase name="pending" classname="other.tftest.hcl" time="0.000">
+      <skipped message="Testing was interrupted before this run block was executed."></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+}
+
+func TestWriteTAP(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTAP(&buf, testSuite(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `TAP version 13
+1..5
+ok 1 - main.tftest.hcl/setup
+not ok 2 - main.tftest.hcl/check
+  ---
+  message: "Expected \"a\" to be \"b\"."
+  severity: fail
+  duration_ms: 500
+  diagnostics: |-
+    Error: Test assertion failed
+
+    Expected "a" to be "b".
+  ...
+ok 3 - main.tftest.hcl/skipped # SKIP
+not ok 4 - other.tftest.hcl/broken
+  ---
+  message: "Invalid thing"
+  severity: error
+  duration_ms: 100
+  diagnostics: |-
+    Error: Invalid thing
+  ...
+ok 5 - other.tftest.hcl/pending # SKIP testing was interrupted
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+}
+
+func testSuite() *moduletest.Suite {
+	var failDiags tfdiags.Diagnostics
+	failDiags = failDiags.Append(tfdiags.Sourceless(tfdiags.Error, "Test assertion failed", `Expected "a" to be "b".`))
+	failDiags = failDiags.Append(tfdiags.Sourceless(tfdiags.Warning, "Deprecated", "Something is deprecated."))
+
+	var errorDiags tfdiags.Diagnostics
+	errorDiags = errorDiags.Append(tfdiags.Sourceless(tfdiags.Error, "Invalid thing", ""))
+
+	return &moduletest.Suite{
+		Status: moduletest.Error,
+		Files: map[string]*moduletest.File{
+			"other.tftest.hcl": {
+				Name:     "other.tftest.hcl",
+				Status:   moduletest.Error,
+				Duration: 1500 * time.Millisecond,
+				Runs: []*moduletest.Run{
+					{Name: "broken", Status: moduletest.Error, Duration: 100 * time.Millisecond, Diagnostics: errorDiags},
+					{Name: "pending", Status: moduletest.Pending},
+				},
+			},
+			"main.tftest.hcl": {
+				Name:     "main.tftest.hcl",
+				Status:   moduletest.Fail,
+				Duration: 3 * time.Second,
+				Runs: []*moduletest.Run{
+					{Name: "setup", Status: moduletest.Pass, Duration: 1250 * time.Millisecond},
+					{Name: "check", Status: moduletest.Fail, Duration: 500 * time.Millisecond, Diagnostics: failDiags},
+					{Name: "skipped", Status: moduletest.Skip},
+				},
+			},
+		},
+	}
+}
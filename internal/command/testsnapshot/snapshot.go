@@ -0,0 +1,206 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package testsnapshot produces the normalised plan snapshots that the
+// "tofu test" command compares against golden files committed alongside the
+// tests.
+package testsnapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+const (
+	// UnknownValue replaces any value that will not be known until apply.
+	UnknownValue = "(known after apply)"
+
+	// SensitiveValue replaces any sensitive value, so that they are never
+	// written into a snapshot file.
+	SensitiveValue = "(sensitive value)"
+)
+
+type snapshot struct {
+	ResourceChanges []resourceChange        `json:"resource_changes"`
+	OutputChanges   map[string]outputChange `json:"output_changes"`
+}
+
+type resourceChange struct {
+	Address         string          `json:"address"`
+	PreviousAddress string          `json:"previous_address,omitempty"`
+	Deposed         string          `json:"deposed,omitempty"`
+	ProviderName    string          `json:"provider_name"`
+	Actions         []string        `json:"actions"`
+	ActionReason    string          `json:"action_reason,omitempty"`
+	After           any             `json:"after"`
+	ReplacePaths    json.RawMessage `json:"replace_paths,omitempty"`
+}
+
+type outputChange struct {
+	Actions []string `json:"actions"`
+	After   any      `json:"after"`
+}
+
+// Plan returns the snapshot of the given plan, as indented JSON.
+//
+// The snapshot is based on the JSON plan representation, but only includes
+// the planned actions and the planned values of each resource instance and
+// output value. The prior values are not included as they typically contain
+// values chosen by the remote system, such as identifiers, which would differ
+// every time the test is executed. Unknown and sensitive values are replaced
+// with UnknownValue and SensitiveValue respectively.
+func Plan(plan *plans.Plan, schemas *tofu.Schemas) ([]byte, error) {
+	resources, err := jsonplan.MarshalResourceChanges(plan.Changes.Resources, schemas)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := jsonplan.MarshalOutputChanges(plan.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := snapshot{
+		ResourceChanges: make([]resourceChange, 0, len(resources)),
+		OutputChanges:   make(map[string]outputChange, len(outputs)),
+	}
+	for _, rc := range resources {
+		after, err := maskChange(rc.Change)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build snapshot for %s: %w", rc.Address, err)
+		}
+		snap.ResourceChanges = append(snap.ResourceChanges, resourceChange{
+			Address:         rc.Address,
+			PreviousAddress: rc.PreviousAddress,
+			Deposed:         rc.Deposed,
+			ProviderName:    rc.ProviderName,
+			Actions:         rc.Change.Actions,
+			ActionReason:    rc.ActionReason,
+			After:           after,
+			ReplacePaths:    rc.Change.ReplacePaths,
+		})
+	}
+	for name, oc := range outputs {
+		after, err := maskChange(oc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build snapshot for output %s: %w", name, err)
+		}
+		snap.OutputChanges[name] = outputChange{
+			Actions: oc.Actions,
+			After:   after,
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(snap); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Diff returns a unified diff between the expected and actual snapshots, or
+// an empty string if they are equal.
+func Diff(want, got []byte) string {
+	if bytes.Equal(want, got) {
+		return ""
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(want),
+		B:        splitLines(got),
+		FromFile: "expected",
+		ToFile:   "actual",
+		Context:  3,
+	})
+	return diff
+}
+
+// splitLines splits the given text into lines, retaining the line endings.
+// Unlike difflib.SplitLines, it does not add an extra empty line at the end
+// of text that is already terminated by a newline.
+func splitLines(text []byte) []string {
+	lines := strings.SplitAfter(string(text), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func maskChange(change jsonplan.Change) (any, error) {
+	var after, unknown, sensitive any
+	for _, raw := range []struct {
+		src json.RawMessage
+		dst *any
+	}{
+		{change.After, &after},
+		{change.AfterUnknown, &unknown},
+		{change.AfterSensitive, &sensitive},
+	} {
+		if len(raw.src) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw.src))
+		dec.UseNumber()
+		if err := dec.Decode(raw.dst); err != nil {
+			return nil, err
+		}
+	}
+	return mask(after, unknown, sensitive), nil
+}
+
+// mask walks the given value alongside the matching parts of the
+// after_unknown and after_sensitive structures from the JSON plan, replacing
+// any unknown or sensitive values.
+func mask(value, unknown, sensitive any) any {
+	if sensitive == true {
+		return SensitiveValue
+	}
+	if unknown == true {
+		return UnknownValue
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		unknowns, _ := unknown.(map[string]any)
+		sensitives, _ := sensitive.(map[string]any)
+		ret := make(map[string]any, len(v))
+		for key, elem := range v {
+			ret[key] = mask(elem, unknowns[key], sensitives[key])
+		}
+		// Unknown attributes may be missing from the value entirely.
+		for key, u := range unknowns {
+			if _, exists := ret[key]; !exists {
+				ret[key] = mask(nil, u, sensitives[key])
+			}
+		}
+		return ret
+	case []any:
+		unknowns, _ := unknown.([]any)
+		sensitives, _ := sensitive.([]any)
+		ret := make([]any, len(v))
+		for i, elem := range v {
+			ret[i] = mask(elem, index(unknowns, i), index(sensitives, i))
+		}
+		return ret
+	default:
+		return value
+	}
+}
+
+func index(s []any, i int) any {
+	if i < len(s) {
+		return s[i]
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testsnapshot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+)
+
+func TestMaskChange(t *testing.T) {
+	tcs := map[string]struct {
+		after, unknown, sensitive string
+		want                      any
+	}{
+		"known": {
+			after: `{"id":"foo","count":1}`,
+			want:  map[string]any{"id": "foo", "count": json.Number("1")},
+		},
+		"unknown attribute": {
+			after:   `{"value":"bar"}`,
+			unknown: `{"id":true}`,
+			want:    map[string]any{"id": UnknownValue, "value": "bar"},
+		},
+		"sensitive attribute": {
+			after:     `{"id":"foo","password":"hunter2"}`,
+			sensitive: `{"password":true}`,
+			want:      map[string]any{"id": "foo", "password": SensitiveValue},
+		},
+		"nested": {
+			after:     `{"list":["a",null,"c"],"block":[{"token":"x"}]}`,
+			unknown:   `{"list":[false,true]}`,
+			sensitive: `{"block":[{"token":true}]}`,
+			want: map[string]any{
+				"list":  []any{"a", UnknownValue, "c"},
+				"block": []any{map[string]any{"token": SensitiveValue}},
+			},
+		},
+		"unknown value": {
+			unknown: `true`,
+			want:    UnknownValue,
+		},
+		"sensitive value": {
+			after:     `"hunter2"`,
+			sensitive: `true`,
+			want:      SensitiveValue,
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := maskChange(jsonplan.Change{
+				After:          json.RawMessage(tc.after),
+				AfterUnknown:   json.RawMessage(tc.unknown),
+				AfterSensitive: json.RawMessage(tc.sensitive),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if diff := Diff([]byte("a\nb\n"), []byte("a\nb\n")); diff != "" {
+		t.Errorf("expected no diff for equal snapshots, got:\n%s", diff)
+	}
+
+	want := `--- expected
++++ actual
+@@ -1,3 +1,3 @@
+ a
+-b
++x
+ c
+`
+	if diff := cmp.Diff(want, Diff([]byte("a\nb\nc\n"), []byte("a\nx\nc\n"))); diff != "" {
+		t.Errorf("wrong diff\n%s", diff)
+	}
+}
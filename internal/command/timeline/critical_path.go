@@ -0,0 +1,110 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package timeline
+
+import (
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// CriticalPath returns the chain of apply actions that determined how long
+// the apply phase took, in the order they happened.
+//
+// The chain is found by starting at the apply action that finished last and
+// then repeatedly stepping back to whichever of that action's dependencies
+// finished last before it started, since that is the dependency that the
+// action was waiting for. Dependencies are taken from the given state, which
+// should be the state produced by the apply. Resource instances that are no
+// longer in that state, such as those that were destroyed, therefore end the
+// chain.
+//
+// The result is nil if no apply actions were recorded.
+func CriticalPath(spans []Span, state *states.State) []Span {
+	applied := make(map[string]Span)
+	byConfigResource := make(map[string][]string)
+	for _, span := range spans {
+		if span.Category != CategoryApply || !span.IsResourceInstance() {
+			continue
+		}
+		key := span.Resource.String()
+		if prev, exists := applied[key]; exists {
+			// A resource instance can be applied more than once in a single
+			// operation, such as when it is replaced, in which case we
+			// treat all of the actions together as a single span.
+			if prev.Start.Before(span.Start) {
+				span.Start = prev.Start
+			}
+			if prev.End.After(span.End) {
+				span.End = prev.End
+			}
+			span.Failed = span.Failed || prev.Failed
+		} else {
+			cfgKey := span.Resource.ConfigResource().String()
+			byConfigResource[cfgKey] = append(byConfigResource[cfgKey], key)
+		}
+		applied[key] = span
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	var current Span
+	for _, span := range applied {
+		if current.End.IsZero() || span.End.After(current.End) || (span.End.Equal(current.End) && span.Name < current.Name) {
+			current = span
+		}
+	}
+
+	visited := map[string]bool{current.Resource.String(): true}
+	path := []Span{current}
+	for {
+		var blocking *Span
+		for _, dep := range dependencies(state, current) {
+			for _, key := range byConfigResource[dep] {
+				if visited[key] {
+					continue
+				}
+				candidate := applied[key]
+				if candidate.End.After(current.Start) {
+					// This can't have been blocking the current action.
+					continue
+				}
+				if blocking == nil || candidate.End.After(blocking.End) {
+					blocking = &candidate
+				}
+			}
+		}
+		if blocking == nil {
+			break
+		}
+		visited[blocking.Resource.String()] = true
+		path = append(path, *blocking)
+		current = *blocking
+	}
+
+	// We built the path backwards, so we'll now reverse it.
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// dependencies returns the string representations of the configuration
+// resources that the given span's resource instance depends on according
+// to the given state.
+func dependencies(state *states.State, span Span) []string {
+	if state == nil {
+		return nil
+	}
+	is := state.ResourceInstance(span.Resource)
+	if is == nil || is.Current == nil {
+		return nil
+	}
+	ret := make([]string, len(is.Current.Dependencies))
+	for i, dep := range is.Current.Dependencies {
+		ret[i] = dep.String()
+	}
+	return ret
+}
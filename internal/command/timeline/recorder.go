@@ -0,0 +1,196 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package timeline records when each of the individual actions taken during
+// an operation started and finished, so that the results can be written to
+// a local file for later analysis without needing any tracing infrastructure.
+package timeline
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// Category describes the kind of action that a Span represents.
+type Category string
+
+const (
+	CategoryProvider  Category = "provider"
+	CategoryRefresh   Category = "refresh"
+	CategoryPlan      Category = "plan"
+	CategoryApply     Category = "apply"
+	CategoryProvision Category = "provision"
+	CategoryEphemeral Category = "ephemeral"
+)
+
+// Span is a single action that started and finished during an operation.
+type Span struct {
+	Category Category
+
+	// Name describes the subject of the action, which is usually the
+	// address of a resource instance or provider configuration.
+	Name string
+
+	// Resource is the address of the resource instance that the action
+	// relates to. This is the zero value for actions that don't relate to
+	// a particular resource instance, such as configuring a provider.
+	Resource addrs.AbsResourceInstance
+
+	Start time.Time
+	End   time.Time
+
+	// Failed is true if the action returned an error.
+	Failed bool
+}
+
+// Duration returns how long the action took.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// IsResourceInstance returns true if the span relates to a resource instance.
+func (s Span) IsResourceInstance() bool {
+	return s.Resource.Resource.Resource.Type != ""
+}
+
+type spanKey struct {
+	category Category
+	name     string
+}
+
+// Recorder is a tofu.Hook that records a Span for each pair of "pre" and
+// "post" hook calls it receives.
+type Recorder struct {
+	tofu.NilHook
+
+	mu    sync.Mutex
+	now   func() time.Time
+	open  map[spanKey]time.Time
+	spans []Span
+}
+
+var _ tofu.Hook = (*Recorder)(nil)
+
+// NewRecorder returns a Recorder that has not yet recorded anything.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		now:  time.Now,
+		open: make(map[spanKey]time.Time),
+	}
+}
+
+// Spans returns all of the completed spans recorded so far, ordered by
+// their start times.
+func (r *Recorder) Spans() []Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ret := make([]Span, len(r.spans))
+	copy(ret, r.spans)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Start.Before(ret[j].Start)
+	})
+	return ret
+}
+
+func (r *Recorder) start(category Category, name string) (tofu.HookAction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.open[spanKey{category, name}] = r.now()
+	return tofu.HookActionContinue, nil
+}
+
+func (r *Recorder) finish(category Category, name string, resource addrs.AbsResourceInstance, err error) (tofu.HookAction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := spanKey{category, name}
+	start, ok := r.open[key]
+	if !ok {
+		// We didn't see the corresponding "pre" call, so there's nothing
+		// useful we can record.
+		return tofu.HookActionContinue, nil
+	}
+	delete(r.open, key)
+
+	r.spans = append(r.spans, Span{
+		Category: category,
+		Name:     name,
+		Resource: resource,
+		Start:    start,
+		End:      r.now(),
+		Failed:   err != nil,
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func providerSpanName(addr addrs.AbsProviderConfig, key addrs.InstanceKey) string {
+	if key == addrs.NoKey {
+		return addr.String()
+	}
+	return addr.String() + key.String()
+}
+
+func provisionSpanName(addr addrs.AbsResourceInstance, typeName string) string {
+	return fmt.Sprintf("%s (%s)", addr, typeName)
+}
+
+func (r *Recorder) PreConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) (tofu.HookAction, error) {
+	return r.start(CategoryProvider, providerSpanName(addr, key))
+}
+
+func (r *Recorder) PostConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, err error) (tofu.HookAction, error) {
+	return r.finish(CategoryProvider, providerSpanName(addr, key), addrs.AbsResourceInstance{}, err)
+}
+
+func (r *Recorder) PreRefresh(addr addrs.AbsResourceInstance, _ states.Generation, _ cty.Value) (tofu.HookAction, error) {
+	return r.start(CategoryRefresh, addr.String())
+}
+
+func (r *Recorder) PostRefresh(addr addrs.AbsResourceInstance, _ states.Generation, _ cty.Value, _ cty.Value) (tofu.HookAction, error) {
+	return r.finish(CategoryRefresh, addr.String(), addr, nil)
+}
+
+func (r *Recorder) PreDiff(addr addrs.AbsResourceInstance, _ states.Generation, _, _ cty.Value) (tofu.HookAction, error) {
+	return r.start(CategoryPlan, addr.String())
+}
+
+func (r *Recorder) PostDiff(addr addrs.AbsResourceInstance, _ states.Generation, _ plans.Action, _, _ cty.Value) (tofu.HookAction, error) {
+	return r.finish(CategoryPlan, addr.String(), addr, nil)
+}
+
+func (r *Recorder) PreApply(addr addrs.AbsResourceInstance, _ states.Generation, _ plans.Action, _, _ cty.Value) (tofu.HookAction, error) {
+	return r.start(CategoryApply, addr.String())
+}
+
+func (r *Recorder) PostApply(addr addrs.AbsResourceInstance, _ states.Generation, _ cty.Value, err error) (tofu.HookAction, error) {
+	return r.finish(CategoryApply, addr.String(), addr, err)
+}
+
+func (r *Recorder) PreProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string) (tofu.HookAction, error) {
+	return r.start(CategoryProvision, provisionSpanName(addr, typeName))
+}
+
+func (r *Recorder) PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string, err error) (tofu.HookAction, error) {
+	return r.finish(CategoryProvision, provisionSpanName(addr, typeName), addr, err)
+}
+
+func (r *Recorder) PreOpen(addr addrs.AbsResourceInstance) (tofu.HookAction, error) {
+	return r.start(CategoryEphemeral, addr.String())
+}
+
+func (r *Recorder) PostOpen(addr addrs.AbsResourceInstance, err error) (tofu.HookAction, error) {
+	return r.finish(CategoryEphemeral, addr.String(), addr, err)
+}
@@ -0,0 +1,160 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package timeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestRecorder(t *testing.T) {
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRecorder()
+	r.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	provider := addrs.AbsProviderConfig{Module: addrs.RootModule, Provider: addrs.NewDefaultProvider("test")}
+	foo := mustResourceInstanceAddr("test_instance.foo")
+
+	r.PreConfigureProvider(provider, addrs.NoKey)
+	r.PostConfigureProvider(provider, addrs.NoKey, nil)
+	r.PreApply(foo, states.CurrentGen, plans.Create, cty.NilVal, cty.NilVal)
+	r.PreProvisionInstanceStep(foo, "local-exec")
+	r.PostProvisionInstanceStep(foo, "local-exec", errors.New("oops"))
+	r.PostApply(foo, states.CurrentGen, cty.NilVal, nil)
+	// A post call without a matching pre call is ignored.
+	r.PostRefresh(foo, states.CurrentGen, cty.NilVal, cty.NilVal)
+
+	var got []string
+	for _, span := range r.Spans() {
+		got = append(got, string(span.Category)+" "+span.Name+" "+span.Duration().String()+" "+map[bool]string{true: "failed", false: "ok"}[span.Failed])
+	}
+	want := []string{
+		`provider provider["registry.opentofu.org/hashicorp/test"] 1s ok`,
+		`apply test_instance.foo 3s ok`,
+		`provision test_instance.foo (local-exec) 1s failed`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong spans\n%s", diff)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+	applySpan := func(addr string, from, to int) Span {
+		return Span{
+			Category: CategoryApply,
+			Name:     addr,
+			Resource: mustResourceInstanceAddr(addr),
+			Start:    at(from),
+			End:      at(to),
+		}
+	}
+
+	spans := []Span{
+		applySpan("test_network.main", 0, 10),
+		applySpan("test_role.main", 0, 2),
+		// The subnets depend on the network, and one of them takes longer.
+		applySpan("test_subnet.a[0]", 10, 12),
+		applySpan("test_subnet.a[1]", 10, 15),
+		// The instance depends on both the subnets and the role, but it
+		// was the slower subnet that it was waiting for.
+		applySpan("test_instance.web", 15, 20),
+		// Planning spans are not considered.
+		{Category: CategoryPlan, Name: "test_instance.web", Resource: mustResourceInstanceAddr("test_instance.web"), Start: at(0), End: at(30)},
+	}
+
+	state := states.BuildState(func(s *states.SyncState) {
+		set := func(addr string, deps ...string) {
+			var depAddrs []addrs.ConfigResource
+			for _, dep := range deps {
+				depAddrs = append(depAddrs, mustResourceInstanceAddr(dep).ConfigResource())
+			}
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(addr),
+				&states.ResourceInstanceObjectSrc{Status: states.ObjectReady, AttrsJSON: []byte(`{}`), Dependencies: depAddrs},
+				addrs.AbsProviderConfig{Module: addrs.RootModule, Provider: addrs.NewDefaultProvider("test")},
+				addrs.NoKey,
+			)
+		}
+		set("test_network.main")
+		set("test_role.main")
+		set("test_subnet.a[0]", "test_network.main")
+		set("test_subnet.a[1]", "test_network.main")
+		set("test_instance.web", "test_subnet.a", "test_role.main")
+	})
+
+	var got []string
+	for _, span := range CriticalPath(spans, state) {
+		got = append(got, span.Name)
+	}
+	want := []string{"test_network.main", "test_subnet.a[1]", "test_instance.web"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong critical path\n%s", diff)
+	}
+
+	if got := CriticalPath(spans[5:], state); got != nil {
+		t.Errorf("unexpected critical path without apply spans: %#v", got)
+	}
+}
+
+func TestWriteChromeTrace(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	spans := []Span{
+		{Category: CategoryApply, Name: "a", Start: start, End: start.Add(2 * time.Second)},
+		{Category: CategoryApply, Name: "b", Start: start.Add(time.Second), End: start.Add(3 * time.Second), Failed: true},
+		{Category: CategoryApply, Name: "c", Start: start.Add(2 * time.Second), End: start.Add(4 * time.Second)},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteChromeTrace(&buf, spans, spans[2:]); err != nil {
+		t.Fatal(err)
+	}
+
+	var got traceFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := traceFile{
+		DisplayTimeUnit: "ms",
+		TraceEvents: []traceEvent{
+			{Name: "process_name", Phase: "M", Process: 1, Args: map[string]any{"name": "OpenTofu"}},
+			{Name: "a", Category: "apply", Phase: "X", Time: 0, Duration: 2000000, Process: 1, Thread: 1},
+			// "b" overlaps with "a", so it goes in a separate thread.
+			{Name: "b", Category: "apply", Phase: "X", Time: 1000000, Duration: 2000000, Process: 1, Thread: 2, Args: map[string]any{"failed": true}},
+			// "c" starts when "a" finishes, so it can reuse its thread.
+			{Name: "c", Category: "apply", Phase: "X", Time: 2000000, Duration: 2000000, Process: 1, Thread: 1},
+			{Name: "process_name", Phase: "M", Process: 2, Args: map[string]any{"name": "Critical path"}},
+			{Name: "c", Category: "apply", Phase: "X", Time: 2000000, Duration: 2000000, Process: 2, Thread: 1},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong trace\n%s", diff)
+	}
+}
+
+func mustResourceInstanceAddr(s string) addrs.AbsResourceInstance {
+	addr, diags := addrs.ParseAbsResourceInstanceStr(s)
+	if diags.HasErrors() {
+		panic(diags.Err())
+	}
+	return addr
+}
@@ -0,0 +1,109 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package timeline
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// traceFile is the top-level object of the Chrome trace event format, which
+// is understood by chrome://tracing, Perfetto and various other tools.
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+type traceEvent struct {
+	Name     string         `json:"name"`
+	Category string         `json:"cat,omitempty"`
+	Phase    string         `json:"ph"`
+	Time     int64          `json:"ts"`
+	Duration int64          `json:"dur,omitempty"`
+	Process  int            `json:"pid"`
+	Thread   int            `json:"tid"`
+	Args     map[string]any `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the given spans to the given writer using the
+// Chrome trace event format.
+//
+// Each span becomes a "complete" event with its time given relative to the
+// earliest span. Spans that overlap in time are assigned to separate
+// "threads", so that tools render actions that were running concurrently
+// in separate rows. The critical path spans, if any, are included again as
+// a separate process so that they can be seen together.
+func WriteChromeTrace(w io.Writer, spans []Span, criticalPath []Span) error {
+	file := traceFile{
+		TraceEvents:     []traceEvent{},
+		DisplayTimeUnit: "ms",
+	}
+
+	var origin time.Time
+	for _, span := range spans {
+		if origin.IsZero() || span.Start.Before(origin) {
+			origin = span.Start
+		}
+	}
+
+	file.TraceEvents = append(file.TraceEvents, processName(1, "OpenTofu"))
+	file.TraceEvents = append(file.TraceEvents, spanEvents(spans, origin, 1)...)
+	if len(criticalPath) != 0 {
+		file.TraceEvents = append(file.TraceEvents, processName(2, "Critical path"))
+		file.TraceEvents = append(file.TraceEvents, spanEvents(criticalPath, origin, 2)...)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(file)
+}
+
+func processName(pid int, name string) traceEvent {
+	return traceEvent{
+		Name:    "process_name",
+		Phase:   "M",
+		Process: pid,
+		Args:    map[string]any{"name": name},
+	}
+}
+
+func spanEvents(spans []Span, origin time.Time, pid int) []traceEvent {
+	// lanes tracks the end time of the latest span in each lane, so that
+	// each span can be placed in the first lane that is free at its start.
+	var lanes []time.Time
+	ret := make([]traceEvent, 0, len(spans))
+	for _, span := range spans {
+		lane := -1
+		for i, end := range lanes {
+			if !end.After(span.Start) {
+				lane = i
+				break
+			}
+		}
+		if lane == -1 {
+			lane = len(lanes)
+			lanes = append(lanes, time.Time{})
+		}
+		lanes[lane] = span.End
+
+		args := map[string]any{}
+		if span.Failed {
+			args["failed"] = true
+		}
+		ret = append(ret, traceEvent{
+			Name:     span.Name,
+			Category: string(span.Category),
+			Phase:    "X",
+			Time:     span.Start.Sub(origin).Microseconds(),
+			Duration: span.Duration().Microseconds(),
+			Process:  pid,
+			Thread:   lane + 1,
+			Args:     args,
+		})
+	}
+	return ret
+}
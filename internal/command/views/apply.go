@@ -7,9 +7,12 @@ package views
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/command/timeline"
 	"github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -21,6 +24,10 @@ type Apply interface {
 	ResourceCount(stateOutPath string)
 	Outputs(outputValues map[string]*states.OutputValue)
 
+	// Timeline reports that a timeline of the operation was written to the
+	// given path, along with the critical path through the apply phase.
+	Timeline(path string, criticalPath []timeline.Span)
+
 	Operation() Operation
 	Hooks() []tofu.Hook
 
@@ -78,6 +85,12 @@ func (m ApplyMulti) Outputs(outputValues map[string]*states.OutputValue) {
 	}
 }
 
+func (m ApplyMulti) Timeline(path string, criticalPath []timeline.Span) {
+	for _, a := range m {
+		a.Timeline(path, criticalPath)
+	}
+}
+
 func (m ApplyMulti) Operation() Operation {
 	var operation OperationMulti
 	for _, a := range m {
@@ -180,6 +193,23 @@ func (v *ApplyHuman) Outputs(outputValues map[string]*states.OutputValue) {
 	}
 }
 
+func (v *ApplyHuman) Timeline(path string, criticalPath []timeline.Span) {
+	v.view.streams.Printf("\nTimeline written to %s.\n", path)
+	if len(criticalPath) == 0 {
+		return
+	}
+
+	width := 0
+	for _, span := range criticalPath {
+		width = max(width, len(span.Name))
+	}
+	total := criticalPath[len(criticalPath)-1].End.Sub(criticalPath[0].Start)
+	v.view.streams.Print(v.view.colorize.Color(fmt.Sprintf("\n[reset][bold]Critical path (%s):\n", total.Round(time.Millisecond))))
+	for _, span := range criticalPath {
+		v.view.streams.Printf("  %-*s  %s\n", width, span.Name, span.Duration().Round(time.Millisecond))
+	}
+}
+
 func (v *ApplyHuman) Operation() Operation {
 	return NewOperation(arguments.ViewHuman, v.view)
 }
@@ -244,6 +274,18 @@ func (v *ApplyJSON) Outputs(outputValues map[string]*states.OutputValue) {
 	}
 }
 
+func (v *ApplyJSON) Timeline(path string, criticalPath []timeline.Span) {
+	steps := make([]string, len(criticalPath))
+	for i, span := range criticalPath {
+		steps[i] = fmt.Sprintf("%s (%s)", span.Name, span.Duration().Round(time.Millisecond))
+	}
+	msg := fmt.Sprintf("Timeline written to %s", path)
+	if len(steps) != 0 {
+		msg += "; critical path: " + strings.Join(steps, " -> ")
+	}
+	v.view.Info(msg)
+}
+
 func (v *ApplyJSON) Operation() Operation {
 	return &OperationJSON{view: v.view}
 }
@@ -9,8 +9,10 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/timeline"
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/terminal"
@@ -70,6 +72,29 @@ func TestApplyHuman_outputsEmpty(t *testing.T) {
 
 // Ensure that the correct view type and in-automation settings propagate to the
 // Operation view.
+func TestApplyHuman_timeline(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewApply(arguments.ViewOptions{ViewType: arguments.ViewHuman}, false, NewView(streams))
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	v.Timeline("timeline.json", []timeline.Span{
+		{Name: "test_network.main", Start: start, End: start.Add(10 * time.Second)},
+		{Name: "test_instance.web", Start: start.Add(11 * time.Second), End: start.Add(15 * time.Second)},
+	})
+
+	got := done(t).Stdout()
+	want := `
+Timeline written to timeline.json.
+
+Critical path (15s):
+  test_network.main  10s
+  test_instance.web  4s
+`
+	if got != want {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
 func TestApplyHuman_operation(t *testing.T) {
 	streams, done := terminal.StreamsForTesting(t)
 	defer done(t)
@@ -0,0 +1,103 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+type ImportDiscover interface {
+	Diagnostics(diags tfdiags.Diagnostics)
+	ErrorUnsupportedLocalOp()
+
+	// Discovered lists the objects that can be imported, along with the
+	// resource instances that already manage some of the listed objects.
+	Discovered(resources []*tofu.DiscoveredResource, managed []addrs.AbsResourceInstance)
+	// NothingDiscovered reports that there are no objects to import, because
+	// the provider listed none or they are all already managed.
+	NothingDiscovered(managed []addrs.AbsResourceInstance)
+	// WroteFile reports that the import blocks and generated configuration
+	// were written into the file at the given path.
+	WroteFile(path string)
+	// Output prints the import blocks and generated configuration.
+	Output(src string)
+
+	// Backend returns the non-command view that contains methods to provide
+	// progress output for the backend operations.
+	Backend() Backend
+}
+
+// NewImportDiscover returns an initialized ImportDiscover implementation for the given ViewType.
+func NewImportDiscover(view *View) ImportDiscover {
+	return &ImportDiscoverHuman{view: view}
+}
+
+type ImportDiscoverHuman struct {
+	view *View
+}
+
+var _ ImportDiscover = (*ImportDiscoverHuman)(nil)
+
+func (v *ImportDiscoverHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *ImportDiscoverHuman) ErrorUnsupportedLocalOp() {
+	v.Diagnostics(tfdiags.Diagnostics{diagUnsupportedLocalOp})
+}
+
+func (v *ImportDiscoverHuman) Discovered(resources []*tofu.DiscoveredResource, managed []addrs.AbsResourceInstance) {
+	_, _ = v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf("[bold]OpenTofu found %d %s to import:[reset]\n", len(resources), pluralObjects(len(resources)))))
+	for _, resource := range resources {
+		_, _ = v.view.streams.Printf("  %s (%s)\n", resource.Addr, resource.DisplayName)
+	}
+	_, _ = v.view.streams.Println()
+	v.managed(managed)
+}
+
+func (v *ImportDiscoverHuman) NothingDiscovered(managed []addrs.AbsResourceInstance) {
+	_, _ = v.view.streams.Println("No objects to import. The provider didn't list any objects that aren't already managed by OpenTofu.")
+	if len(managed) > 0 {
+		_, _ = v.view.streams.Println()
+	}
+	v.managed(managed)
+}
+
+func (v *ImportDiscoverHuman) managed(managed []addrs.AbsResourceInstance) {
+	if len(managed) == 0 {
+		return
+	}
+	_, _ = v.view.streams.Printf("Skipped %d %s already managed by OpenTofu:\n\n", len(managed), pluralObjects(len(managed)))
+	for _, addr := range managed {
+		_, _ = v.view.streams.Printf("  %s\n", addr)
+	}
+	_, _ = v.view.streams.Println()
+}
+
+func (v *ImportDiscoverHuman) WroteFile(path string) {
+	_, _ = v.view.streams.Printf("The import blocks and generated configuration were written to %s. Review them and then run \"tofu plan\" to check what will be imported.\n", path)
+}
+
+func (v *ImportDiscoverHuman) Output(src string) {
+	_, _ = v.view.streams.Print(src)
+}
+
+func (v *ImportDiscoverHuman) Backend() Backend {
+	return &BackendHuman{
+		view: v.view,
+	}
+}
+
+func pluralObjects(n int) string {
+	if n == 1 {
+		return "object"
+	}
+	return "objects"
+}
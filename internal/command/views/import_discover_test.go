@@ -0,0 +1,106 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestImportDiscoverView(t *testing.T) {
+	managed := []addrs.AbsResourceInstance{
+		addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "db", addrs.NoKey),
+	}
+
+	tests := map[string]struct {
+		viewCall   func(discover ImportDiscover)
+		wantStdout string
+		wantStderr string
+	}{
+		"discovered": {
+			viewCall: func(discover ImportDiscover) {
+				discover.Discovered([]*tofu.DiscoveredResource{
+					{
+						Addr:        addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "web", addrs.NoKey),
+						DisplayName: "web",
+					},
+					{
+						Addr:        addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "cache_server", addrs.NoKey),
+						DisplayName: "Cache Server",
+					},
+				}, managed)
+			},
+			wantStdout: `OpenTofu found 2 objects to import:
+
+  test_instance.web (web)
+  test_instance.cache_server (Cache Server)
+
+Skipped 1 object already managed by OpenTofu:
+
+  test_instance.db
+
+`,
+		},
+		"nothing discovered": {
+			viewCall: func(discover ImportDiscover) {
+				discover.NothingDiscovered(nil)
+			},
+			wantStdout: "No objects to import. The provider didn't list any objects that aren't already managed by OpenTofu.\n",
+		},
+		"nothing discovered with managed": {
+			viewCall: func(discover ImportDiscover) {
+				discover.NothingDiscovered(managed)
+			},
+			wantStdout: `No objects to import. The provider didn't list any objects that aren't already managed by OpenTofu.
+
+Skipped 1 object already managed by OpenTofu:
+
+  test_instance.db
+
+`,
+		},
+		"wrote file": {
+			viewCall: func(discover ImportDiscover) {
+				discover.WroteFile("discovered.tf")
+			},
+			wantStdout: "The import blocks and generated configuration were written to discovered.tf. Review them and then run \"tofu plan\" to check what will be imported.\n",
+		},
+		"output": {
+			viewCall: func(discover ImportDiscover) {
+				discover.Output("import {\n  to = a.b\n}\n")
+			},
+			wantStdout: "import {\n  to = a.b\n}\n",
+		},
+		"diagnostics error": {
+			viewCall: func(discover ImportDiscover) {
+				discover.Diagnostics(tfdiags.Diagnostics{
+					tfdiags.Sourceless(tfdiags.Error, "An error occurred", "This is an error message"),
+				})
+			},
+			wantStderr: "\nError: An error occurred\n\nThis is an error message\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			call := NewImportDiscover(view)
+			tc.viewCall(call)
+			output := done(t)
+			if diff := cmp.Diff(tc.wantStderr, output.Stderr()); diff != "" {
+				t.Errorf("invalid stderr (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantStdout, output.Stdout()); diff != "" {
+				t.Errorf("invalid stdout (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
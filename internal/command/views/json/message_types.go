@@ -34,12 +34,14 @@ const (
 	MessageEphemeralActionComplete MessageType = "ephemeral_action_complete"
 
 	// Test messages
-	MessageTestAbstract  MessageType = "test_abstract"
-	MessageTestFile      MessageType = "test_file"
-	MessageTestRun       MessageType = "test_run"
-	MessageTestPlan      MessageType = "test_plan"
-	MessageTestState     MessageType = "test_state"
-	MessageTestSummary   MessageType = "test_summary"
-	MessageTestCleanup   MessageType = "test_cleanup"
-	MessageTestInterrupt MessageType = "test_interrupt"
+	MessageTestAbstract         MessageType = "test_abstract"
+	MessageTestFile             MessageType = "test_file"
+	MessageTestRun              MessageType = "test_run"
+	MessageTestPlan             MessageType = "test_plan"
+	MessageTestState            MessageType = "test_state"
+	MessageTestSummary          MessageType = "test_summary"
+	MessageTestCoverage         MessageType = "test_coverage"
+	MessageTestCleanup          MessageType = "test_cleanup"
+	MessageTestCleanupLeftovers MessageType = "test_cleanup_leftovers"
+	MessageTestInterrupt        MessageType = "test_interrupt"
 )
@@ -34,6 +34,43 @@ type TestSuiteSummary struct {
 	Skipped int        `json:"skipped"`
 }
 
+type TestCoverage struct {
+	Exercised    int                           `json:"exercised"`
+	Total        int                           `json:"total"`
+	Kinds        map[string]TestCoverageTotals `json:"kinds"`
+	NotExercised []TestCoverageItem            `json:"not_exercised,omitempty"`
+}
+
+type TestCleanupLeftovers struct {
+	Cleaned   int `json:"cleaned"`
+	Remaining int `json:"remaining"`
+}
+
+type TestCoverageTotals struct {
+	Exercised int `json:"exercised"`
+	Total     int `json:"total"`
+}
+
+type TestCoverageItem struct {
+	Kind    string `json:"kind"`
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Runs    int    `json:"runs"`
+}
+
+func NewTestCoverageItem(item moduletest.CoverageItem) TestCoverageItem {
+	return TestCoverageItem{
+		Kind:    string(item.Kind),
+		Address: item.Address,
+		Label:   item.Label,
+		File:    item.DeclRange.Filename,
+		Line:    item.DeclRange.Start.Line,
+		Runs:    item.Runs,
+	}
+}
+
 type TestFileCleanup struct {
 	FailedResources []TestFailedResource `json:"failed_resources"`
 }
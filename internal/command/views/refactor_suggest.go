@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/refactoring"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+type RefactorSuggest interface {
+	Diagnostics(diags tfdiags.Diagnostics)
+	ErrorUnsupportedLocalOp()
+
+	// Suggestions describes each of the suggested moves and why it was
+	// suggested.
+	Suggestions(moves []refactoring.SuggestedMove)
+	// NoSuggestions reports that there is nothing to suggest.
+	NoSuggestions()
+	// WroteFile reports that the suggested moved blocks were written into
+	// the file at the given path.
+	WroteFile(path string)
+	// Output prints the generated moved blocks.
+	Output(src string)
+
+	// Backend returns the non-command view that contains methods to provide
+	// progress output for the backend operations.
+	Backend() Backend
+}
+
+// NewRefactorSuggest returns an initialized RefactorSuggest implementation for the given ViewType.
+func NewRefactorSuggest(view *View) RefactorSuggest {
+	return &RefactorSuggestHuman{view: view}
+}
+
+type RefactorSuggestHuman struct {
+	view *View
+}
+
+var _ RefactorSuggest = (*RefactorSuggestHuman)(nil)
+
+func (v *RefactorSuggestHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *RefactorSuggestHuman) ErrorUnsupportedLocalOp() {
+	v.Diagnostics(tfdiags.Diagnostics{diagUnsupportedLocalOp})
+}
+
+func (v *RefactorSuggestHuman) Suggestions(moves []refactoring.SuggestedMove) {
+	noun := "moves"
+	if len(moves) == 1 {
+		noun = "move"
+	}
+	_, _ = v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf("[bold]OpenTofu found %d possible %s:[reset]\n", len(moves), noun)))
+	for _, move := range moves {
+		_, _ = v.view.streams.Printf("  %s -> %s\n", move.From, move.To)
+		_, _ = v.view.streams.Printf("    confidence %.0f%%: %s\n", move.Confidence*100, strings.Join(move.Reasons, ", "))
+	}
+	_, _ = v.view.streams.Println()
+}
+
+func (v *RefactorSuggestHuman) NoSuggestions() {
+	_, _ = v.view.streams.Println("No moves to suggest. OpenTofu found no objects that are no longer declared in the configuration and closely match a newly-declared object.")
+}
+
+func (v *RefactorSuggestHuman) WroteFile(path string) {
+	_, _ = v.view.streams.Printf("The suggested moved blocks were written to %s. Review them and then run \"tofu plan\" to check that they have the intended effect.\n", path)
+}
+
+func (v *RefactorSuggestHuman) Output(src string) {
+	_, _ = v.view.streams.Print(src)
+}
+
+func (v *RefactorSuggestHuman) Backend() Backend {
+	return &BackendHuman{
+		view: v.view,
+	}
+}
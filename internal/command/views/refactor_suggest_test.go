@@ -0,0 +1,92 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/refactoring"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestRefactorSuggestView(t *testing.T) {
+	tests := map[string]struct {
+		viewCall   func(suggest RefactorSuggest)
+		wantStdout string
+		wantStderr string
+	}{
+		"suggestions": {
+			viewCall: func(suggest RefactorSuggest) {
+				suggest.Suggestions([]refactoring.SuggestedMove{
+					{
+						From:       addrs.RootModuleInstance.Resource(addrs.ManagedResourceMode, "test_instance", "a"),
+						To:         addrs.RootModuleInstance.Child("child", addrs.NoKey).Resource(addrs.ManagedResourceMode, "test_instance", "a"),
+						Confidence: 1,
+						Reasons:    []string{"all 2 instances of test_instance.a match by key"},
+					},
+					{
+						From:       addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "b", addrs.IntKey(0)),
+						To:         addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "b", addrs.StringKey("blue")),
+						Confidence: 0.9,
+						Reasons:    []string{"1 of 1 known attribute values match", "same resource name"},
+					},
+				})
+			},
+			wantStdout: `OpenTofu found 2 possible moves:
+
+  test_instance.a -> module.child.test_instance.a
+    confidence 100%: all 2 instances of test_instance.a match by key
+  test_instance.b[0] -> test_instance.b["blue"]
+    confidence 90%: 1 of 1 known attribute values match, same resource name
+
+`,
+		},
+		"no suggestions": {
+			viewCall: func(suggest RefactorSuggest) {
+				suggest.NoSuggestions()
+			},
+			wantStdout: "No moves to suggest. OpenTofu found no objects that are no longer declared in the configuration and closely match a newly-declared object.\n",
+		},
+		"wrote file": {
+			viewCall: func(suggest RefactorSuggest) {
+				suggest.WroteFile("moved.tf")
+			},
+			wantStdout: "The suggested moved blocks were written to moved.tf. Review them and then run \"tofu plan\" to check that they have the intended effect.\n",
+		},
+		"output": {
+			viewCall: func(suggest RefactorSuggest) {
+				suggest.Output("moved {\n  from = a.b\n  to   = a.c\n}\n")
+			},
+			wantStdout: "moved {\n  from = a.b\n  to   = a.c\n}\n",
+		},
+		"diagnostics error": {
+			viewCall: func(suggest RefactorSuggest) {
+				suggest.Diagnostics(tfdiags.Diagnostics{
+					tfdiags.Sourceless(tfdiags.Error, "An error occurred", "This is an error message"),
+				})
+			},
+			wantStderr: "\nError: An error occurred\n\nThis is an error message\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			call := NewRefactorSuggest(view)
+			tc.viewCall(call)
+			output := done(t)
+			if diff := cmp.Diff(tc.wantStderr, output.Stderr()); diff != "" {
+				t.Errorf("invalid stderr (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantStdout, output.Stdout()); diff != "" {
+				t.Errorf("invalid stdout (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
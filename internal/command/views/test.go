@@ -8,6 +8,7 @@ package views
 import (
 	"bytes"
 	"fmt"
+	"sync"
 
 	"github.com/mitchellh/colorstring"
 
@@ -42,6 +43,14 @@ type Test interface {
 	// completed status.
 	Conclusion(suite *moduletest.Suite)
 
+	// Coverage prints out a summary of which parts of the configuration
+	// under test were exercised by the tests.
+	Coverage(coverage *moduletest.Coverage)
+
+	// CleanupLeftovers prints out a summary of the leftover states that were
+	// destroyed by tofu test -cleanup-leftovers, and the number that remain.
+	CleanupLeftovers(cleaned, remaining int)
+
 	// File prints out the summary for an entire test file.
 	File(file *moduletest.File)
 
@@ -112,6 +121,18 @@ func (m TestMulti) Conclusion(suite *moduletest.Suite) {
 	}
 }
 
+func (m TestMulti) CleanupLeftovers(cleaned, remaining int) {
+	for _, t := range m {
+		t.CleanupLeftovers(cleaned, remaining)
+	}
+}
+
+func (m TestMulti) Coverage(coverage *moduletest.Coverage) {
+	for _, t := range m {
+		t.Coverage(coverage)
+	}
+}
+
 func (m TestMulti) File(file *moduletest.File) {
 	for _, t := range m {
 		t.File(file)
@@ -154,6 +175,89 @@ func (m TestMulti) FatalInterruptSummary(run *moduletest.Run, file *moduletest.F
 	}
 }
 
+// TestBuffer is a Test view that holds on to the output for a single test
+// file until Flush is called, at which point it is written to the wrapped
+// view. This allows test files to be executed concurrently while their output
+// is still rendered in a deterministic order.
+//
+// The interrupt messages are not buffered, as the user needs to see those
+// straight away.
+type TestBuffer struct {
+	view Test
+
+	mu    sync.Mutex
+	calls []func(view Test)
+}
+
+var _ Test = (*TestBuffer)(nil)
+
+func NewTestBuffer(view Test) *TestBuffer {
+	return &TestBuffer{view: view}
+}
+
+// Flush writes all the output recorded so far to the wrapped view, in the
+// order it was recorded.
+func (b *TestBuffer) Flush() {
+	b.mu.Lock()
+	calls := b.calls
+	b.calls = nil
+	b.mu.Unlock()
+
+	for _, call := range calls {
+		call(b.view)
+	}
+}
+
+func (b *TestBuffer) record(call func(view Test)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, call)
+}
+
+func (b *TestBuffer) Abstract(suite *moduletest.Suite) {
+	b.record(func(view Test) { view.Abstract(suite) })
+}
+
+func (b *TestBuffer) Conclusion(suite *moduletest.Suite) {
+	b.record(func(view Test) { view.Conclusion(suite) })
+}
+
+func (b *TestBuffer) CleanupLeftovers(cleaned, remaining int) {
+	b.record(func(view Test) { view.CleanupLeftovers(cleaned, remaining) })
+}
+
+func (b *TestBuffer) Coverage(coverage *moduletest.Coverage) {
+	b.record(func(view Test) { view.Coverage(coverage) })
+}
+
+func (b *TestBuffer) File(file *moduletest.File) {
+	b.record(func(view Test) { view.File(file) })
+}
+
+func (b *TestBuffer) Run(run *moduletest.Run, file *moduletest.File) {
+	b.record(func(view Test) { view.Run(run, file) })
+}
+
+func (b *TestBuffer) DestroySummary(diags tfdiags.Diagnostics, run *moduletest.Run, file *moduletest.File, state *states.State) {
+	b.record(func(view Test) { view.DestroySummary(diags, run, file, state) })
+}
+
+func (b *TestBuffer) Diagnostics(run *moduletest.Run, file *moduletest.File, diags tfdiags.Diagnostics) {
+	b.record(func(view Test) { view.Diagnostics(run, file, diags) })
+}
+
+func (b *TestBuffer) Interrupted() {
+	b.view.Interrupted()
+}
+
+func (b *TestBuffer) FatalInterrupt() {
+	b.view.FatalInterrupt()
+}
+
+func (b *TestBuffer) FatalInterruptSummary(run *moduletest.Run, file *moduletest.File, states map[*moduletest.Run]*states.State, created []*plans.ResourceInstanceChangeSrc) {
+	b.view.FatalInterruptSummary(run, file, states, created)
+}
+
 type TestHuman struct {
 	view *View
 }
@@ -200,6 +304,50 @@ func (t *TestHuman) Conclusion(suite *moduletest.Suite) {
 	}
 }
 
+func (t *TestHuman) CleanupLeftovers(cleaned, remaining int) {
+	if cleaned == 0 && remaining == 0 {
+		t.view.streams.Println("No leftover test infrastructure to clean up.")
+		return
+	}
+
+	if cleaned > 0 {
+		t.view.streams.Printf("%s Destroyed the resources of %d leftover %s.\n", t.view.colorize.Color("[green]Success![reset]"), cleaned, pluralStates(cleaned))
+	}
+	if remaining > 0 {
+		t.view.streams.Print(format.WordWrap(fmt.Sprintf("%s %d leftover %s could not be destroyed, and will be tried again by the next execution of tofu test -cleanup-leftovers.\n", t.view.colorize.Color("[red]Failure![reset]"), remaining, pluralStates(remaining)), t.view.outputColumns()))
+	}
+}
+
+func pluralStates(n int) string {
+	if n == 1 {
+		return "state"
+	}
+	return "states"
+}
+
+func (t *TestHuman) Coverage(coverage *moduletest.Coverage) {
+	overall, kinds := coverage.Totals()
+
+	t.view.streams.Println()
+	t.view.streams.Printf("Coverage: %d of %d items exercised (%.1f%%).\n", overall.Exercised, overall.Total, overall.Percent())
+	for _, kind := range moduletest.CoverageKinds {
+		if totals, ok := kinds[kind]; ok {
+			t.view.streams.Printf("  %-24s %d of %d\n", coverageKindNames[kind], totals.Exercised, totals.Total)
+		}
+	}
+
+	if overall.Exercised == overall.Total {
+		return
+	}
+	t.view.streams.Println()
+	t.view.streams.Println("Not exercised:")
+	for _, item := range coverage.Items() {
+		if item.Runs == 0 {
+			t.view.streams.Printf("  - %s:%d: %s\n", item.DeclRange.Filename, item.DeclRange.Start.Line, item)
+		}
+	}
+}
+
 func (t *TestHuman) File(file *moduletest.File) {
 	t.view.streams.Printf("%s... %s\n", file.Name, colorizeTestStatus(file.Status, t.view.colorize))
 	t.Diagnostics(nil, file, file.Diagnostics)
@@ -455,6 +603,36 @@ func (t *TestJSON) Conclusion(suite *moduletest.Suite) {
 		json.MessageTestSummary, summary)
 }
 
+func (t *TestJSON) CleanupLeftovers(cleaned, remaining int) {
+	t.view.log.Info(
+		fmt.Sprintf("Cleaned up %d leftover states, %d remaining.", cleaned, remaining),
+		"type", json.MessageTestCleanupLeftovers,
+		json.MessageTestCleanupLeftovers, json.TestCleanupLeftovers{Cleaned: cleaned, Remaining: remaining})
+}
+
+func (t *TestJSON) Coverage(coverage *moduletest.Coverage) {
+	overall, kinds := coverage.Totals()
+
+	message := json.TestCoverage{
+		Exercised: overall.Exercised,
+		Total:     overall.Total,
+		Kinds:     make(map[string]json.TestCoverageTotals, len(kinds)),
+	}
+	for kind, totals := range kinds {
+		message.Kinds[string(kind)] = json.TestCoverageTotals{Exercised: totals.Exercised, Total: totals.Total}
+	}
+	for _, item := range coverage.Items() {
+		if item.Runs == 0 {
+			message.NotExercised = append(message.NotExercised, json.NewTestCoverageItem(item))
+		}
+	}
+
+	t.view.log.Info(
+		fmt.Sprintf("Coverage: %d of %d items exercised (%.1f%%).", overall.Exercised, overall.Total, overall.Percent()),
+		"type", json.MessageTestCoverage,
+		json.MessageTestCoverage, message)
+}
+
 func (t *TestJSON) File(file *moduletest.File) {
 	t.view.log.Info(
 		fmt.Sprintf("%s... %s", file.Name, testStatus(file.Status)),
@@ -606,6 +784,17 @@ func (t *TestJSON) FatalInterruptSummary(run *moduletest.Run, file *moduletest.F
 		"@testfile", file.Name)
 }
 
+var coverageKindNames = map[moduletest.CoverageKind]string{
+	moduletest.CoverageResource:           "resources",
+	moduletest.CoverageDataSource:         "data sources",
+	moduletest.CoverageOutput:             "outputs",
+	moduletest.CoverageVariableValidation: "variable validations",
+	moduletest.CoverageCheck:              "check blocks",
+	moduletest.CoveragePrecondition:       "preconditions",
+	moduletest.CoveragePostcondition:      "postconditions",
+	moduletest.CoverageBranch:             "count/for_each branches",
+}
+
 func colorizeTestStatus(status moduletest.Status, color *colorstring.Colorize) string {
 	switch status {
 	case moduletest.Error, moduletest.Fail:
@@ -647,6 +836,11 @@ func SaveErroredTestStateFile(state *states.State, run *moduletest.Run, file *mo
 	//creating an operation to invoke EmergencyDumpState()
 	var op Operation
 	switch v := view.(type) {
+	case *TestBuffer:
+		// We'll write the state file at the same time as the rest of the
+		// output for this test file.
+		v.record(func(view Test) { SaveErroredTestStateFile(state, run, file, view) })
+		return
 	case *TestHuman:
 		op = NewOperation(arguments.ViewHuman, v.view)
 		v.view.streams.Eprint(format.WordWrap("\nWriting state to file: errored_test.tfstate\n", v.view.errorColumns()))
@@ -461,6 +461,37 @@ func TestTestHuman_File(t *testing.T) {
 	}
 }
 
+func TestTestBuffer(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewTest(arguments.ViewOptions{ViewType: arguments.ViewHuman}, NewView(streams))
+
+	first := NewTestBuffer(view)
+	second := NewTestBuffer(view)
+
+	first.File(&moduletest.File{Name: "first.tftest.hcl", Status: moduletest.Pass})
+	second.File(&moduletest.File{Name: "second.tftest.hcl", Status: moduletest.Fail})
+	first.Run(&moduletest.Run{Name: "setup", Status: moduletest.Pass}, &moduletest.File{Name: "first.tftest.hcl"})
+
+	// Interrupts should be written straight away, regardless of when the
+	// buffers are flushed.
+	second.Interrupted()
+
+	first.Flush()
+	second.Flush()
+
+	output := done(t)
+	expected := `first.tftest.hcl... pass
+  run "setup"... pass
+second.tftest.hcl... fail
+`
+	if diff := cmp.Diff(expected, output.Stdout()); len(diff) > 0 {
+		t.Errorf("wrong stdout\n%s", diff)
+	}
+	if !strings.Contains(output.Stderr(), "Interrupt received") {
+		t.Errorf("expected interrupt message in stderr, got:\n%s", output.Stderr())
+	}
+}
+
 func TestTestHuman_Run(t *testing.T) {
 	tcs := map[string]struct {
 		Run    *moduletest.Run
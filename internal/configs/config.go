@@ -947,7 +947,7 @@ func (c *Config) getProviderConfigTransformForTest(evalCtx *hcl.EvalContext) tes
 
 			for _, ref := range run.Providers {
 
-				testProvider, ok := file.getTestProviderOrMock(ref.InParent.String())
+				testProvider, ok := file.getTestProviderOrMock(ref.InParent.String(), evalCtx, c)
 				if !ok {
 					// Then this reference was invalid as we didn't have the
 					// specified provider in the parent. This should have been
@@ -981,6 +981,7 @@ func (c *Config) getProviderConfigTransformForTest(evalCtx *hcl.EvalContext) tes
 					IsMocked:          testProvider.IsMocked,
 					MockResources:     testProvider.MockResources,
 					OverrideResources: testProvider.OverrideResources,
+					MockGenerators:    testProvider.MockGenerators,
 				}
 
 			}
@@ -1009,6 +1010,7 @@ func (c *Config) getProviderConfigTransformForTest(evalCtx *hcl.EvalContext) tes
 					IsMocked:          true,
 					MockResources:     mp.MockResources,
 					OverrideResources: mp.OverrideResources,
+					MockGenerators:    mp.mockGenerators(evalCtx, c),
 				}
 			}
 		}
@@ -1083,11 +1085,7 @@ func (c *Config) transformOverriddenResourcesForTest(run *TestRun, file *TestFil
 		}
 
 		if res.Mode != overrideRes.Mode {
-			// TODO ephemeral testing support - include also the ephemeral resource and the test_file.go#override_ephemeral
-			blockName, targetMode := blockNameOverrideResource, "data"
-			if overrideRes.Mode == addrs.DataResourceMode {
-				blockName, targetMode = blockNameOverrideData, "resource"
-			}
+			blockName, targetMode := overrideRes.getBlockName(), resourceModeBlockName(res.Mode)
 			// It could be a warning, but for the sake of consistent UX let's make it an error
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -64,18 +64,26 @@ func buildTestModules(ctx context.Context, root *Config, walker ModuleWalker) hc
 			// Some examples:
 			//    - file: main.tftest.hcl, run: setup - test.main.setup
 			//    - file: tests/main.tftest.hcl, run: setup - test.tests.main.setup
-
-			dir := filepath.Dir(name)
-			base := filepath.Base(name)
+			//
+			// Run blocks expanded from for_each and test files expanded
+			// from a matrix block all call the same module, so they share
+			// the path of the original run block in the original file. This
+			// also means the keys can't affect the path.
+			filename := file.Filename
+			if filename == "" {
+				filename = name
+			}
+			dir := filepath.Dir(filename)
+			base := filepath.Base(filename)
 
 			path := addrs.Module{}
 			path = append(path, "test")
 			if dir != "." {
 				path = append(path, strings.Split(dir, "/")...)
 			}
-			path = append(path, strings.TrimSuffix(base, ".tftest.hcl"), run.Name)
+			path = append(path, strings.TrimSuffix(base, ".tftest.hcl"), run.BaseName)
 			req := ModuleRequest{
-				Name:              run.Name,
+				Name:              run.BaseName,
 				Path:              path,
 				SourceAddr:        run.Module.Source,
 				SourceAddrRange:   run.Module.SourceDeclRange,
@@ -431,6 +431,49 @@ func TestBuildConfig_WithTestModule(t *testing.T) {
 	}
 }
 
+func TestBuildConfig_WithExpandedTestModule(t *testing.T) {
+	parser := NewParser(nil)
+	mod, diags := parser.LoadConfigDirWithTests("testdata/valid-modules/with-tests-expanded-module", "tests", RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+	if mod == nil {
+		t.Fatal("got nil root module; want non-nil")
+	}
+
+	var paths []string
+	_, diags = BuildConfig(t.Context(), mod, ModuleWalkerFunc(
+		func(_ context.Context, req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+			paths = append(paths, req.Path.String())
+
+			sourcePath := filepath.Join("testdata/valid-modules/with-tests-expanded-module", req.SourceAddr.String())
+			mod, modDiags := parser.LoadConfigDir(sourcePath, req.Call)
+			version, _ := version.NewVersion("1.0.0")
+			return mod, version, modDiags
+		},
+	))
+	assertNoDiagnostics(t, diags)
+
+	// The keys of the matrix and of the for_each argument must not affect
+	// the path of the module, even if they look like paths themselves.
+	sort.Strings(paths)
+	want := []string{
+		"module.test.module.tests.module.main.module.setup",
+		"module.test.module.tests.module.main.module.setup",
+		"module.test.module.tests.module.matrix.module.setup",
+		"module.test.module.tests.module.matrix.module.setup",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("wrong module paths\ngot:  %s\nwant: %s", spew.Sdump(paths), spew.Sdump(want))
+	}
+
+	for name, file := range mod.Tests {
+		if strings.HasPrefix(name, filepath.Join("tests", "matrix.tftest.hcl")) {
+			if got, want := file.Filename, filepath.Join("tests", "matrix.tftest.hcl"); got != want {
+				t.Errorf("wrong filename for %s\ngot:  %s\nwant: %s", name, got, want)
+			}
+		}
+	}
+}
+
 // TestBuildConfig_UninitModuleAndProviderValidation is testing for a very
 // specific interaction between the config loader and the provider reference
 // validation behavior: we need to not attempt any provider reference validation
@@ -0,0 +1,448 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// Function represents a "function" block in a module, which declares a
+// function that can be called as module_fn::<name> by expressions within
+// the same module.
+//
+// Module functions are pure: their result expression can refer only to
+// their own parameters and call other functions, so they always return the
+// same result for the same arguments.
+type Function struct {
+	Name        string
+	Description string
+
+	// Params are the positional parameters of the function, in the order
+	// they are declared.
+	Params []*FunctionParam
+
+	// VariadicParam is the optional parameter that collects any arguments
+	// after the positional ones. It is available to the result expression
+	// as a list of those arguments.
+	VariadicParam *FunctionParam
+
+	// Result is the expression that computes the function's result.
+	Result hcl.Expression
+
+	DeclRange hcl.Range
+}
+
+// FunctionParam represents a "parameter" or "variadic_parameter" block
+// within a function block.
+type FunctionParam struct {
+	Name        string
+	Description string
+	Type        cty.Type
+
+	DeclRange hcl.Range
+}
+
+// moduleFunctionParamsRoot is the name of the object through which the result
+// expression of a function refers to its parameters.
+const moduleFunctionParamsRoot = "param"
+
+func decodeFunctionBlock(block *hcl.Block, override bool) (*Function, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	fn := &Function{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+
+	if override {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Can't override function blocks",
+			Detail:   "Override files cannot override \"function\" blocks.",
+			Subject:  fn.DeclRange.Ptr(),
+		})
+		return nil, diags
+	}
+
+	if !hclsyntax.ValidIdentifier(fn.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid function name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[0],
+		})
+	}
+
+	content, moreDiags := block.Body.Content(functionBlockSchema)
+	diags = append(diags, moreDiags...)
+
+	if attr, exists := content.Attributes["description"]; exists {
+		diags = append(diags, decodeFunctionDescription(attr, &fn.Description)...)
+	}
+
+	names := make(map[string]*FunctionParam)
+	for _, block := range content.Blocks {
+		param, paramDiags := decodeFunctionParamBlock(block)
+		diags = append(diags, paramDiags...)
+		if param == nil {
+			continue
+		}
+
+		if existing, exists := names[param.Name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate function parameter",
+				Detail:   fmt.Sprintf("A parameter named %q was already declared at %s. Parameter names must be unique within a function.", param.Name, existing.DeclRange),
+				Subject:  param.DeclRange.Ptr(),
+			})
+			continue
+		}
+		names[param.Name] = param
+
+		switch block.Type {
+		case "parameter":
+			if fn.VariadicParam != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Parameter after variadic parameter",
+					Detail:   fmt.Sprintf("The variadic parameter %q must be declared after all of the other parameters of the function.", fn.VariadicParam.Name),
+					Subject:  param.DeclRange.Ptr(),
+				})
+				continue
+			}
+			fn.Params = append(fn.Params, param)
+		case "variadic_parameter":
+			if fn.VariadicParam != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple variadic parameters",
+					Detail:   fmt.Sprintf("This function already has a variadic parameter declared at %s. A function can have at most one.", fn.VariadicParam.DeclRange),
+					Subject:  param.DeclRange.Ptr(),
+				})
+				continue
+			}
+			fn.VariadicParam = param
+		}
+	}
+
+	if attr, exists := content.Attributes["result"]; exists {
+		fn.Result = attr.Expr
+		diags = append(diags, fn.validateResult(names)...)
+	}
+
+	return fn, diags
+}
+
+func decodeFunctionParamBlock(block *hcl.Block) (*FunctionParam, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	param := &FunctionParam{
+		Name:      block.Labels[0],
+		Type:      cty.DynamicPseudoType,
+		DeclRange: block.DefRange,
+	}
+
+	if !hclsyntax.ValidIdentifier(param.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid parameter name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[0],
+		})
+		return nil, diags
+	}
+
+	content, moreDiags := block.Body.Content(functionParamBlockSchema)
+	diags = append(diags, moreDiags...)
+
+	if attr, exists := content.Attributes["type"]; exists {
+		ty, tyDiags := typeexpr.TypeConstraint(attr.Expr)
+		diags = append(diags, tyDiags...)
+		if !tyDiags.HasErrors() {
+			param.Type = ty
+		}
+	}
+
+	if attr, exists := content.Attributes["description"]; exists {
+		diags = append(diags, decodeFunctionDescription(attr, &param.Description)...)
+	}
+
+	return param, diags
+}
+
+func decodeFunctionDescription(attr *hcl.Attribute, target *string) hcl.Diagnostics {
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() {
+		return diags
+	}
+	if val.Type() != cty.String || val.IsNull() {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid description",
+			Detail:   "The description must be a string.",
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+	*target = val.AsString()
+	return diags
+}
+
+// validateResult checks that the result expression of the function refers
+// only to the given parameters, and does not call any provider functions,
+// which keeps the function pure.
+func (fn *Function) validateResult(params map[string]*FunctionParam) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, traversal := range fn.Result.Variables() {
+		rng := traversal.SourceRange()
+		if traversal.RootName() != moduleFunctionParamsRoot {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid reference in function",
+				Detail:   fmt.Sprintf("The result of a function can only refer to its parameters, as %s.<name>. Pass any other values the function needs as arguments.", moduleFunctionParamsRoot),
+				Subject:  &rng,
+			})
+			continue
+		}
+
+		if len(traversal) < 2 {
+			continue
+		}
+		var name string
+		switch step := traversal[1].(type) {
+		case hcl.TraverseAttr:
+			name = step.Name
+		case hcl.TraverseIndex:
+			if step.Key.Type() == cty.String && step.Key.IsKnown() && !step.Key.IsNull() {
+				name = step.Key.AsString()
+			}
+		}
+		if _, exists := params[name]; name != "" && !exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Reference to undeclared parameter",
+				Detail:   fmt.Sprintf("The function %q has no parameter named %q.", fn.Name, name),
+				Subject:  &rng,
+			})
+		}
+	}
+
+	for _, call := range fn.calls() {
+		if call.fn.IsNamespace(addrs.FunctionNamespaceProvider) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid function call in function",
+				Detail:   fmt.Sprintf("The result of a function cannot call the provider function %s, because provider functions are only available once their provider is configured.", call.fn),
+				Subject:  call.rng.Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+type functionCall struct {
+	fn  addrs.Function
+	rng hcl.Range
+}
+
+// calls returns the functions called by the result expression of the function.
+func (fn *Function) calls() []functionCall {
+	expr, ok := fn.Result.(hcl.ExpressionWithFunctions)
+	if !ok {
+		return nil
+	}
+
+	var calls []functionCall
+	for _, traversal := range expr.Functions() {
+		calls = append(calls, functionCall{
+			fn:  addrs.ParseFunction(traversal.RootName()),
+			rng: traversal.SourceRange(),
+		})
+	}
+	return calls
+}
+
+// validateFunctions checks that the module functions called by each function
+// of the module exist, and that no function calls itself either directly or
+// through other functions.
+func (m *Module) validateFunctions() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, name := range slices.Sorted(maps.Keys(m.Functions)) {
+		fn := m.Functions[name]
+		if fn.Result == nil {
+			continue
+		}
+		for _, call := range fn.calls() {
+			if !call.fn.IsNamespace(addrs.FunctionNamespaceModule) {
+				continue
+			}
+			if _, exists := m.Functions[call.fn.Name]; !exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Call to unknown function",
+					Detail:   fmt.Sprintf("There is no function named %q declared in this module.", call.fn.Name),
+					Subject:  call.rng.Ptr(),
+				})
+			}
+		}
+
+		if path := m.functionCycle(fn, nil); path != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Recursive function call",
+				Detail:   fmt.Sprintf("The function %q calls itself through %s. Module functions cannot be recursive.", fn.Name, strings.Join(path, " -> ")),
+				Subject:  fn.DeclRange.Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+// functionCycle returns a description of the chain of calls through which
+// the given function eventually calls the first function of the stack, or
+// nil if there is no such chain.
+func (m *Module) functionCycle(fn *Function, stack []string) []string {
+	stack = append(stack, fn.Name)
+	for _, call := range fn.calls() {
+		if !call.fn.IsNamespace(addrs.FunctionNamespaceModule) {
+			continue
+		}
+		if call.fn.Name == stack[0] {
+			return append(slices.Clone(stack), call.fn.Name)
+		}
+		if slices.Contains(stack, call.fn.Name) {
+			// This is a cycle that doesn't involve the first function, which
+			// will be reported for the functions that are part of it.
+			continue
+		}
+		callee, exists := m.Functions[call.fn.Name]
+		if !exists || callee.Result == nil {
+			continue
+		}
+		if path := m.functionCycle(callee, stack); path != nil {
+			return path
+		}
+	}
+	return nil
+}
+
+// FunctionImpls returns the functions declared by the function blocks of the
+// module, keyed by their fully-qualified names, module_fn::<name>.
+//
+// The result expressions of the functions are evaluated with the given table
+// of built-in functions available, along with the other functions of the
+// module.
+func (m *Module) FunctionImpls(builtins map[string]function.Function) map[string]function.Function {
+	if m == nil || len(m.Functions) == 0 {
+		return nil
+	}
+
+	table := maps.Clone(builtins)
+	impls := make(map[string]function.Function, len(m.Functions))
+	for name, fn := range m.Functions {
+		if fn.Result == nil {
+			continue
+		}
+		addr := addrs.Function{Namespaces: []string{addrs.FunctionNamespaceModule}, Name: name}
+		impls[addr.String()] = fn.impl(table)
+	}
+	// The functions are added to the table only once they all exist, and
+	// each function only reads the table when it is called.
+	maps.Copy(table, impls)
+	return impls
+}
+
+func (fn *Function) impl(table map[string]function.Function) function.Function {
+	spec := &function.Spec{
+		Description: fn.Description,
+		Type:        function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			params := make(map[string]cty.Value, len(fn.Params)+1)
+			for i, param := range fn.Params {
+				params[param.Name] = args[i]
+			}
+			if fn.VariadicParam != nil {
+				params[fn.VariadicParam.Name] = fn.variadicValue(args[len(fn.Params):])
+			}
+
+			ctx := &hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					moduleFunctionParamsRoot: cty.ObjectVal(params),
+				},
+				Functions: table,
+			}
+			val, diags := fn.Result.Value(ctx)
+			if diags.HasErrors() {
+				return cty.NilVal, diags
+			}
+			return val, nil
+		},
+	}
+
+	for _, param := range fn.Params {
+		spec.Params = append(spec.Params, *param.spec())
+	}
+	if fn.VariadicParam != nil {
+		spec.VarParam = fn.VariadicParam.spec()
+	}
+	return function.New(spec)
+}
+
+// variadicValue collects the arguments for the variadic parameter into a
+// list, or a tuple when the parameter accepts values of any type and the
+// arguments might not share one.
+func (fn *Function) variadicValue(args []cty.Value) cty.Value {
+	ty := fn.VariadicParam.Type
+	if ty.HasDynamicTypes() {
+		return cty.TupleVal(args)
+	}
+	if len(args) == 0 {
+		return cty.ListValEmpty(ty)
+	}
+	return cty.ListVal(args)
+}
+
+func (param *FunctionParam) spec() *function.Parameter {
+	return &function.Parameter{
+		Name:             param.Name,
+		Description:      param.Description,
+		Type:             param.Type,
+		AllowNull:        true,
+		AllowUnknown:     true,
+		AllowDynamicType: true,
+		AllowMarked:      true,
+	}
+}
+
+var functionBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "description"},
+		{Name: "result", Required: true},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "parameter", LabelNames: []string{"name"}},
+		{Type: "variadic_parameter", LabelNames: []string{"name"}},
+	},
+}
+
+var functionParamBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "type"},
+		{Name: "description"},
+	},
+}
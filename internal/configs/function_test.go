@@ -0,0 +1,100 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestModuleFunctionImpls(t *testing.T) {
+	cfg, diags := testModuleConfigFromFile(t.Context(), "testdata/valid-files/functions.tf")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	mod := cfg.Module
+
+	builtins := map[string]function.Function{
+		"concat":  stdlib.ConcatFunc,
+		"join":    stdlib.JoinFunc,
+		"lower":   stdlib.LowerFunc,
+		"replace": stdlib.ReplaceFunc,
+	}
+	impls := mod.FunctionImpls(builtins)
+
+	tcs := map[string]struct {
+		fn   string
+		args []cty.Value
+		want cty.Value
+	}{
+		"positional only": {
+			fn:   "module_fn::slug",
+			args: []cty.Value{cty.StringVal("My Bucket")},
+			want: cty.StringVal("my-bucket"),
+		},
+		"variadic": {
+			fn:   "module_fn::slug",
+			args: []cty.Value{cty.StringVal("App"), cty.StringVal("eu"), cty.StringVal("1")},
+			want: cty.StringVal("app-eu-1"),
+		},
+		"calls module function": {
+			fn:   "module_fn::bucket_name",
+			args: []cty.Value{cty.StringVal("prod")},
+			want: cty.StringVal("bucket-prod"),
+		},
+		"unknown argument": {
+			fn:   "module_fn::slug",
+			args: []cty.Value{cty.UnknownVal(cty.String)},
+			want: cty.UnknownVal(cty.String).RefineNotNull(),
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			fn, exists := impls[tc.fn]
+			if !exists {
+				t.Fatalf("no function %s", tc.fn)
+			}
+			got, err := fn.Call(tc.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.RawEquals(tc.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := impls["module_fn::slug"].Call(nil); err == nil {
+		t.Errorf("expected an error for a missing argument")
+	}
+}
+
+func TestModuleFunctions_recursion(t *testing.T) {
+	_, diags := testModuleFromDir("testdata/invalid-modules/function-recursion")
+	if !diags.HasErrors() {
+		t.Fatal("expected errors, got none")
+	}
+
+	var got []string
+	for _, diag := range diags {
+		got = append(got, diag.Detail)
+	}
+	want := []string{
+		`The function "even" calls itself through even -> odd -> even. Module functions cannot be recursive.`,
+		`The function "odd" calls itself through odd -> even -> odd. Module functions cannot be recursive.`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("wrong diagnostics\ngot:  %q\nwant: %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("wrong diagnostic %d\ngot:  %s\nwant: %s", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+)
+
+// MockGenerators computes the values of mocked objects from the generators
+// declared in a mock_provider block and its mock_resource, mock_data and
+// mock_ephemeral blocks.
+//
+// Generators are expressions that are evaluated separately for each object,
+// in the scope of the test file with an additional "mock" object describing
+// the object being generated. This allows the values to be realistic enough
+// for modules that parse them, such as ARNs or CIDR blocks, where the
+// placeholder values used otherwise would not be.
+type MockGenerators struct {
+	provider  map[string]hcl.Expression
+	resources []*MockResource
+	evalCtx   *hcl.EvalContext
+
+	// declared lists the resources of each mode and type in the
+	// configuration under test, in declaration order. It is used to number
+	// the generated objects, see sequence.
+	declared map[string][]addrs.ConfigResource
+}
+
+// mockGenerators returns the generators of the mock provider, to be
+// evaluated in the given context for the resources of the given
+// configuration. It returns nil if the mock provider does not declare any
+// generators.
+func (mp *MockProvider) mockGenerators(evalCtx *hcl.EvalContext, config *Config) *MockGenerators {
+	hasGenerators := len(mp.Generators) > 0
+	for _, res := range mp.MockResources {
+		hasGenerators = hasGenerators || len(res.Generators) > 0
+	}
+	if !hasGenerators {
+		return nil
+	}
+
+	if evalCtx == nil {
+		evalCtx = &hcl.EvalContext{}
+	}
+	return &MockGenerators{
+		provider:  mp.Generators,
+		resources: mp.MockResources,
+		evalCtx:   evalCtx,
+		declared:  declaredResources(config),
+	}
+}
+
+// Generate evaluates the generators that apply to the given resource
+// instance, returning the generated values keyed by attribute name. The
+// instances are all the instances of the same resource, across all instances
+// of its module, in the order returned by the instance expander.
+//
+// The generators of the matching mock_resource, mock_data or mock_ephemeral
+// block apply first, then the generators of the mock_provider block for any
+// remaining computed attributes that are not given a default value by that
+// block. Attributes that are set in the configuration are never generated.
+func (g *MockGenerators) Generate(addr addrs.AbsResourceInstance, instances []addrs.AbsResourceInstance, schema *configschema.Block, config cty.Value) (map[string]cty.Value, hcl.Diagnostics) {
+	if g == nil || schema == nil {
+		return nil, nil
+	}
+	var diags hcl.Diagnostics
+
+	resource := addr.Resource.Resource
+	exprs := make(map[string]hcl.Expression)
+	for name, expr := range g.provider {
+		if attr, exists := schema.Attributes[name]; exists && attr.Computed {
+			exprs[name] = expr
+		}
+	}
+	for _, res := range g.resources {
+		if res.Mode != resource.Mode || res.Type != resource.Type {
+			continue
+		}
+		for name := range res.Defaults {
+			delete(exprs, name)
+		}
+		for name, expr := range res.Generators {
+			if attr, exists := schema.Attributes[name]; !exists || !attr.Computed {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid mock generator",
+					Detail:   fmt.Sprintf("The attribute %q is not a computed attribute of %s, so it cannot be generated.", name, resource.Type),
+					Subject:  expr.Range().Ptr(),
+				})
+				continue
+			}
+			exprs[name] = expr
+		}
+		break
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if !config.IsNull() && config.IsKnown() {
+		for name, val := range config.AsValueMap() {
+			if !val.IsNull() {
+				delete(exprs, name)
+			}
+		}
+	}
+	if len(exprs) == 0 {
+		return nil, diags
+	}
+
+	ctx := g.evalCtx.NewChild()
+	ctx.Variables = map[string]cty.Value{
+		"mock": cty.ObjectVal(map[string]cty.Value{
+			"sequence": cty.NumberIntVal(int64(g.sequence(addr, instances))),
+			"type":     cty.StringVal(resource.Type),
+			"address":  cty.StringVal(addr.String()),
+			"config":   config,
+		}),
+	}
+
+	values := make(map[string]cty.Value, len(exprs))
+	for _, name := range slices.Sorted(maps.Keys(exprs)) {
+		expr := exprs[name]
+		val, valDiags := expr.Value(ctx)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			continue
+		}
+
+		val, err := convert.Convert(val, schema.Attributes[name].ImpliedType())
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid mock generator result",
+				Detail:      fmt.Sprintf("The generated value for %q in %s is not suitable: %s.", name, addr, err),
+				Subject:     expr.Range().Ptr(),
+				Expression:  expr,
+				EvalContext: ctx,
+			})
+			continue
+		}
+		values[name] = val
+	}
+	return values, diags
+}
+
+// sequence returns the number of the given resource instance within the
+// sequence of its resource type, which starts at zero.
+//
+// Objects are generated concurrently, so the number cannot depend on the
+// order in which they are generated. Instead, the resources of each type are
+// numbered in declaration order, and the instances of each resource take
+// every nth number from there, where n is the number of resources of that
+// type. An instance therefore keeps the same number for as long as the
+// configuration and the expansion of its resource don't change, so that
+// planning it again produces the same values.
+func (g *MockGenerators) sequence(addr addrs.AbsResourceInstance, instances []addrs.AbsResourceInstance) int {
+	resource := addr.ConfigResource()
+	declared := g.declared[resourceTypeKey(resource.Resource)]
+	pos := slices.IndexFunc(declared, resource.Equal)
+	if pos < 0 {
+		// This shouldn't happen, as we only generate objects for resources
+		// in the configuration under test, but we'll still hand out a number
+		// that can't clash with the others.
+		pos = len(declared)
+		declared = append(slices.Clip(declared), resource)
+	}
+
+	ix := max(slices.IndexFunc(instances, addr.Equal), 0)
+	return ix*len(declared) + pos
+}
+
+// declaredResources returns the resources of each mode and type within the
+// given configuration and its descendants, in declaration order. The root
+// module comes first, followed by the other modules in order of their paths.
+func declaredResources(config *Config) map[string][]addrs.ConfigResource {
+	declared := make(map[string][]addrs.ConfigResource)
+	if config == nil {
+		return declared
+	}
+
+	modules := config.AllModules()
+	slices.SortFunc(modules, func(a, b *Config) int {
+		return strings.Compare(a.Path.String(), b.Path.String())
+	})
+	for _, module := range modules {
+		var resources []*Resource
+		for _, rs := range []map[string]*Resource{module.Module.ManagedResources, module.Module.DataResources, module.Module.EphemeralResources} {
+			resources = slices.AppendSeq(resources, maps.Values(rs))
+		}
+		slices.SortFunc(resources, func(a, b *Resource) int {
+			if c := strings.Compare(a.DeclRange.Filename, b.DeclRange.Filename); c != 0 {
+				return c
+			}
+			return a.DeclRange.Start.Byte - b.DeclRange.Start.Byte
+		})
+		for _, r := range resources {
+			key := resourceTypeKey(r.Addr())
+			declared[key] = append(declared[key], r.Addr().InModule(module.Path))
+		}
+	}
+	return declared
+}
+
+// resourceTypeKey returns the key of the sequence that objects of the given
+// resource belong to. Each mode and type has its own sequence.
+func resourceTypeKey(resource addrs.Resource) string {
+	return resource.Mode.String() + "." + resource.Type
+}
+
+// decodeMockGenerators decodes the generators attribute of a mock_provider,
+// mock_resource, mock_data or mock_ephemeral block. The attribute must be an
+// object constructor, whose values are kept as expressions to be evaluated
+// for each object later.
+func decodeMockGenerators(attr *hcl.Attribute) (map[string]hcl.Expression, hcl.Diagnostics) {
+	pairs, diags := hcl.ExprMap(attr.Expr)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	generators := make(map[string]hcl.Expression, len(pairs))
+	for _, pair := range pairs {
+		name := hcl.ExprAsKeyword(pair.Key)
+		if name == "" {
+			key, keyDiags := pair.Key.Value(nil)
+			diags = append(diags, keyDiags...)
+			if keyDiags.HasErrors() {
+				continue
+			}
+			if key.Type() != cty.String || !key.IsKnown() || key.IsNull() {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid mock generator",
+					Detail:   "The name of each generator must be the name of an attribute.",
+					Subject:  pair.Key.Range().Ptr(),
+				})
+				continue
+			}
+			name = key.AsString()
+		}
+
+		if _, exists := generators[name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate mock generator",
+				Detail:   fmt.Sprintf("A generator for %q is already defined in this block.", name),
+				Subject:  pair.Key.Range().Ptr(),
+			})
+			continue
+		}
+		generators[name] = pair.Value
+	}
+	return generators, diags
+}
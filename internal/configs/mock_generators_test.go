@@ -0,0 +1,257 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/lang"
+)
+
+func TestMockGenerators(t *testing.T) {
+	src := `
+mock_provider "test" {
+  generators = {
+    arn  = "arn:${mock.type}:${format("%03d", mock.sequence)}"
+    name = "provider"
+  }
+
+  mock_resource "test_resource" {
+    defaults = {
+      arn = "default"
+    }
+    generators = {
+      cidr = cidrsubnet(var.base, 8, mock.sequence)
+    }
+  }
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	tf, diags := loadTestFile(file.Body)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	parser := testParser(map[string]string{
+		"main.tf": `
+resource "test_resource" "a" {
+  count = 2
+}
+
+resource "test_resource" "b" {
+}
+
+data "test_resource" "a" {
+}
+`,
+	})
+	configFile, diags := parser.LoadConfigFile("main.tf")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	mod, diags := NewModule([]*File{configFile}, nil, RootModuleCallForTesting(), "main.tf", SelectiveLoadAll)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	config, diags := BuildConfig(t.Context(), mod, nil)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	evalCtx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(map[string]cty.Value{
+				"base": cty.StringVal("10.0.0.0/8"),
+			}),
+		},
+		Functions: (&lang.Scope{}).Functions(),
+	}
+	generators := tf.MockProviders["test"].mockGenerators(evalCtx, config)
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"arn":  {Type: cty.String, Computed: true},
+			"cidr": {Type: cty.String, Computed: true},
+			"name": {Type: cty.String, Optional: true, Computed: true},
+		},
+	}
+	resource := func(mode addrs.ResourceMode, name string) addrs.Resource {
+		return addrs.Resource{Mode: mode, Type: "test_resource", Name: name}
+	}
+	instances := map[addrs.Resource][]addrs.AbsResourceInstance{
+		resource(addrs.ManagedResourceMode, "a"): {
+			resource(addrs.ManagedResourceMode, "a").Instance(addrs.IntKey(0)).Absolute(addrs.RootModuleInstance),
+			resource(addrs.ManagedResourceMode, "a").Instance(addrs.IntKey(1)).Absolute(addrs.RootModuleInstance),
+		},
+		resource(addrs.ManagedResourceMode, "b"): {
+			resource(addrs.ManagedResourceMode, "b").Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+		},
+		resource(addrs.DataResourceMode, "a"): {
+			resource(addrs.DataResourceMode, "a").Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+		},
+	}
+	generate := func(addr addrs.AbsResourceInstance, config cty.Value) map[string]string {
+		t.Helper()
+		values, diags := generators.Generate(addr, instances[addr.Resource.Resource], schema, config)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		ret := make(map[string]string)
+		for name, val := range values {
+			ret[name] = val.AsString()
+		}
+		return ret
+	}
+	noConfig := cty.NullVal(schema.ImpliedType())
+
+	tcs := []struct {
+		addr   addrs.AbsResourceInstance
+		config cty.Value
+		want   map[string]string
+	}{
+		{
+			// The numbers don't depend on the order the objects are
+			// generated in. The instances of each resource take every
+			// other number, as there are two resources of this type.
+			addr:   instances[resource(addrs.ManagedResourceMode, "a")][1],
+			config: noConfig,
+			want:   map[string]string{"cidr": "10.2.0.0/16", "name": "provider"},
+		},
+		{
+			addr: instances[resource(addrs.ManagedResourceMode, "b")][0],
+			config: cty.ObjectVal(map[string]cty.Value{
+				"arn":  cty.NullVal(cty.String),
+				"cidr": cty.NullVal(cty.String),
+				"name": cty.StringVal("configured"),
+			}),
+			want: map[string]string{"cidr": "10.1.0.0/16"},
+		},
+		{
+			addr:   instances[resource(addrs.ManagedResourceMode, "a")][0],
+			config: noConfig,
+			want:   map[string]string{"cidr": "10.0.0.0/16", "name": "provider"},
+		},
+		{
+			// Generating the same object again gives the same values.
+			addr:   instances[resource(addrs.ManagedResourceMode, "a")][1],
+			config: noConfig,
+			want:   map[string]string{"cidr": "10.2.0.0/16", "name": "provider"},
+		},
+		{
+			// Each resource type has its own sequence.
+			addr:   instances[resource(addrs.DataResourceMode, "a")][0],
+			config: noConfig,
+			want:   map[string]string{"arn": "arn:test_resource:000", "name": "provider"},
+		},
+	}
+	for _, tc := range tcs {
+		if diff := cmp.Diff(tc.want, generate(tc.addr, tc.config)); diff != "" {
+			t.Errorf("wrong values for %s\n%s", tc.addr, diff)
+		}
+	}
+}
+
+func TestMockGenerators_errors(t *testing.T) {
+	tcs := map[string]struct {
+		src  string
+		want string
+	}{
+		"conflicting default": {
+			src: `
+mock_provider "test" {
+  mock_resource "test_resource" {
+    defaults = {
+      arn = "default"
+    }
+    generators = {
+      arn = "generated"
+    }
+  }
+}
+`,
+			want: "Conflicting mock values",
+		},
+		"duplicate generator": {
+			src: `
+mock_provider "test" {
+  generators = {
+    arn   = "a"
+    "arn" = "b"
+  }
+}
+`,
+			want: "Duplicate mock generator",
+		},
+		"not an object": {
+			src: `
+mock_provider "test" {
+  generators = "arn"
+}
+`,
+			want: "Invalid expression",
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			_, diags = loadTestFile(file.Body)
+			if !diags.HasErrors() {
+				t.Fatal("expected errors, got none")
+			}
+			if got := diags[0].Summary; got != tc.want {
+				t.Errorf("wrong summary %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMockGenerators_notComputed(t *testing.T) {
+	src := `
+mock_provider "test" {
+  mock_resource "test_resource" {
+    generators = {
+      value = "generated"
+    }
+  }
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	tf, diags := loadTestFile(file.Body)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"value": {Type: cty.String, Optional: true},
+		},
+	}
+	addr := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_resource", Name: "x"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	_, diags = tf.MockProviders["test"].mockGenerators(nil, nil).Generate(addr, []addrs.AbsResourceInstance{addr}, schema, cty.NullVal(schema.ImpliedType()))
+	if !diags.HasErrors() {
+		t.Fatal("expected errors, got none")
+	}
+	if got, want := diags[0].Summary, "Invalid mock generator"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+}
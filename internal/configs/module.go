@@ -55,6 +55,10 @@ type Module struct {
 
 	Checks map[string]*Check
 
+	// Functions are the functions declared by "function" blocks, keyed by
+	// name. They can be called as module_fn::<name> within the module.
+	Functions map[string]*Function
+
 	Tests map[string]*TestFile
 
 	// IsOverridden indicates if the module is being overridden. It's used in
@@ -111,6 +115,8 @@ type File struct {
 	Removed []*Removed
 
 	Checks []*Check
+
+	Functions []*Function
 }
 
 // SelectiveLoader allows the consumer to only load and validate the portions of files needed for the given operations/contexts
@@ -178,6 +184,7 @@ func NewModuleUneval(primaryFiles, overrideFiles []*File, sourceDir string, load
 		DataResources:      map[string]*Resource{},
 		EphemeralResources: map[string]*Resource{},
 		Checks:             map[string]*Check{},
+		Functions:          map[string]*Function{},
 		ProviderMetas:      map[addrs.Provider]*ProviderMeta{},
 		Tests:              map[string]*TestFile{},
 		SourceDir:          sourceDir,
@@ -232,6 +239,8 @@ func NewModuleUneval(primaryFiles, overrideFiles []*File, sourceDir string, load
 		diags = append(diags, fileDiags...)
 	}
 
+	diags = append(diags, mod.validateFunctions()...)
+
 	return mod, diags
 }
 
@@ -599,6 +608,19 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 
 	m.Removed = append(m.Removed, file.Removed...)
 
+	for _, fn := range file.Functions {
+		if existing, exists := m.Functions[fn.Name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate function",
+				Detail:   fmt.Sprintf("A function named %q was already declared at %s. Function names must be unique within a module.", existing.Name, existing.DeclRange),
+				Subject:  &fn.DeclRange,
+			})
+			continue
+		}
+		m.Functions[fn.Name] = fn
+	}
+
 	return diags
 }
 
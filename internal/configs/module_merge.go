@@ -168,6 +168,11 @@ func (o *Output) merge(oo *Output) hcl.Diagnostics {
 		o.EphemeralSet = oo.EphemeralSet
 		o.Ephemeral = oo.Ephemeral
 	}
+	if oo.Type != cty.NilType {
+		o.Type = oo.Type
+		o.ConstraintType = oo.ConstraintType
+		o.TypeDefaults = oo.TypeDefaults
+	}
 
 	// We don't allow depends_on to be overridden because that is likely to
 	// cause confusing misbehavior.
@@ -528,3 +528,19 @@ func TestModuleOverrideIgnoreAllChanges(t *testing.T) {
 		t.Fatalf("wrong result: expected r.Managed.IgnoreAllChanges to be true")
 	}
 }
+
+func TestModuleOverrideOutputType(t *testing.T) {
+	mod, diags := testModuleFromDir("testdata/valid-modules/override-output-type")
+	assertNoDiagnostics(t, diags)
+	if mod == nil {
+		t.Fatalf("module is nil")
+	}
+
+	got := mod.Outputs["typed"]
+	if want := cty.Set(cty.String); !got.Type.Equals(want) {
+		t.Errorf("wrong type %#v; want %#v", got.Type, want)
+	}
+	if want := cty.Set(cty.String); !got.ConstraintType.Equals(want) {
+		t.Errorf("wrong constraint type %#v; want %#v", got.ConstraintType, want)
+	}
+}
@@ -494,6 +494,14 @@ type Output struct {
 	Deprecated  string
 	Ephemeral   bool
 
+	// Type is the concrete type of the output value, or cty.NilType if the
+	// output has no type constraint.
+	Type cty.Type
+	// ConstraintType is used for type conversions, and may contain nested
+	// ObjectWithOptionalAttr types.
+	ConstraintType cty.Type
+	TypeDefaults   *typeexpr.Defaults
+
 	Preconditions []*CheckRule
 
 	DescriptionSet bool
@@ -546,6 +554,14 @@ func decodeOutputBlock(block *hcl.Block, override bool) (*Output, hcl.Diagnostic
 		o.Expr = attr.Expr
 	}
 
+	if attr, exists := content.Attributes["type"]; exists {
+		ty, tyDefaults, _, tyDiags := decodeVariableType(attr.Expr)
+		diags = append(diags, tyDiags...)
+		o.ConstraintType = ty
+		o.TypeDefaults = tyDefaults
+		o.Type = ty.WithoutOptionalAttributesDeep()
+	}
+
 	if attr, exists := content.Attributes["sensitive"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Sensitive)
 		diags = append(diags, valDiags...)
@@ -703,6 +719,9 @@ var outputBlockSchema = &hcl.BodySchema{
 			Name:     "value",
 			Required: true,
 		},
+		{
+			Name: "type",
+		},
 		{
 			Name: "depends_on",
 		},
@@ -236,6 +236,13 @@ func loadConfigFileBody(body hcl.Body, _ string, override bool) (*File, hcl.Diag
 				file.Removed = append(file.Removed, cfg)
 			}
 
+		case "function":
+			cfg, cfgDiags := decodeFunctionBlock(block, override)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				file.Functions = append(file.Functions, cfg)
+			}
+
 		default:
 			// Should never happen because the above cases should be exhaustive
 			// for all block type names in our schema.
@@ -305,6 +312,10 @@ var configFileSchema = &hcl.BodySchema{
 		{
 			Type: "removed",
 		},
+		{
+			Type:       "function",
+			LabelNames: []string{"name"},
+		},
 		{
 			Type: "terraform",
 		},
@@ -325,6 +325,15 @@ func (p *Parser) loadTestFiles(basePath string, paths []string) (map[string]*Tes
 				})
 				continue
 			}
+			tf.Filename = relPath
+			if files := tf.expandMatrix(); files != nil {
+				// Each combination of the matrix is tested as if it were a
+				// separate file, so they each have their own state.
+				for key, file := range files {
+					tfs[fmt.Sprintf("%s[%s]", relPath, key)] = file
+				}
+				continue
+			}
 			tfs[relPath] = tf
 		}
 	}
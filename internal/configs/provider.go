@@ -47,6 +47,7 @@ type Provider struct {
 	IsMocked          bool
 	MockResources     []*MockResource
 	OverrideResources []*OverrideResource
+	MockGenerators    *MockGenerators
 
 	ForEach   hcl.Expression
 	Instances map[addrs.InstanceKey]instances.RepetitionData
@@ -30,6 +30,8 @@ func newStaticScope(eval *StaticEvaluator, stack0 StaticIdentifier, stack ...Sta
 		BaseDir:     ".", // Always current working directory for now. (same as Evaluator.Scope())
 		PureOnly:    false,
 		ConsoleMode: false,
+
+		ModuleFunctions: eval.cfg.FunctionImpls,
 	}
 }
 
@@ -7,15 +7,25 @@ package configs
 
 import (
 	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/instances"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/lang/evalchecks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -76,9 +86,51 @@ type TestFile struct {
 	// with Providers map to use later when instantiating provider instance.
 	MockProviders map[string]*MockProvider
 
+	// Matrix contains the each.key and each.value for every combination of
+	// values in the matrix block of this file, if it has one. Test files with
+	// a matrix block are expanded into one test file for each combination
+	// when they are loaded, so this is only set on the original file.
+	Matrix []instances.RepetitionData
+
+	// Cleanup configures how the infrastructure created by the run blocks
+	// of this file is destroyed once they have all executed. It is nil if
+	// the file has no cleanup block, in which case the defaults apply.
+	Cleanup *TestCleanup
+
+	// Filename is the path of the test file relative to the module under
+	// test. Test files expanded from a matrix block keep the path of the
+	// original file, so it is safe to build other paths from it whatever
+	// the keys of the matrix are.
+	Filename string
+
 	VariablesDeclRange hcl.Range
+	MatrixDeclRange    hcl.Range
+
+	// expandedRuns records the keys of the run blocks that were expanded
+	// using for_each, by the name of the original run block.
+	expandedRuns map[string]map[string]bool
+}
+
+// TestCleanup represents the cleanup block of a test file.
+type TestCleanup struct {
+	// Retries is the number of times to try destroying the infrastructure
+	// again if the first attempt fails. RetryDelay is how long to wait before
+	// the first retry, and is doubled before each subsequent retry.
+	Retries    int
+	RetryDelay time.Duration
+
+	// SkipCleanup leaves the infrastructure in place so it can be inspected,
+	// recording the states so it can be destroyed by a later execution of
+	// tofu test -cleanup-leftovers.
+	SkipCleanup bool
+
+	DeclRange hcl.Range
 }
 
+// DefaultTestCleanupRetryDelay is the delay before retrying a failed cleanup
+// when the cleanup block of a test file does not set retry_delay.
+const DefaultTestCleanupRetryDelay = 5 * time.Second
+
 // Validate does a very simple and cursory check across the file blocks to look
 // for simple issues we can highlight early on. It doesn't validate nested run blocks.
 func (file *TestFile) Validate() tfdiags.Diagnostics {
@@ -92,7 +144,7 @@ func (file *TestFile) Validate() tfdiags.Diagnostics {
 	return diags
 }
 
-func (file *TestFile) getTestProviderOrMock(addr string) (*Provider, bool) {
+func (file *TestFile) getTestProviderOrMock(addr string, evalCtx *hcl.EvalContext, config *Config) (*Provider, bool) {
 	testProvider, ok := file.Providers[addr]
 	if ok {
 		return testProvider, true
@@ -111,6 +163,7 @@ func (file *TestFile) getTestProviderOrMock(addr string) (*Provider, bool) {
 			IsMocked:          true,
 			MockResources:     mockProvider.MockResources,
 			OverrideResources: mockProvider.OverrideResources,
+			MockGenerators:    mockProvider.mockGenerators(evalCtx, config),
 		}
 
 		return p, true
@@ -179,6 +232,11 @@ type TestRun struct {
 	// run.
 	ExpectFailures []hcl.Traversal
 
+	// ExpectDiagnostics describes diagnostics that are expected to be
+	// produced while executing this run block. Unlike ExpectFailures, these
+	// can match any diagnostic, such as provider errors or warnings.
+	ExpectDiagnostics []*TestExpectedDiagnostic
+
 	// OverrideResources is a list of resources to be overridden with static values.
 	// Underlying providers shouldn't be called for overridden resources.
 	OverrideResources []*OverrideResource
@@ -187,6 +245,36 @@ type TestRun struct {
 	// Underlying modules shouldn't be called.
 	OverrideModules []*OverrideModule
 
+	// ExpectPlanSnapshot is the path, relative to the directory containing the
+	// test file, of a golden file that the plan for this run block should
+	// match. It is empty if the plan should not be compared to a snapshot.
+	ExpectPlanSnapshot          string
+	ExpectPlanSnapshotDeclRange hcl.Range
+
+	// Parallel allows this run block to be executed concurrently with any
+	// directly adjacent run blocks that also set it, as long as they do not
+	// share state and do not reference each other's outputs.
+	Parallel bool
+
+	// ForEach is the for_each argument of the run block, if set. Run blocks
+	// with for_each are expanded into one run block for each element when
+	// the test file is loaded, and each expanded run block is named after
+	// the original with the element key in brackets.
+	//
+	// As with resources, later run blocks refer to the outputs of expanded
+	// run blocks using the name of the original run block and the element
+	// key, such as run.name["key"].
+	ForEach hcl.Expression
+
+	// BaseName is the name of the original run block that this run block was
+	// expanded from using for_each, or the same as Name otherwise.
+	BaseName string
+
+	// Each holds the values for each.key and each.value within this run
+	// block, if it was expanded from a for_each argument or from the matrix
+	// block of its test file.
+	Each instances.RepetitionData
+
 	NameDeclRange      hcl.Range
 	VariablesDeclRange hcl.Range
 	DeclRange          hcl.Range
@@ -261,19 +349,19 @@ type TestRunOptions struct {
 }
 
 const (
-	blockNameOverrideResource = "override_resource"
-	blockNameOverrideData     = "override_data"
-	// TODO ephemeral testing support - uncomment this when testing support will be added for ephemerals
-	// blockNameOverrideEphemeral = "override_ephemeral"
+	blockNameOverrideResource  = "override_resource"
+	blockNameOverrideData      = "override_data"
+	blockNameOverrideEphemeral = "override_ephemeral"
 )
 
-// OverrideResource contains information about a resource or data block to be overridden.
+// OverrideResource contains information about a resource, data or ephemeral
+// block to be overridden.
 type OverrideResource struct {
-	// Target references resource or data block to override.
+	// Target references resource, data or ephemeral block to override.
 	Target       hcl.Traversal
 	TargetParsed *addrs.ConfigResource
 
-	// Mode indicates if the Target is resource or data block.
+	// Mode indicates if the Target is resource, data or ephemeral block.
 	Mode addrs.ResourceMode
 
 	// Values represents fields to use as defaults
@@ -287,6 +375,8 @@ func (r OverrideResource) getBlockName() string {
 		return blockNameOverrideResource
 	case addrs.DataResourceMode:
 		return blockNameOverrideData
+	case addrs.EphemeralResourceMode:
+		return blockNameOverrideEphemeral
 	case addrs.InvalidResourceMode:
 		panic("BUG: invalid resource mode in override resource")
 	default:
@@ -294,6 +384,21 @@ func (r OverrideResource) getBlockName() string {
 	}
 }
 
+// resourceModeBlockName returns the name of the configuration block used to
+// declare resources of the given mode.
+func resourceModeBlockName(mode addrs.ResourceMode) string {
+	switch mode {
+	case addrs.ManagedResourceMode:
+		return "resource"
+	case addrs.DataResourceMode:
+		return "data"
+	case addrs.EphemeralResourceMode:
+		return "ephemeral"
+	default:
+		panic("BUG: unsupported resource mode: " + mode.String())
+	}
+}
+
 const blockNameOverrideModule = "override_module"
 
 // OverrideModule contains information about a module to be overridden.
@@ -329,6 +434,10 @@ type MockProvider struct {
 
 	MockResources     []*MockResource
 	OverrideResources []*OverrideResource
+
+	// Generators compute the values of the named computed attributes for
+	// every resource type of the provider. See MockGenerators.
+	Generators map[string]hcl.Expression
 }
 
 // moduleUniqueKey is copied from Provider.moduleUniqueKey
@@ -342,14 +451,14 @@ func (mp *MockProvider) moduleUniqueKey() string {
 func (mp *MockProvider) validateMockResources() hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
-	managedResources := make(map[string]struct{})
-	dataResources := make(map[string]struct{})
+	resourcesByMode := map[addrs.ResourceMode]map[string]struct{}{
+		addrs.ManagedResourceMode:   make(map[string]struct{}),
+		addrs.DataResourceMode:      make(map[string]struct{}),
+		addrs.EphemeralResourceMode: make(map[string]struct{}),
+	}
 
 	for _, res := range mp.MockResources {
-		resources := managedResources
-		if res.Mode == addrs.DataResourceMode {
-			resources = dataResources
-		}
+		resources := resourcesByMode[res.Mode]
 
 		if _, ok := resources[res.Type]; ok {
 			diags = append(diags, &hcl.Diagnostic{
@@ -392,8 +501,9 @@ func (mp *MockProvider) validateOverrideResources() hcl.Diagnostics {
 }
 
 const (
-	blockNameMockResource = "mock_resource"
-	blockNameMockData     = "mock_data"
+	blockNameMockResource  = "mock_resource"
+	blockNameMockData      = "mock_data"
+	blockNameMockEphemeral = "mock_ephemeral"
 )
 
 // MockResource represents mocked resource. It is similar to OverrideResource,
@@ -402,6 +512,10 @@ type MockResource struct {
 	Mode     addrs.ResourceMode
 	Type     string
 	Defaults map[string]cty.Value
+
+	// Generators compute the values of the named computed attributes
+	// separately for each object. See MockGenerators.
+	Generators map[string]hcl.Expression
 }
 
 func (r MockResource) getBlockName() string {
@@ -410,6 +524,8 @@ func (r MockResource) getBlockName() string {
 		return blockNameMockResource
 	case addrs.DataResourceMode:
 		return blockNameMockData
+	case addrs.EphemeralResourceMode:
+		return blockNameMockEphemeral
 	case addrs.InvalidResourceMode:
 		panic("BUG: invalid resource mode in mock resource")
 	default:
@@ -433,9 +549,55 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 		case "run":
 			run, runDiags := decodeTestRunBlock(block)
 			diags = append(diags, runDiags...)
-			if !runDiags.HasErrors() {
+			if runDiags.HasErrors() {
+				continue
+			}
+			if run.ForEach == nil {
 				tf.Runs = append(tf.Runs, run)
+				continue
+			}
+			runs, expandDiags := run.expand()
+			diags = append(diags, expandDiags...)
+			tf.Runs = append(tf.Runs, runs...)
+			if tf.expandedRuns == nil {
+				tf.expandedRuns = make(map[string]map[string]bool)
 			}
+			keys := make(map[string]bool, len(runs))
+			for _, run := range runs {
+				keys[run.Each.EachKey.AsString()] = true
+			}
+			tf.expandedRuns[run.Name] = keys
+
+		case "matrix":
+			if tf.Matrix != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple \"matrix\" blocks",
+					Detail:   fmt.Sprintf("This test file already has a matrix block defined at %s.", tf.MatrixDeclRange),
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			matrix, matrixDiags := decodeTestMatrixBlock(block)
+			diags = append(diags, matrixDiags...)
+			tf.Matrix = matrix
+			tf.MatrixDeclRange = block.DefRange
+
+		case "cleanup":
+			if tf.Cleanup != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple \"cleanup\" blocks",
+					Detail:   fmt.Sprintf("This test file already has a cleanup block defined at %s.", tf.Cleanup.DeclRange),
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			cleanup, cleanupDiags := decodeTestCleanupBlock(block)
+			diags = append(diags, cleanupDiags...)
+			tf.Cleanup = cleanup
 
 		case "variables":
 			if tf.Variables != nil {
@@ -464,7 +626,7 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 				tf.Providers[provider.moduleUniqueKey()] = provider
 			}
 
-		case blockNameOverrideResource, blockNameOverrideData:
+		case blockNameOverrideResource, blockNameOverrideData, blockNameOverrideEphemeral:
 			overrideRes, overrideResDiags := decodeOverrideResourceBlock(block)
 			diags = append(diags, overrideResDiags...)
 			if !overrideResDiags.HasErrors() {
@@ -499,6 +661,24 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 		}
 	}
 
+	if tf.Matrix != nil {
+		// Both the matrix block and the for_each argument set the each
+		// object, so they can't be used together.
+		for _, run := range tf.Runs {
+			if run.ForEach != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid for_each argument",
+					Detail:   fmt.Sprintf("Run blocks cannot use for_each in a test file with a matrix block, as both set the each object. The matrix block is defined at %s.", tf.MatrixDeclRange),
+					Subject:  run.ForEach.Range().Ptr(),
+				})
+				break
+			}
+		}
+	}
+
+	diags = append(diags, tf.validateRunReferences()...)
+
 	return &tf, diags
 }
 
@@ -510,6 +690,7 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 
 	r := TestRun{
 		Name:          block.Labels[0],
+		BaseName:      block.Labels[0],
 		NameDeclRange: block.LabelRanges[0],
 		DeclRange:     block.DefRange,
 	}
@@ -582,7 +763,7 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 				r.Module = module
 			}
 
-		case blockNameOverrideResource, blockNameOverrideData:
+		case blockNameOverrideResource, blockNameOverrideData, blockNameOverrideEphemeral:
 			overrideRes, overrideResDiags := decodeOverrideResourceBlock(block)
 			diags = append(diags, overrideResDiags...)
 			if !overrideResDiags.HasErrors() {
@@ -595,6 +776,13 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 			if !overrideModDiags.HasErrors() {
 				r.OverrideModules = append(r.OverrideModules, overrideMod)
 			}
+
+		case "expect_diagnostics":
+			expected, expectedDiags := decodeTestExpectedDiagnosticBlock(block)
+			diags = append(diags, expectedDiags...)
+			if !expectedDiags.HasErrors() {
+				r.ExpectDiagnostics = append(r.ExpectDiagnostics, expected)
+			}
 		}
 	}
 
@@ -644,9 +832,343 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 		r.ExpectFailures = failures
 	}
 
+	if attr, exists := content.Attributes["expect_plan_snapshot"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &r.ExpectPlanSnapshot)...)
+		r.ExpectPlanSnapshotDeclRange = attr.Range
+		if !diags.HasErrors() && r.ExpectPlanSnapshot == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"expect_plan_snapshot\" path",
+				Detail:   "The \"expect_plan_snapshot\" argument must be the path of a snapshot file.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	if attr, exists := content.Attributes["parallel"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &r.Parallel)...)
+	}
+
+	if attr, exists := content.Attributes["for_each"]; exists {
+		r.ForEach = attr.Expr
+	}
+
 	return &r, diags
 }
 
+// expand returns one copy of the run block for each element of its for_each
+// argument, ordered by their keys.
+func (run *TestRun) expand() ([]*TestRun, hcl.Diagnostics) {
+	forEach, diags := evalchecks.EvaluateForEachExpression(run.ForEach, staticTestContext("run block for_each"), nil)
+	if diags.HasErrors() {
+		return nil, diags.ToHCL()
+	}
+
+	keys := make([]string, 0, len(forEach))
+	for key := range forEach {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	runs := make([]*TestRun, 0, len(keys))
+	for _, key := range keys {
+		expanded := *run
+		expanded.Name = fmt.Sprintf("%s[%s]", run.Name, key)
+		expanded.BaseName = run.Name
+		expanded.Each = instances.RepetitionData{
+			EachKey:   cty.StringVal(key),
+			EachValue: forEach[key],
+		}
+		runs = append(runs, &expanded)
+	}
+	return runs, diags.ToHCL()
+}
+
+// validateRunReferences checks that references to the outputs of run blocks
+// that were expanded using for_each use the name of the original run block
+// and one of its keys, such as run.name["key"], rather than the name of an
+// expanded run block.
+func (file *TestFile) validateRunReferences() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if len(file.expandedRuns) == 0 {
+		return diags
+	}
+
+	var exprs []hcl.Expression
+	for _, expr := range file.Variables {
+		exprs = append(exprs, expr)
+	}
+	seen := make(map[string]bool)
+	for _, run := range file.Runs {
+		// Expanded run blocks share the expressions of the original, so we
+		// only need to check them once.
+		if seen[run.BaseName] {
+			continue
+		}
+		seen[run.BaseName] = true
+		for _, expr := range run.Variables {
+			exprs = append(exprs, expr)
+		}
+		for _, rule := range run.CheckRules {
+			exprs = append(exprs, rule.Condition, rule.ErrorMessage)
+		}
+	}
+
+	traversals := file.ProviderTraversals()
+	for _, expr := range exprs {
+		if expr != nil {
+			traversals = append(traversals, expr.Variables()...)
+		}
+	}
+
+	for _, traversal := range traversals {
+		if traversal.RootName() != "run" || len(traversal) < 2 {
+			continue
+		}
+		name, ok := traversalStepKey(traversal[1])
+		if !ok {
+			continue
+		}
+
+		if base, _, found := strings.Cut(name, "["); found && file.expandedRuns[base] != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid run block reference",
+				Detail:   fmt.Sprintf("The run block %q uses for_each, so its outputs must be referred to by the name of the run block and a key, such as run.%s[\"key\"].", base, base),
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+			continue
+		}
+
+		keys, expanded := file.expandedRuns[name]
+		if !expanded || len(traversal) < 3 {
+			continue
+		}
+		if key, ok := traversalStepKey(traversal[2]); ok && !keys[key] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid run block key",
+				Detail:   fmt.Sprintf("The for_each argument of the run block %q does not have an element with the key %q.", name, key),
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+		}
+	}
+	return diags
+}
+
+// ProviderTraversals returns the traversals referred to by the provider and
+// mock_provider blocks within the test file. Provider blocks are evaluated
+// for each run block, so these can refer to the outputs of earlier run
+// blocks.
+func (file *TestFile) ProviderTraversals() []hcl.Traversal {
+	var traversals []hcl.Traversal
+	for _, provider := range file.Providers {
+		traversals = append(traversals, bodyTraversals(provider.Config)...)
+		if provider.ForEach != nil {
+			traversals = append(traversals, provider.ForEach.Variables()...)
+		}
+	}
+	for _, provider := range file.MockProviders {
+		if provider.ForEach != nil {
+			traversals = append(traversals, provider.ForEach.Variables()...)
+		}
+	}
+	return traversals
+}
+
+// bodyTraversals returns the traversals referred to by the attributes of the
+// given body and any blocks nested within it.
+func bodyTraversals(body hcl.Body) []hcl.Traversal {
+	if wrapped, ok := body.(testProviderBody); ok {
+		body = wrapped.originalBody
+	}
+	if body == nil {
+		return nil
+	}
+
+	var traversals []hcl.Traversal
+	if syntaxBody, ok := body.(*hclsyntax.Body); ok {
+		for _, attr := range syntaxBody.Attributes {
+			traversals = append(traversals, attr.Expr.Variables()...)
+		}
+		for _, block := range syntaxBody.Blocks {
+			traversals = append(traversals, bodyTraversals(block.Body)...)
+		}
+		return traversals
+	}
+
+	// Other body types, such as merged or JSON bodies, don't let us walk
+	// their nested blocks without a schema, so we make do with whatever
+	// attributes they can give us.
+	attrs, _ := body.JustAttributes()
+	for _, attr := range attrs {
+		traversals = append(traversals, attr.Expr.Variables()...)
+	}
+	return traversals
+}
+
+// traversalStepKey returns the attribute name or string index key of the
+// given traversal step, if it has one.
+func traversalStepKey(step hcl.Traverser) (string, bool) {
+	switch step := step.(type) {
+	case hcl.TraverseAttr:
+		return step.Name, true
+	case hcl.TraverseIndex:
+		if step.Key.Type() == cty.String && step.Key.IsKnown() && !step.Key.IsNull() {
+			return step.Key.AsString(), true
+		}
+	}
+	return "", false
+}
+
+// expandMatrix returns one copy of the test file for each combination of
+// values in its matrix block, keyed by each.key for that combination. It
+// returns nil if the file does not have a matrix block.
+func (file *TestFile) expandMatrix() map[string]*TestFile {
+	if file.Matrix == nil {
+		return nil
+	}
+
+	files := make(map[string]*TestFile, len(file.Matrix))
+	for _, each := range file.Matrix {
+		expanded := *file
+		expanded.Matrix = nil
+		expanded.Runs = make([]*TestRun, len(file.Runs))
+		for ix, run := range file.Runs {
+			run := *run
+			run.Each = each
+			expanded.Runs[ix] = &run
+		}
+		files[each.EachKey.AsString()] = &expanded
+	}
+	return files
+}
+
+// decodeTestMatrixBlock evaluates the attributes of a matrix block, each of
+// which must be a list or set of primitive values, and returns the each.key
+// and each.value for every combination of those values. The keys list the
+// value of each attribute in alphabetical order, such as "region=a,size=1".
+func decodeTestMatrixBlock(block *hcl.Block) ([]instances.RepetitionData, hcl.Diagnostics) {
+	attrs, diags := block.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type dimension struct {
+		name   string
+		values []cty.Value
+		keys   []string
+	}
+	var dimensions []dimension
+	for _, name := range names {
+		attr := attrs[name]
+		val, valDiags := evalStaticTestExpression(attr.Expr, "matrix block")
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			continue
+		}
+
+		invalid := &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid matrix value",
+			Detail:   fmt.Sprintf("The %q attribute of a matrix block must be a non-empty list or set of strings, numbers or bools.", name),
+			Subject:  attr.Expr.Range().Ptr(),
+		}
+		ty := val.Type()
+		if val.IsNull() || !(ty.IsListType() || ty.IsSetType() || ty.IsTupleType()) || val.LengthInt() == 0 {
+			diags = append(diags, invalid)
+			continue
+		}
+
+		dim := dimension{name: name}
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			key, err := convert.Convert(elem, cty.String)
+			if err != nil || !elem.Type().IsPrimitiveType() || elem.IsNull() {
+				diags = append(diags, invalid)
+				dim.values = nil
+				break
+			}
+			dim.values = append(dim.values, elem)
+			dim.keys = append(dim.keys, key.AsString())
+		}
+		if dim.values != nil {
+			dimensions = append(dimensions, dim)
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Start with a single empty combination, and then multiply it by each of
+	// the dimensions in turn.
+	type combination struct {
+		keys   []string
+		values map[string]cty.Value
+	}
+	combinations := []combination{{values: map[string]cty.Value{}}}
+	for _, dim := range dimensions {
+		var next []combination
+		for _, c := range combinations {
+			for ix, value := range dim.values {
+				values := make(map[string]cty.Value, len(c.values)+1)
+				maps.Copy(values, c.values)
+				values[dim.name] = value
+				next = append(next, combination{
+					keys:   append(slices.Clip(c.keys), fmt.Sprintf("%s=%s", dim.name, dim.keys[ix])),
+					values: values,
+				})
+			}
+		}
+		combinations = next
+	}
+
+	matrix := make([]instances.RepetitionData, 0, len(combinations))
+	for _, c := range combinations {
+		matrix = append(matrix, instances.RepetitionData{
+			EachKey:   cty.StringVal(strings.Join(c.keys, ",")),
+			EachValue: cty.ObjectVal(c.values),
+		})
+	}
+	return matrix, diags
+}
+
+// staticTestContext returns a function that builds the evaluation context for
+// expressions in test files that are evaluated when the file is loaded. These
+// can only use literal values and functions.
+func staticTestContext(what string) evalchecks.ContextFunc {
+	return func(refs []*addrs.Reference) (*hcl.EvalContext, tfdiags.Diagnostics) {
+		var diags tfdiags.Diagnostics
+		for _, ref := range refs {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid reference",
+				Detail:   fmt.Sprintf("The %s is evaluated when the test file is loaded, so it can only use literal values and functions.", what),
+				Subject:  ref.SourceRange.ToHCL().Ptr(),
+			})
+		}
+		scope := &lang.Scope{PureOnly: true}
+		return &hcl.EvalContext{Functions: scope.Functions()}, diags
+	}
+}
+
+func evalStaticTestExpression(expr hcl.Expression, what string) (cty.Value, hcl.Diagnostics) {
+	refs, diags := lang.ReferencesInExpr(addrs.ParseRef, expr)
+	evalCtx, ctxDiags := staticTestContext(what)(refs)
+	diags = diags.Append(ctxDiags)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags.ToHCL()
+	}
+	val, valDiags := expr.Value(evalCtx)
+	return val, append(diags.ToHCL(), valDiags...)
+}
+
 func decodeTestRunModuleBlock(block *hcl.Block) (*TestRunModuleCall, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
@@ -738,6 +1260,186 @@ func decodeTestRunModuleBlock(block *hcl.Block) (*TestRunModuleCall, hcl.Diagnos
 	return &module, diags
 }
 
+// TestExpectedDiagnostic describes a diagnostic that a run block is expected
+// to produce, from an expect_diagnostics block.
+//
+// A diagnostic matches if it has the given severity, if its summary and
+// detail match the given regular expressions, and if its source range
+// includes the given file and line. Any of the criteria other than the
+// severity can be omitted, in which case they match every diagnostic.
+type TestExpectedDiagnostic struct {
+	Severity tfdiags.Severity
+	Summary  *regexp.Regexp
+	Detail   *regexp.Regexp
+
+	// Filename and Line restrict the diagnostic to those whose subject is in
+	// the given file and includes the given line. Filename matches either
+	// the whole filename of the diagnostic or a suffix of it starting after
+	// a path separator, and Line is zero if any line matches.
+	Filename string
+	Line     int
+
+	DeclRange hcl.Range
+}
+
+// Matches returns true if the given diagnostic is described by the
+// expectation.
+func (e *TestExpectedDiagnostic) Matches(diag tfdiags.Diagnostic) bool {
+	if diag.Severity() != e.Severity {
+		return false
+	}
+
+	desc := diag.Description()
+	if e.Summary != nil && !e.Summary.MatchString(desc.Summary) {
+		return false
+	}
+	if e.Detail != nil && !e.Detail.MatchString(desc.Detail) {
+		return false
+	}
+
+	if e.Filename == "" && e.Line == 0 {
+		return true
+	}
+	subject := diag.Source().Subject
+	if subject == nil {
+		return false
+	}
+	if e.Filename != "" {
+		filename := filepath.ToSlash(subject.Filename)
+		if filename != e.Filename && !strings.HasSuffix(filename, "/"+e.Filename) {
+			return false
+		}
+	}
+	if e.Line != 0 && (e.Line < subject.Start.Line || e.Line > subject.End.Line) {
+		return false
+	}
+	return true
+}
+
+func decodeTestExpectedDiagnosticBlock(block *hcl.Block) (*TestExpectedDiagnostic, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(testExpectedDiagnosticBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	expected := TestExpectedDiagnostic{
+		Severity:  tfdiags.Error,
+		DeclRange: block.DefRange,
+	}
+
+	if attr, exists := content.Attributes["severity"]; exists {
+		switch hcl.ExprAsKeyword(attr.Expr) {
+		case "error":
+			expected.Severity = tfdiags.Error
+		case "warning":
+			expected.Severity = tfdiags.Warning
+		default:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"severity\" keyword",
+				Detail:   "The \"severity\" argument requires one of the following keywords without quotes: error or warning.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	decodeRegexp := func(name string) *regexp.Regexp {
+		attr, exists := content.Attributes[name]
+		if !exists {
+			return nil
+		}
+
+		var pattern string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &pattern)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			return nil
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid regular expression",
+				Detail:   fmt.Sprintf("The %q argument must be a valid regular expression: %s.", name, err),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			return nil
+		}
+		return re
+	}
+	expected.Summary = decodeRegexp("summary")
+	expected.Detail = decodeRegexp("detail")
+
+	if attr, exists := content.Attributes["filename"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &expected.Filename)...)
+		expected.Filename = filepath.ToSlash(expected.Filename)
+	}
+
+	if attr, exists := content.Attributes["line"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &expected.Line)...)
+		if expected.Line < 1 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"line\" value",
+				Detail:   "The \"line\" argument must be a line number, starting at 1.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	return &expected, diags
+}
+
+func decodeTestCleanupBlock(block *hcl.Block) (*TestCleanup, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(testCleanupBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	cleanup := TestCleanup{
+		RetryDelay: DefaultTestCleanupRetryDelay,
+		DeclRange:  block.DefRange,
+	}
+
+	if attr, exists := content.Attributes["retries"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &cleanup.Retries)...)
+		if cleanup.Retries < 0 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"retries\" value",
+				Detail:   "The \"retries\" argument must not be negative.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	if attr, exists := content.Attributes["retry_delay"]; exists {
+		var raw string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &raw)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			delay, err := time.ParseDuration(raw)
+			if err != nil || delay < 0 {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid \"retry_delay\" value",
+					Detail:   "The \"retry_delay\" argument must be a duration, such as \"30s\" or \"2m\".",
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			} else {
+				cleanup.RetryDelay = delay
+			}
+		}
+	}
+
+	if attr, exists := content.Attributes["skip_cleanup"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &cleanup.SkipCleanup)...)
+	}
+
+	return &cleanup, diags
+}
+
 func decodeTestRunOptionsBlock(block *hcl.Block) (*TestRunOptions, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
@@ -822,6 +1524,8 @@ func decodeOverrideResourceBlock(block *hcl.Block) (*OverrideResource, hcl.Diagn
 		res.Mode = addrs.ManagedResourceMode
 	case blockNameOverrideData:
 		res.Mode = addrs.DataResourceMode
+	case blockNameOverrideEphemeral:
+		res.Mode = addrs.EphemeralResourceMode
 	default:
 		panic("BUG: unsupported block type for override resource: " + block.Type)
 	}
@@ -921,6 +1625,12 @@ func decodeMockProviderBlock(block *hcl.Block) (*MockProvider, hcl.Diagnostics)
 		provider.ForEach = attr.Expr
 	}
 
+	if attr, exists := content.Attributes["generators"]; exists {
+		generators, genDiags := decodeMockGenerators(attr)
+		diags = append(diags, genDiags...)
+		provider.Generators = generators
+	}
+
 	if len(provider.Alias) == 0 && provider.ForEach != nil {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -932,13 +1642,13 @@ func decodeMockProviderBlock(block *hcl.Block) (*MockProvider, hcl.Diagnostics)
 
 	for _, block := range content.Blocks {
 		switch block.Type {
-		case blockNameMockData, blockNameMockResource:
+		case blockNameMockData, blockNameMockResource, blockNameMockEphemeral:
 			res, resDiags := decodeMockResourceBlock(block)
 			diags = append(diags, resDiags...)
 			if !resDiags.HasErrors() {
 				provider.MockResources = append(provider.MockResources, res)
 			}
-		case blockNameOverrideData, blockNameOverrideResource:
+		case blockNameOverrideData, blockNameOverrideResource, blockNameOverrideEphemeral:
 			res, resDiags := decodeOverrideResourceBlock(block)
 			diags = append(diags, resDiags...)
 			if !resDiags.HasErrors() {
@@ -961,6 +1671,8 @@ func decodeMockResourceBlock(block *hcl.Block) (*MockResource, hcl.Diagnostics)
 		mode = addrs.ManagedResourceMode
 	case blockNameMockData:
 		mode = addrs.DataResourceMode
+	case blockNameMockEphemeral:
+		mode = addrs.EphemeralResourceMode
 	default:
 		panic("BUG: unsupported block type for mock resource: " + block.Type)
 	}
@@ -977,6 +1689,23 @@ func decodeMockResourceBlock(block *hcl.Block) (*MockResource, hcl.Diagnostics)
 		res.Defaults, diags = v, append(diags, moreDiags...)
 	}
 
+	if attr, exists := content.Attributes["generators"]; exists {
+		generators, genDiags := decodeMockGenerators(attr)
+		diags = append(diags, genDiags...)
+		res.Generators = generators
+
+		for name, expr := range generators {
+			if _, exists := res.Defaults[name]; exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Conflicting mock values",
+					Detail:   fmt.Sprintf("The attribute %q has both a default value and a generator. Only one of them can be set.", name),
+					Subject:  expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+
 	return res, diags
 }
 
@@ -1062,12 +1791,24 @@ var testFileSchema = &hcl.BodySchema{
 			// variables block defines input variables to pass to the test.
 			Type: "variables",
 		},
+		{
+			// matrix block expands the file into one test file for each
+			// combination of its values.
+			Type: "matrix",
+		},
+		{
+			// cleanup block configures how the infrastructure is destroyed.
+			Type: "cleanup",
+		},
 		{
 			Type: blockNameOverrideResource,
 		},
 		{
 			Type: blockNameOverrideData,
 		},
+		{
+			Type: blockNameOverrideEphemeral,
+		},
 		{
 			Type: blockNameOverrideModule,
 		},
@@ -1088,6 +1829,12 @@ var testRunBlockSchema = &hcl.BodySchema{
 		{Name: "providers"},
 		// expect_failures indicates whether test failures are expected.
 		{Name: "expect_failures"},
+		// expect_plan_snapshot is the path of a golden file the plan should match.
+		{Name: "expect_plan_snapshot"},
+		// parallel allows the run block to execute alongside adjacent parallel run blocks.
+		{Name: "parallel"},
+		// for_each expands the run block into one run block for each element.
+		{Name: "for_each"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -1112,9 +1859,16 @@ var testRunBlockSchema = &hcl.BodySchema{
 		{
 			Type: blockNameOverrideData,
 		},
+		{
+			Type: blockNameOverrideEphemeral,
+		},
 		{
 			Type: blockNameOverrideModule,
 		},
+		{
+			// expect_diagnostics describes a diagnostic the run block should produce.
+			Type: "expect_diagnostics",
+		},
 	},
 }
 
@@ -1170,6 +1924,24 @@ var overrideModuleBlockSchema = &hcl.BodySchema{
 	},
 }
 
+var testCleanupBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "retries"},
+		{Name: "retry_delay"},
+		{Name: "skip_cleanup"},
+	},
+}
+
+var testExpectedDiagnosticBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "severity"},
+		{Name: "summary"},
+		{Name: "detail"},
+		{Name: "filename"},
+		{Name: "line"},
+	},
+}
+
 var mockProviderBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{
@@ -1180,6 +1952,10 @@ var mockProviderBlockSchema = &hcl.BodySchema{
 			Name:     "for_each",
 			Required: false,
 		},
+		{
+			Name:     "generators",
+			Required: false,
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -1190,12 +1966,19 @@ var mockProviderBlockSchema = &hcl.BodySchema{
 			Type:       blockNameMockData,
 			LabelNames: []string{"type"},
 		},
+		{
+			Type:       blockNameMockEphemeral,
+			LabelNames: []string{"type"},
+		},
 		{
 			Type: blockNameOverrideResource,
 		},
 		{
 			Type: blockNameOverrideData,
 		},
+		{
+			Type: blockNameOverrideEphemeral,
+		},
 	},
 }
 
@@ -1204,5 +1987,8 @@ var mockResourceBlockSchema = &hcl.BodySchema{
 		{
 			Name: "defaults",
 		},
+		{
+			Name: "generators",
+		},
 	},
 }
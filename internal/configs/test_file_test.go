@@ -8,11 +8,15 @@ package configs
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hcltest"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
 )
 
 func TestTestRun_Validate(t *testing.T) {
@@ -178,3 +182,578 @@ func TestDecodeTestRunModuleBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeTestRunBlock_parallel(t *testing.T) {
+	tcs := map[string]struct {
+		src          string
+		want         bool
+		expectedDiag string
+	}{
+		"default": {
+			src:  `run "test" {}`,
+			want: false,
+		},
+		"enabled": {
+			src:  `run "test" { parallel = true }`,
+			want: true,
+		},
+		"disabled": {
+			src:  `run "test" { parallel = false }`,
+			want: false,
+		},
+		"invalid": {
+			src:          `run "test" { parallel = "sometimes" }`,
+			expectedDiag: "Unsuitable value type",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			tf, diags := loadTestFile(file.Body)
+			if tc.expectedDiag != "" {
+				assertDiagsSummaryMatch(t, hcl.Diagnostics{{Summary: tc.expectedDiag}}, diags)
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			if got := tf.Runs[0].Parallel; got != tc.want {
+				t.Errorf("wrong result %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_ephemeral(t *testing.T) {
+	src := `
+override_ephemeral {
+  target = ephemeral.test_ephemeral.file
+  values = {
+    value = "file"
+  }
+}
+
+mock_provider "test" {
+  mock_ephemeral "test_ephemeral" {
+    defaults = {
+      value = "mocked"
+    }
+  }
+
+  override_ephemeral {
+    target = ephemeral.test_ephemeral.provider
+    values = {
+      value = "provider"
+    }
+  }
+}
+
+run "test" {
+  override_ephemeral {
+    target = module.child.ephemeral.test_ephemeral.run
+    values = {
+      value = "run"
+    }
+  }
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	tf, diags := loadTestFile(file.Body)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	type override struct {
+		Target string
+		Mode   addrs.ResourceMode
+		Value  string
+	}
+	toOverrides := func(resources []*OverrideResource) []override {
+		var ret []override
+		for _, res := range resources {
+			ret = append(ret, override{
+				Target: res.TargetParsed.String(),
+				Mode:   res.Mode,
+				Value:  res.Values["value"].AsString(),
+			})
+		}
+		return ret
+	}
+
+	if diff := cmp.Diff([]override{{"ephemeral.test_ephemeral.file", addrs.EphemeralResourceMode, "file"}}, toOverrides(tf.OverrideResources)); diff != "" {
+		t.Errorf("wrong file overrides\n%s", diff)
+	}
+	if diff := cmp.Diff([]override{{"module.child.ephemeral.test_ephemeral.run", addrs.EphemeralResourceMode, "run"}}, toOverrides(tf.Runs[0].OverrideResources)); diff != "" {
+		t.Errorf("wrong run overrides\n%s", diff)
+	}
+
+	provider := tf.MockProviders["test"]
+	if diff := cmp.Diff([]override{{"ephemeral.test_ephemeral.provider", addrs.EphemeralResourceMode, "provider"}}, toOverrides(provider.OverrideResources)); diff != "" {
+		t.Errorf("wrong mock provider overrides\n%s", diff)
+	}
+	if got, want := len(provider.MockResources), 1; got != want {
+		t.Fatalf("wrong number of mock resources %d; want %d", got, want)
+	}
+	if mock := provider.MockResources[0]; mock.Mode != addrs.EphemeralResourceMode || mock.Type != "test_ephemeral" || mock.Defaults["value"].AsString() != "mocked" {
+		t.Errorf("wrong mock resource %#v", mock)
+	}
+}
+
+func TestLoadTestFile_duplicateMockEphemeral(t *testing.T) {
+	src := `
+mock_provider "test" {
+  mock_ephemeral "test_ephemeral" {}
+  mock_ephemeral "test_ephemeral" {}
+
+  # A data source of the same type does not conflict.
+  mock_data "test_ephemeral" {}
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	_, diags = loadTestFile(file.Body)
+	if len(diags) != 1 {
+		t.Fatalf("expected exactly one diagnostic, got %d: %s", len(diags), diags.Error())
+	}
+	if got, want := diags[0].Summary, "Duplicated `mock_ephemeral` block"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+}
+
+func TestDecodeTestRunBlock_expectPlanSnapshot(t *testing.T) {
+	tcs := map[string]struct {
+		src          string
+		want         string
+		expectedDiag string
+	}{
+		"default": {
+			src:  `run "test" {}`,
+			want: "",
+		},
+		"path": {
+			src:  `run "test" { expect_plan_snapshot = "snapshots/basic.json" }`,
+			want: "snapshots/basic.json",
+		},
+		"empty": {
+			src:          `run "test" { expect_plan_snapshot = "" }`,
+			expectedDiag: `Invalid "expect_plan_snapshot" path`,
+		},
+		"variable": {
+			src:          `run "test" { expect_plan_snapshot = var.path }`,
+			expectedDiag: "Variables not allowed",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			tf, diags := loadTestFile(file.Body)
+			if tc.expectedDiag != "" {
+				if len(diags) == 0 {
+					t.Fatalf("expected diagnostic %q, got none", tc.expectedDiag)
+				}
+				assertDiagsSummaryMatch(t, hcl.Diagnostics{{Summary: tc.expectedDiag}}, diags)
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			if got := tf.Runs[0].ExpectPlanSnapshot; got != tc.want {
+				t.Errorf("wrong result %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_runForEach(t *testing.T) {
+	src := `
+run "setup" {}
+
+run "cases" {
+  for_each = toset(["b", "a"])
+
+  variables {
+    input = each.value
+  }
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	tf, diags := loadTestFile(file.Body)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	var got []string
+	for _, run := range tf.Runs {
+		each := "-"
+		if run.Each.EachKey != cty.NilVal {
+			each = fmt.Sprintf("%s=%s", run.Each.EachKey.AsString(), run.Each.EachValue.AsString())
+		}
+		got = append(got, fmt.Sprintf("%s/%s %s", run.BaseName, run.Name, each))
+	}
+	want := []string{"setup/setup -", "cases/cases[a] a=a", "cases/cases[b] b=b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong runs\n%s", diff)
+	}
+}
+
+func TestLoadTestFile_runForEachReferences(t *testing.T) {
+	src := `
+run "cases" {
+  for_each = toset(["a", "b"])
+}
+
+run "check" {
+  variables {
+    all   = run.cases
+    index = run.cases["a"].value
+    attr  = run.cases.b.value
+  }
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	if _, diags := loadTestFile(file.Body); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+}
+
+func TestLoadTestFile_matrix(t *testing.T) {
+	src := `
+matrix {
+  size   = [1, 2]
+  region = ["us", "eu"]
+}
+
+run "test" {}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	tf, diags := loadTestFile(file.Body)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	files := tf.expandMatrix()
+	got := make(map[string]string)
+	for key, file := range files {
+		if len(file.Runs) != 1 || file.Runs[0].Each.EachKey.AsString() != key {
+			t.Errorf("wrong runs for %s", key)
+		}
+		value := file.Runs[0].Each.EachValue
+		got[key] = fmt.Sprintf("%s/%s", value.GetAttr("region").AsString(), value.GetAttr("size").AsBigFloat().String())
+	}
+	want := map[string]string{
+		"region=us,size=1": "us/1",
+		"region=us,size=2": "us/2",
+		"region=eu,size=1": "eu/1",
+		"region=eu,size=2": "eu/2",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong matrix\n%s", diff)
+	}
+	if tf.Runs[0].Each.EachKey != cty.NilVal {
+		t.Errorf("expanding the matrix modified the original run block")
+	}
+}
+
+func TestLoadTestFile_forEachErrors(t *testing.T) {
+	tcs := map[string]struct {
+		src  string
+		want string
+	}{
+		"reference": {
+			src: `
+run "test" {
+  for_each = var.cases
+}
+`,
+			want: "Invalid reference",
+		},
+		"invalid for_each": {
+			src: `
+run "test" {
+  for_each = "nope"
+}
+`,
+			want: "Invalid for_each argument",
+		},
+		"invalid matrix": {
+			src: `
+matrix {
+  size = []
+}
+`,
+			want: "Invalid matrix value",
+		},
+		"expanded run name reference": {
+			src: `
+run "cases" {
+  for_each = toset(["a"])
+}
+
+run "check" {
+  variables {
+    input = run["cases[a]"].value
+  }
+}
+`,
+			want: "Invalid run block reference",
+		},
+		"unknown expanded run key": {
+			src: `
+run "cases" {
+  for_each = toset(["a"])
+}
+
+run "check" {
+  assert {
+    condition     = run.cases["b"].value == "b"
+    error_message = "wrong value"
+  }
+}
+`,
+			want: "Invalid run block key",
+		},
+		"matrix and for_each": {
+			src: `
+matrix {
+  size = [1]
+}
+
+run "test" {
+  for_each = toset(["a"])
+}
+`,
+			want: "Invalid for_each argument",
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			_, diags = loadTestFile(file.Body)
+			if !diags.HasErrors() {
+				t.Fatal("expected errors, got none")
+			}
+			if got := diags[0].Summary; got != tc.want {
+				t.Errorf("wrong summary %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_expectDiagnostics(t *testing.T) {
+	src := `
+run "test" {
+  expect_diagnostics {
+    summary = "^Invalid value"
+  }
+
+  expect_diagnostics {
+    severity = warning
+    detail   = "deprecated"
+    filename = "modules/child/main.tf"
+    line     = 12
+  }
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	tf, diags := loadTestFile(file.Body)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	type expected struct {
+		Severity string
+		Summary  string
+		Detail   string
+		Filename string
+		Line     int
+	}
+	var got []expected
+	for _, e := range tf.Runs[0].ExpectDiagnostics {
+		var summary, detail string
+		if e.Summary != nil {
+			summary = e.Summary.String()
+		}
+		if e.Detail != nil {
+			detail = e.Detail.String()
+		}
+		got = append(got, expected{e.Severity.String(), summary, detail, e.Filename, e.Line})
+	}
+	want := []expected{
+		{"Error", "^Invalid value", "", "", 0},
+		{"Warning", "", "deprecated", "modules/child/main.tf", 12},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong expected diagnostics\n%s", diff)
+	}
+}
+
+func TestLoadTestFile_expectDiagnosticsErrors(t *testing.T) {
+	tcs := map[string]struct {
+		src  string
+		want string
+	}{
+		"severity": {
+			src: `
+run "test" {
+  expect_diagnostics {
+    severity = "error"
+  }
+}
+`,
+			want: "Invalid \"severity\" keyword",
+		},
+		"regexp": {
+			src: `
+run "test" {
+  expect_diagnostics {
+    summary = "(unclosed"
+  }
+}
+`,
+			want: "Invalid regular expression",
+		},
+		"line": {
+			src: `
+run "test" {
+  expect_diagnostics {
+    line = 0
+  }
+}
+`,
+			want: "Invalid \"line\" value",
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			_, diags = loadTestFile(file.Body)
+			if !diags.HasErrors() {
+				t.Fatal("expected errors, got none")
+			}
+			if got := diags[0].Summary; got != tc.want {
+				t.Errorf("wrong summary %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_cleanup(t *testing.T) {
+	src := `
+cleanup {
+  retries      = 3
+  retry_delay  = "10s"
+  skip_cleanup = true
+}
+
+run "test" {}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	tf, diags := loadTestFile(file.Body)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	if got, want := tf.Cleanup.Retries, 3; got != want {
+		t.Errorf("wrong retries %d; want %d", got, want)
+	}
+	if got, want := tf.Cleanup.RetryDelay, 10*time.Second; got != want {
+		t.Errorf("wrong retry delay %s; want %s", got, want)
+	}
+	if !tf.Cleanup.SkipCleanup {
+		t.Errorf("expected skip_cleanup to be set")
+	}
+}
+
+func TestLoadTestFile_cleanupErrors(t *testing.T) {
+	tcs := map[string]struct {
+		src  string
+		want string
+	}{
+		"retries": {
+			src: `
+cleanup {
+  retries = -1
+}
+`,
+			want: "Invalid \"retries\" value",
+		},
+		"retry_delay": {
+			src: `
+cleanup {
+  retry_delay = "soon"
+}
+`,
+			want: "Invalid \"retry_delay\" value",
+		},
+		"multiple": {
+			src: `
+cleanup {}
+cleanup {}
+`,
+			want: "Multiple \"cleanup\" blocks",
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			_, diags = loadTestFile(file.Body)
+			if !diags.HasErrors() {
+				t.Fatal("expected errors, got none")
+			}
+			if got := diags[0].Summary; got != tc.want {
+				t.Errorf("wrong summary %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
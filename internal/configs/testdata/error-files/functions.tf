@@ -0,0 +1,30 @@
+variable "prefix" {
+  type = string
+}
+
+function "impure" {
+  parameter "name" {
+    type = string
+  }
+
+  result = "${var.prefix}-${param.name}" # ERROR: Invalid reference in function
+}
+
+function "undeclared" {
+  parameter "name" {}
+
+  result = param.other # ERROR: Reference to undeclared parameter
+}
+
+function "provider_call" {
+  parameter "name" {}
+
+  result = provider::test::echo(param.name) # ERROR: Invalid function call in function
+}
+
+function "variadic_first" {
+  variadic_parameter "rest" {}
+  parameter "name" {} # ERROR: Parameter after variadic parameter
+
+  result = param.rest
+}
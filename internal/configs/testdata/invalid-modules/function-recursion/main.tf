@@ -0,0 +1,15 @@
+function "even" {
+  parameter "n" {
+    type = number
+  }
+
+  result = param.n == 0 ? true : module_fn::odd(param.n - 1)
+}
+
+function "odd" {
+  parameter "n" {
+    type = number
+  }
+
+  result = param.n == 0 ? false : module_fn::even(param.n - 1)
+}
@@ -0,0 +1,26 @@
+function "slug" {
+  description = "Normalizes a name for use in resource identifiers."
+
+  parameter "name" {
+    type        = string
+    description = "The name to normalize."
+  }
+
+  variadic_parameter "suffixes" {
+    type = string
+  }
+
+  result = join("-", concat([lower(replace(param.name, " ", "-"))], param.suffixes))
+}
+
+function "bucket_name" {
+  parameter "env" {
+    type = string
+  }
+
+  result = module_fn::slug("bucket", param.env)
+}
+
+locals {
+  bucket = module_fn::bucket_name("prod")
+}
@@ -24,3 +24,13 @@ output "π" {
     pizza.cheese,
   ]
 }
+
+output "endpoint" {
+  value = {
+    host = "localhost"
+  }
+  type = object({
+    host = string
+    port = optional(number, 443)
+  })
+}
@@ -0,0 +1,4 @@
+output "typed" {
+  value = ["a"]
+  type  = list(string)
+}
@@ -0,0 +1,3 @@
+output "typed" {
+  type = set(string)
+}
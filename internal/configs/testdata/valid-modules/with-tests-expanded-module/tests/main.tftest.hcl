@@ -0,0 +1,14 @@
+run "setup" {
+  for_each = {
+    "../x" = "one"
+    "y\\z" = "two"
+  }
+
+  module {
+    source = "./setup"
+  }
+
+  variables {
+    value = each.value
+  }
+}
@@ -0,0 +1,13 @@
+matrix {
+  dir = ["a/b", "..\\c"]
+}
+
+run "setup" {
+  module {
+    source = "./setup"
+  }
+
+  variables {
+    value = each.value
+  }
+}
@@ -213,6 +213,11 @@ func (m *managedResourceInstanceMockProvider) ImportResourceState(context.Contex
 	panic("unimplemented")
 }
 
+// ListResource implements providers.Configured.
+func (m *managedResourceInstanceMockProvider) ListResource(context.Context, providers.ListResourceRequest) providers.ListResourceResponse {
+	panic("unimplemented")
+}
+
 // MoveResourceState implements providers.Configured.
 func (m *managedResourceInstanceMockProvider) MoveResourceState(context.Context, providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
 	panic("unimplemented")
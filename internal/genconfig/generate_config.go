@@ -68,6 +68,31 @@ func WrapResourceContents(addr addrs.AbsResourceInstance, config string) string
 	return string(formatted)
 }
 
+// GenerateImportBlock generates an import block that imports the remote
+// object with the given resource identity into the given resource instance.
+//
+// Null attributes of the identity are omitted, because they are optional
+// parts of the identity that the provider didn't need to identify the object.
+func GenerateImportBlock(addr addrs.AbsResourceInstance, identity cty.Value) string {
+	attrs := make(map[string]cty.Value)
+	for name, val := range identity.AsValueMap() {
+		if !val.IsNull() {
+			attrs[name] = val
+		}
+	}
+
+	var buf strings.Builder
+	buf.WriteString("import {\n")
+	buf.WriteString(fmt.Sprintf("to = %s\n", addr))
+	buf.WriteString("identity = ")
+	buf.Write(hclwrite.TokensForValue(cty.ObjectVal(attrs)).Bytes())
+	buf.WriteString("\n}")
+
+	// The output better be valid HCL which can be parsed and formatted.
+	formatted := hclwrite.Format([]byte(buf.String()))
+	return string(formatted)
+}
+
 func writeConfigAttributes(addr addrs.AbsResourceInstance, buf *strings.Builder, attrs map[string]*configschema.Attribute, indent int) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
@@ -843,3 +843,34 @@ resource "tfcoremock_simple_resource" "example" {
 		})
 	}
 }
+
+func TestGenerateImportBlock(t *testing.T) {
+	addr := addrs.AbsResourceInstance{
+		Module: addrs.RootModuleInstance,
+		Resource: addrs.ResourceInstance{
+			Resource: addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "tfcoremock_simple_resource",
+				Name: "example",
+			},
+			Key: addrs.NoKey,
+		},
+	}
+	identity := cty.ObjectVal(map[string]cty.Value{
+		"id":     cty.StringVal("i-123"),
+		"region": cty.StringVal("eu-west-1"),
+		"owner":  cty.NullVal(cty.String),
+	})
+
+	got := GenerateImportBlock(addr, identity)
+	want := `import {
+  to = tfcoremock_simple_resource.example
+  identity = {
+    id     = "i-123"
+    region = "eu-west-1"
+  }
+}`
+	if diff := cmp.Diff(want, got); len(diff) > 0 {
+		t.Errorf("got:\n%s\nwant:\n%s\ndiff:\n%s", got, want, diff)
+	}
+}
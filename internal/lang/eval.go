@@ -259,6 +259,9 @@ func enhanceFunctionDiag(diag *hcl.Diagnostic, funcExtra hclsyntax.FunctionCallU
 			enhanced.Summary = "Invalid function format"
 			enhanced.Detail = err.Error()
 		}
+	} else if fn.IsNamespace(addrs.FunctionNamespaceModule) {
+		enhanced.Summary = "Call to unknown function"
+		enhanced.Detail = fmt.Sprintf("There is no function named %q declared in this module.", funcName)
 	} else {
 		enhanced.Summary = "Unknown function namespace"
 		enhanced.Detail = fmt.Sprintf("Function %q does not exist within a valid namespace (%s)", fn, strings.Join(addrs.FunctionNamespaces, ","))
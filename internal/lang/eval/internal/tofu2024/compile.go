@@ -83,6 +83,7 @@ func CompileModuleInstance(
 		inst:          ret,
 		coreFunctions: compileCoreFunctions(ctx, call.AllowImpureFunctions, call.EvalContext.RootModuleDir, call.EvalContext.PlanTimestamp),
 	}
+	topScope.moduleFunctions = module.FunctionImpls(topScope.coreFunctions)
 
 	// We have some shims in here to deal with the unusual way the existing
 	// OpenTofu language deals with references to provider instances, since
@@ -42,6 +42,7 @@ import (
 type moduleInstanceScope struct {
 	inst              *CompiledModuleInstance
 	coreFunctions     map[string]function.Function
+	moduleFunctions   map[string]function.Function
 	providerFunctions func(context.Context, addrs.ProviderFunction, hcl.Range) (function.Function, tfdiags.Diagnostics)
 }
 
@@ -92,6 +93,18 @@ func (m *moduleInstanceScope) ResolveFunc(ctx context.Context, call *hcl.StaticC
 		fn, moreDiags := m.providerFunctions(ctx, pf, call.NameRange)
 		diags = diags.Append(moreDiags)
 		return fn, diags
+	case addrs.FunctionNamespaceModule:
+		fn, ok := m.moduleFunctions[call.Name]
+		if !ok {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Call to unknown function",
+				Detail:   fmt.Sprintf("There is no function named %q declared in this module.", parsed.Name),
+				Subject:  &call.NameRange,
+			})
+			return function.Function{}, diags
+		}
+		return fn, diags
 	default:
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -988,7 +988,13 @@ func Test_enhanceFunctionDiags(t *testing.T) {
 			"Invalid prefix",
 			"attr = magic::missing_function(54)",
 			"Unknown function namespace",
-			"Function \"magic::missing_function\" does not exist within a valid namespace (provider,core)",
+			"Function \"magic::missing_function\" does not exist within a valid namespace (provider,core,module_fn)",
+		},
+		{
+			"Missing module function",
+			"attr = module_fn::missing_function(54)",
+			"Call to unknown function",
+			"There is no function named \"missing_function\" declared in this module.",
 		},
 		{
 			"Too many namespaces",
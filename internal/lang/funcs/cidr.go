@@ -8,6 +8,7 @@ package funcs
 import (
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/apparentlymart/go-cidr/cidr"
 	"github.com/opentofu/opentofu/internal/ipaddr"
@@ -263,6 +264,391 @@ var CidrContainsFunc = function.New(&function.Spec{
 	},
 })
 
+// CidrMergeFunc constructs a function that aggregates a list of IP network
+// address prefixes into the smallest list of prefixes covering the same
+// addresses, combining adjacent and overlapping prefixes.
+var CidrMergeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefixes",
+			Type: cty.List(cty.String),
+		},
+	},
+	Type:         function.StaticReturnType(cty.List(cty.String)),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		if !args[0].IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+		networks, err := parseCidrList(args[0], 0)
+		if err != nil {
+			return cty.UnknownVal(retType), err
+		}
+
+		// Each address family is merged separately, with IPv4 first.
+		var v4, v6 []cidrRange
+		for _, network := range networks {
+			r := newCidrRange(network)
+			if r.bits == 32 {
+				v4 = append(v4, r)
+			} else {
+				v6 = append(v6, r)
+			}
+		}
+		var result []*ipaddr.IPNet
+		for _, ranges := range [][]cidrRange{v4, v6} {
+			for _, r := range mergeCidrRanges(ranges) {
+				result = append(result, r.prefixes()...)
+			}
+		}
+		return cidrListVal(result), nil
+	},
+})
+
+// CidrExcludeFunc constructs a function that removes a list of IP network
+// address prefixes from a base prefix, returning the smallest list of
+// prefixes covering the remaining addresses.
+var CidrExcludeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix",
+			Type: cty.String,
+		},
+		{
+			Name: "excluded_prefixes",
+			Type: cty.List(cty.String),
+		},
+	},
+	Type:         function.StaticReturnType(cty.List(cty.String)),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, network, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		if !args[1].IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+		excluded, err := parseCidrList(args[1], 1)
+		if err != nil {
+			return cty.UnknownVal(retType), err
+		}
+		base := newCidrRange(network)
+		used, err := cidrRangesWithin(base, excluded, 1)
+		if err != nil {
+			return cty.UnknownVal(retType), err
+		}
+
+		var result []*ipaddr.IPNet
+		for _, r := range base.subtract(used) {
+			result = append(result, r.prefixes()...)
+		}
+		return cidrListVal(result), nil
+	},
+})
+
+// CidrOverlapsFunc constructs a function that checks whether two IP network
+// address prefixes have any addresses in common.
+var CidrOverlapsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix_a",
+			Type: cty.String,
+		},
+		{
+			Name: "prefix_b",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, a, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		_, b, err := ipaddr.ParseCIDR(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "invalid CIDR expression: %s", err)
+		}
+		if len(a.IP) != len(b.IP) {
+			return cty.UnknownVal(retType), fmt.Errorf("address family mismatch: %s vs. %s", args[0].AsString(), args[1].AsString())
+		}
+
+		// Two prefixes overlap exactly when one of them contains the other.
+		return cty.BoolVal(a.Contains(b.IP) || b.Contains(a.IP)), nil
+	},
+})
+
+// CidrHostsFunc constructs a function that lists the IP addresses within a
+// given IP network address prefix, up to a given limit.
+var CidrHostsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix",
+			Type: cty.String,
+		},
+		{
+			Name: "limit",
+			Type: cty.Number,
+		},
+	},
+	Type:         function.StaticReturnType(cty.List(cty.String)),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, network, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		var limit int
+		if err := gocty.FromCtyValue(args[1], &limit); err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(1, err)
+		}
+		if limit < 0 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "must not be negative")
+		}
+
+		r := newCidrRange(network)
+		var result []cty.Value
+		for addr := new(big.Int).Set(r.first); len(result) < limit && addr.Cmp(r.last) <= 0; addr.Add(addr, big.NewInt(1)) {
+			result = append(result, cty.StringVal(cidrIP(addr, r.bits).String()))
+		}
+		if len(result) == 0 {
+			return cty.ListValEmpty(cty.String), nil
+		}
+		return cty.ListVal(result), nil
+	},
+})
+
+// CidrNextFreeFunc constructs a function that finds the first subnet of a
+// given size within a base IP network address prefix that doesn't overlap
+// any of a list of prefixes that are already in use.
+var CidrNextFreeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix",
+			Type: cty.String,
+		},
+		{
+			Name: "used_prefixes",
+			Type: cty.List(cty.String),
+		},
+		{
+			Name: "newbits",
+			Type: cty.Number,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, network, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		var newbits int
+		if err := gocty.FromCtyValue(args[2], &newbits); err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(2, err)
+		}
+		base := newCidrRange(network)
+		baseLen, _ := network.Mask.Size()
+		length := baseLen + newbits
+		if newbits < 0 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(2, "must not be negative")
+		}
+		if length > base.bits {
+			return cty.UnknownVal(retType), function.NewArgErrorf(2, "would extend prefix to %d bits, which is too long for an %s address", length, ipFamilyName(base.bits))
+		}
+		if !args[1].IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+		usedNetworks, err := parseCidrList(args[1], 1)
+		if err != nil {
+			return cty.UnknownVal(retType), err
+		}
+		used, err := cidrRangesWithin(base, usedNetworks, 1)
+		if err != nil {
+			return cty.UnknownVal(retType), err
+		}
+
+		size := new(big.Int).Lsh(big.NewInt(1), uint(base.bits-length))
+		candidate := new(big.Int).Set(base.first)
+		for _, r := range used {
+			candidateLast := new(big.Int).Add(candidate, size)
+			candidateLast.Sub(candidateLast, big.NewInt(1))
+			if candidateLast.Cmp(r.first) < 0 {
+				break
+			}
+			if r.last.Cmp(candidate) >= 0 {
+				// Move to the first correctly-aligned subnet after the
+				// used range.
+				candidate.Add(r.last, size)
+				candidate.Div(candidate, size)
+				candidate.Mul(candidate, size)
+			}
+		}
+		candidateLast := new(big.Int).Add(candidate, size)
+		candidateLast.Sub(candidateLast, big.NewInt(1))
+		if candidateLast.Cmp(base.last) > 0 {
+			return cty.UnknownVal(retType), fmt.Errorf("not enough free address space in %s for a subnet with a prefix of %d bits", network.String(), length)
+		}
+		return cty.StringVal(cidrRange{first: candidate, last: candidateLast, bits: base.bits}.prefixes()[0].String()), nil
+	},
+})
+
+// cidrRange is an inclusive range of IP addresses, represented as integers,
+// for an address family with the given number of bits.
+type cidrRange struct {
+	first, last *big.Int
+	bits        int
+}
+
+func newCidrRange(network *ipaddr.IPNet) cidrRange {
+	ones, bits := network.Mask.Size()
+	first := new(big.Int).SetBytes(network.IP)
+	last := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	last.Add(last, first)
+	last.Sub(last, big.NewInt(1))
+	return cidrRange{first: first, last: last, bits: bits}
+}
+
+// prefixes returns the smallest list of prefixes that covers exactly the
+// addresses in the range.
+func (r cidrRange) prefixes() []*ipaddr.IPNet {
+	var ret []*ipaddr.IPNet
+	one := big.NewInt(1)
+	first := new(big.Int).Set(r.first)
+	for first.Cmp(r.last) <= 0 {
+		// The largest possible prefix starting at first is limited by the
+		// alignment of first and then by the end of the range.
+		hostBits := int(first.TrailingZeroBits())
+		if first.Sign() == 0 {
+			hostBits = r.bits
+		}
+		for {
+			last := new(big.Int).Lsh(one, uint(hostBits))
+			last.Add(last, first)
+			last.Sub(last, one)
+			if last.Cmp(r.last) <= 0 {
+				break
+			}
+			hostBits--
+		}
+		ret = append(ret, &ipaddr.IPNet{
+			IP:   cidrIP(first, r.bits),
+			Mask: ipaddr.CIDRMask(r.bits-hostBits, r.bits),
+		})
+		first.Add(first, new(big.Int).Lsh(one, uint(hostBits)))
+	}
+	return ret
+}
+
+// subtract returns the parts of the range that are not in any of the given
+// ranges, which must be sorted and non-overlapping.
+func (r cidrRange) subtract(others []cidrRange) []cidrRange {
+	var ret []cidrRange
+	next := new(big.Int).Set(r.first)
+	for _, o := range others {
+		if o.first.Cmp(next) > 0 {
+			ret = append(ret, cidrRange{first: next, last: new(big.Int).Sub(o.first, big.NewInt(1)), bits: r.bits})
+		}
+		if o.last.Cmp(next) >= 0 {
+			next = new(big.Int).Add(o.last, big.NewInt(1))
+		}
+	}
+	if next.Cmp(r.last) <= 0 {
+		ret = append(ret, cidrRange{first: next, last: r.last, bits: r.bits})
+	}
+	return ret
+}
+
+// mergeCidrRanges sorts the given ranges and combines any that overlap or
+// are adjacent.
+func mergeCidrRanges(ranges []cidrRange) []cidrRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].first.Cmp(ranges[j].first) < 0
+	})
+	var ret []cidrRange
+	for _, r := range ranges {
+		if len(ret) > 0 {
+			prev := &ret[len(ret)-1]
+			if new(big.Int).Add(prev.last, big.NewInt(1)).Cmp(r.first) >= 0 {
+				if r.last.Cmp(prev.last) > 0 {
+					prev.last = r.last
+				}
+				continue
+			}
+		}
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+// cidrRangesWithin returns the merged parts of the given prefixes that are
+// within the base range, returning an error if any of them is of a
+// different address family.
+func cidrRangesWithin(base cidrRange, networks []*ipaddr.IPNet, argIdx int) ([]cidrRange, error) {
+	var ret []cidrRange
+	for _, network := range networks {
+		r := newCidrRange(network)
+		if r.bits != base.bits {
+			return nil, function.NewArgErrorf(argIdx, "address family mismatch: %s is not an %s prefix", network.String(), ipFamilyName(base.bits))
+		}
+		if r.last.Cmp(base.first) < 0 || r.first.Cmp(base.last) > 0 {
+			continue
+		}
+		if r.first.Cmp(base.first) < 0 {
+			r.first = base.first
+		}
+		if r.last.Cmp(base.last) > 0 {
+			r.last = base.last
+		}
+		ret = append(ret, r)
+	}
+	return mergeCidrRanges(ret), nil
+}
+
+func parseCidrList(list cty.Value, argIdx int) ([]*ipaddr.IPNet, error) {
+	var ret []*ipaddr.IPNet
+	for it := list.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.IsNull() {
+			return nil, function.NewArgErrorf(argIdx, "prefixes must not be null")
+		}
+		_, network, err := ipaddr.ParseCIDR(v.AsString())
+		if err != nil {
+			return nil, function.NewArgErrorf(argIdx, "invalid CIDR expression: %s", err)
+		}
+		ret = append(ret, network)
+	}
+	return ret, nil
+}
+
+func cidrListVal(networks []*ipaddr.IPNet) cty.Value {
+	if len(networks) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	vals := make([]cty.Value, len(networks))
+	for i, network := range networks {
+		vals[i] = cty.StringVal(network.String())
+	}
+	return cty.ListVal(vals)
+}
+
+func cidrIP(addr *big.Int, bits int) ipaddr.IP {
+	return ipaddr.IP(addr.FillBytes(make([]byte, bits/8)))
+}
+
+func ipFamilyName(bits int) string {
+	switch bits {
+	case 32:
+		return "IPv4"
+	case 128:
+		return "IPv6"
+	default:
+		return "IP"
+	}
+}
+
 // CidrHost calculates a full host IP address within a given IP network address prefix.
 func CidrHost(prefix, hostnum cty.Value) (cty.Value, error) {
 	return CidrHostFunc.Call([]cty.Value{prefix, hostnum})
@@ -291,3 +677,32 @@ func CidrSubnets(prefix cty.Value, newbits ...cty.Value) (cty.Value, error) {
 func CidrContains(prefix, address cty.Value) (cty.Value, error) {
 	return CidrContainsFunc.Call([]cty.Value{prefix, address})
 }
+
+// CidrMerge aggregates a list of IP network address prefixes into the
+// smallest list of prefixes covering the same addresses.
+func CidrMerge(prefixes cty.Value) (cty.Value, error) {
+	return CidrMergeFunc.Call([]cty.Value{prefixes})
+}
+
+// CidrExclude removes a list of IP network address prefixes from a base prefix.
+func CidrExclude(prefix, excluded cty.Value) (cty.Value, error) {
+	return CidrExcludeFunc.Call([]cty.Value{prefix, excluded})
+}
+
+// CidrOverlaps checks whether two IP network address prefixes have any
+// addresses in common.
+func CidrOverlaps(a, b cty.Value) (cty.Value, error) {
+	return CidrOverlapsFunc.Call([]cty.Value{a, b})
+}
+
+// CidrHosts lists the IP addresses within a given IP network address prefix,
+// up to a given limit.
+func CidrHosts(prefix, limit cty.Value) (cty.Value, error) {
+	return CidrHostsFunc.Call([]cty.Value{prefix, limit})
+}
+
+// CidrNextFree finds the first subnet of a given size within a base IP
+// network address prefix that doesn't overlap any of the used prefixes.
+func CidrNextFree(prefix, used, newbits cty.Value) (cty.Value, error) {
+	return CidrNextFreeFunc.Call([]cty.Value{prefix, used, newbits})
+}
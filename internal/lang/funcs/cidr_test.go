@@ -571,3 +571,342 @@ func TestCidrContains(t *testing.T) {
 		})
 	}
 }
+
+func cidrList(prefixes ...string) cty.Value {
+	if len(prefixes) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	vals := make([]cty.Value, len(prefixes))
+	for i, prefix := range prefixes {
+		vals[i] = cty.StringVal(prefix)
+	}
+	return cty.ListVal(vals)
+}
+
+func TestCidrMerge(t *testing.T) {
+	tests := []struct {
+		Prefixes cty.Value
+		Want     cty.Value
+		Err      bool
+	}{
+		{
+			cidrList("10.0.0.0/24", "10.0.1.0/24"),
+			cidrList("10.0.0.0/23"),
+			false,
+		},
+		{
+			cidrList("10.0.1.0/24", "10.0.2.0/24"),
+			cidrList("10.0.1.0/24", "10.0.2.0/24"),
+			false,
+		},
+		{
+			cidrList("10.0.0.0/16", "10.0.3.0/24", "192.168.0.0/25", "192.168.0.128/25"),
+			cidrList("10.0.0.0/16", "192.168.0.0/24"),
+			false,
+		},
+		{
+			cidrList("10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"),
+			cidrList("10.0.0.0/23", "10.0.2.0/24"),
+			false,
+		},
+		{
+			cidrList("fd00::/65", "fd00:0:0:0:8000::/65", "10.0.0.0/8"),
+			cidrList("10.0.0.0/8", "fd00::/64"),
+			false,
+		},
+		{
+			cidrList("010.0.0.0/24"), // legacy octal is interpreted as decimal
+			cidrList("10.0.0.0/24"),
+			false,
+		},
+		{
+			cidrList(),
+			cidrList(),
+			false,
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("10.0.0.0/24"), cty.UnknownVal(cty.String)}),
+			cty.UnknownVal(cty.List(cty.String)).RefineNotNull(),
+			false,
+		},
+		{
+			cidrList("10.0.0.0/33"),
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrmerge(%#v)", test.Prefixes), func(t *testing.T) {
+			got, err := CidrMerge(test.Prefixes)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCidrExclude(t *testing.T) {
+	tests := []struct {
+		Prefix   cty.Value
+		Excluded cty.Value
+		Want     cty.Value
+		Err      bool
+	}{
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cidrList("10.0.0.0/24"),
+			cidrList("10.0.1.0/24", "10.0.2.0/23", "10.0.4.0/22", "10.0.8.0/21", "10.0.16.0/20", "10.0.32.0/19", "10.0.64.0/18", "10.0.128.0/17"),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cidrList("10.0.0.64/26", "10.0.0.192/26"),
+			cidrList("10.0.0.0/26", "10.0.0.128/26"),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cidrList("10.0.0.0/8"),
+			cidrList(),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cidrList("192.168.0.0/24"),
+			cidrList("10.0.0.0/24"),
+			false,
+		},
+		{
+			cty.StringVal("fd00::/63"),
+			cidrList("fd00::/64"),
+			cidrList("fd00:0:0:1::/64"),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cidrList("fd00::/64"),
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrexclude(%#v, %#v)", test.Prefix, test.Excluded), func(t *testing.T) {
+			got, err := CidrExclude(test.Prefix, test.Excluded)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCidrOverlaps(t *testing.T) {
+	tests := []struct {
+		A, B cty.Value
+		Want cty.Value
+		Err  bool
+	}{
+		{cty.StringVal("10.0.0.0/16"), cty.StringVal("10.0.5.0/24"), cty.True, false},
+		{cty.StringVal("10.0.5.0/24"), cty.StringVal("10.0.0.0/16"), cty.True, false},
+		{cty.StringVal("10.0.0.0/24"), cty.StringVal("10.0.1.0/24"), cty.False, false},
+		{cty.StringVal("fd00::/64"), cty.StringVal("fd00::/48"), cty.True, false},
+		{cty.StringVal("10.0.0.0/24"), cty.StringVal("fd00::/64"), cty.NilVal, true},
+		{cty.StringVal("10.0.0.0/24"), cty.StringVal("10.0.0.1"), cty.NilVal, true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidroverlaps(%#v, %#v)", test.A, test.B), func(t *testing.T) {
+			got, err := CidrOverlaps(test.A, test.B)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCidrHosts(t *testing.T) {
+	tests := []struct {
+		Prefix cty.Value
+		Limit  cty.Value
+		Want   cty.Value
+		Err    bool
+	}{
+		{
+			cty.StringVal("10.0.0.0/30"),
+			cty.NumberIntVal(10),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0"),
+				cty.StringVal("10.0.0.1"),
+				cty.StringVal("10.0.0.2"),
+				cty.StringVal("10.0.0.3"),
+			}),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/8"),
+			cty.NumberIntVal(2),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0"),
+				cty.StringVal("10.0.0.1"),
+			}),
+			false,
+		},
+		{
+			cty.StringVal("fd00::/64"),
+			cty.NumberIntVal(2),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("fd00::"),
+				cty.StringVal("fd00::1"),
+			}),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/8"),
+			cty.NumberIntVal(0),
+			cty.ListValEmpty(cty.String),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/8"),
+			cty.NumberIntVal(-1),
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrhosts(%#v, %#v)", test.Prefix, test.Limit), func(t *testing.T) {
+			got, err := CidrHosts(test.Prefix, test.Limit)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCidrNextFree(t *testing.T) {
+	tests := []struct {
+		Prefix  cty.Value
+		Used    cty.Value
+		Newbits cty.Value
+		Want    cty.Value
+		Err     bool
+	}{
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cidrList(),
+			cty.NumberIntVal(8),
+			cty.StringVal("10.0.0.0/24"),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cidrList("10.0.0.0/24", "10.0.1.0/24", "10.0.3.0/24"),
+			cty.NumberIntVal(8),
+			cty.StringVal("10.0.2.0/24"),
+			false,
+		},
+		{ // the result must be aligned to its own size
+			cty.StringVal("10.0.0.0/16"),
+			cidrList("10.0.0.0/24"),
+			cty.NumberIntVal(6),
+			cty.StringVal("10.0.4.0/22"),
+			false,
+		},
+		{ // used prefixes outside the base are ignored
+			cty.StringVal("10.0.0.0/16"),
+			cidrList("10.0.0.0/25", "192.168.0.0/16", "10.1.0.0/16"),
+			cty.NumberIntVal(8),
+			cty.StringVal("10.0.1.0/24"),
+			false,
+		},
+		{
+			cty.StringVal("fd00::/56"),
+			cidrList("fd00::/64"),
+			cty.NumberIntVal(8),
+			cty.StringVal("fd00:0:0:1::/64"),
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cidrList("10.0.0.0/25", "10.0.0.128/25"),
+			cty.NumberIntVal(2),
+			cty.NilVal,
+			true,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cidrList(),
+			cty.NumberIntVal(9),
+			cty.NilVal,
+			true,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cidrList("fd00::/64"),
+			cty.NumberIntVal(2),
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrnextfree(%#v, %#v, %#v)", test.Prefix, test.Used, test.Newbits), func(t *testing.T) {
+			got, err := CidrNextFree(test.Prefix, test.Used, test.Newbits)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
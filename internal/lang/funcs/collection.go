@@ -6,16 +6,24 @@
 package funcs
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"math/big"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/jmespath/go-jmespath"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/function"
 	"github.com/zclconf/go-cty/cty/function/stdlib"
 	"github.com/zclconf/go-cty/cty/gocty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -624,6 +632,662 @@ var TransposeFunc = function.New(&function.Spec{
 	},
 })
 
+// DeepMergeFunc constructs a function that takes an arbitrary number of maps
+// or objects and returns a single value that contains a merged set of
+// elements from all arguments, recursively merging nested maps and objects.
+//
+// When more than one argument has an element with the same key, the element
+// from the later argument takes precedence unless both are maps or objects,
+// in which case they are merged. Lists, sets and tuples are never combined:
+// a later value replaces an earlier one. Null arguments and null elements
+// in later arguments never replace an existing value.
+var DeepMergeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{},
+	VarParam: &function.Parameter{
+		Name:             "maps",
+		Type:             cty.DynamicPseudoType,
+		AllowUnknown:     true,
+		AllowDynamicType: true,
+		AllowNull:        true,
+		AllowMarked:      true,
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		for i, arg := range args {
+			ty := arg.Type()
+			if ty != cty.DynamicPseudoType && !isMappingType(ty) {
+				return cty.NilType, function.NewArgErrorf(i, "arguments must be maps or objects, got %s", ty.FriendlyName())
+			}
+		}
+		return cty.DynamicPseudoType, nil
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		result := cty.NullVal(cty.DynamicPseudoType)
+		for _, arg := range args {
+			result = deepMerge(result, arg)
+		}
+		return result, nil
+	},
+})
+
+// JSONPatchFunc constructs a function that applies a sequence of JSON Patch
+// operations, as defined in RFC 6902, to a value.
+//
+// Each operation is an object with the attributes "op" and "path", and
+// "value" or "from" depending on the operation. Paths are JSON Pointers, as
+// defined in RFC 6901, and address attributes of objects, elements of maps,
+// and elements of lists and tuples. Elements of sets cannot be addressed.
+var JSONPatchFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "doc",
+			Type:             cty.DynamicPseudoType,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+			AllowNull:        true,
+			AllowMarked:      true,
+		},
+		{
+			Name:             "ops",
+			Type:             cty.DynamicPseudoType,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+			AllowMarked:      true,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		ty := args[1].Type()
+		if ty != cty.DynamicPseudoType && !ty.IsListType() && !ty.IsTupleType() {
+			return cty.NilType, function.NewArgErrorf(1, "must be a list of patch operations, got %s", ty.FriendlyName())
+		}
+		return cty.DynamicPseudoType, nil
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		doc := args[0]
+		ops, opsMarks := args[1].Unmark()
+		resultMarks := []cty.ValueMarks{opsMarks}
+		if !ops.IsKnown() {
+			return cty.DynamicVal.WithMarks(resultMarks...), nil
+		}
+
+		for it := ops.ElementIterator(); it.Next(); {
+			idx, op := it.Element()
+			i, _ := idx.AsBigFloat().Int64()
+			op, opMarks := op.Unmark()
+			resultMarks = append(resultMarks, opMarks)
+			if !op.IsKnown() {
+				return cty.DynamicVal.WithMarks(resultMarks...), nil
+			}
+			if op.IsNull() || !isMappingType(op.Type()) {
+				return cty.NilVal, function.NewArgErrorf(1, "operation %d must be an object", i)
+			}
+
+			var fields [3]string
+			for fi, name := range []string{"op", "path", "from"} {
+				v, exists := mappingElement(op, name)
+				if !exists {
+					if name == "from" {
+						continue
+					}
+					return cty.NilVal, function.NewArgErrorf(1, "operation %d is missing the %q attribute", i, name)
+				}
+				v, vMarks := v.Unmark()
+				resultMarks = append(resultMarks, vMarks)
+				if !v.IsKnown() {
+					return cty.DynamicVal.WithMarks(resultMarks...), nil
+				}
+				v, err := convert.Convert(v, cty.String)
+				if err != nil || v.IsNull() {
+					return cty.NilVal, function.NewArgErrorf(1, "operation %d: %q must be a string", i, name)
+				}
+				fields[fi] = v.AsString()
+			}
+			value, hasValue := mappingElement(op, "value")
+
+			var err error
+			switch opName, path, from := fields[0], fields[1], fields[2]; opName {
+			case "add", "replace", "test":
+				if !hasValue {
+					return cty.NilVal, function.NewArgErrorf(1, "operation %d (%s) is missing the \"value\" attribute", i, opName)
+				}
+				switch opName {
+				case "add":
+					doc, err = jsonPatchAdd(doc, path, value)
+				case "replace":
+					doc, err = jsonPatchReplace(doc, path, value)
+				case "test":
+					err = jsonPatchTest(doc, path, value)
+				}
+			case "remove":
+				doc, err = jsonPatchRemove(doc, path)
+			case "move", "copy":
+				if _, exists := mappingElement(op, "from"); !exists {
+					return cty.NilVal, function.NewArgErrorf(1, "operation %d (%s) is missing the \"from\" attribute", i, opName)
+				}
+				if opName == "move" {
+					doc, err = jsonPatchMove(doc, from, path)
+				} else {
+					doc, err = jsonPatchCopy(doc, from, path)
+				}
+			default:
+				return cty.NilVal, function.NewArgErrorf(1, "operation %d has unsupported op %q", i, opName)
+			}
+			if errors.Is(err, errPatchUnknown) {
+				return cty.DynamicVal.WithMarks(resultMarks...), nil
+			}
+			if err != nil {
+				return cty.NilVal, function.NewArgErrorf(1, "operation %d (%s): %s", i, fields[0], err)
+			}
+		}
+		return doc.WithMarks(resultMarks...), nil
+	},
+})
+
+// JSONMergePatchFunc constructs a function that applies a JSON Merge Patch,
+// as defined in RFC 7396, to a value.
+//
+// Maps and objects in the patch are merged into the corresponding value,
+// with null elements removing the corresponding key. Any other value in
+// the patch replaces the corresponding value.
+var JSONMergePatchFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "doc",
+			Type:             cty.DynamicPseudoType,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+			AllowNull:        true,
+			AllowMarked:      true,
+		},
+		{
+			Name:             "patch",
+			Type:             cty.DynamicPseudoType,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+			AllowNull:        true,
+			AllowMarked:      true,
+		},
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return jsonMergePatch(args[0], args[1]), nil
+	},
+})
+
+func isMappingType(ty cty.Type) bool {
+	return ty.IsObjectType() || ty.IsMapType()
+}
+
+// mappingElement returns the element of the given known, non-null and
+// unmarked map or object with the given key, if it exists.
+func mappingElement(v cty.Value, key string) (cty.Value, bool) {
+	if v.Type().IsObjectType() {
+		if !v.Type().HasAttribute(key) {
+			return cty.NilVal, false
+		}
+		return v.GetAttr(key), true
+	}
+	k := cty.StringVal(key)
+	if !v.HasIndex(k).True() {
+		return cty.NilVal, false
+	}
+	return v.Index(k), true
+}
+
+// mappingVal returns a map containing the given elements if asMap is set
+// and all of the elements have the same type, or an object otherwise.
+func mappingVal(elems map[string]cty.Value, asMap bool) cty.Value {
+	if len(elems) == 0 {
+		return cty.EmptyObjectVal
+	}
+	if asMap && sameElementTypes(slices.Collect(maps.Values(elems))) {
+		return cty.MapVal(elems)
+	}
+	return cty.ObjectVal(elems)
+}
+
+// mappingValOf is like mappingVal, but keeps the type of an original map
+// that has no elements left.
+func mappingValOf(elems map[string]cty.Value, orig cty.Type) cty.Value {
+	if len(elems) == 0 && orig.IsMapType() {
+		return cty.MapValEmpty(orig.ElementType())
+	}
+	return mappingVal(elems, orig.IsMapType())
+}
+
+// sequenceVal returns a list containing the given elements if the original
+// type was a list and all of the elements have the same type, or a tuple
+// otherwise.
+func sequenceVal(elems []cty.Value, orig cty.Type) cty.Value {
+	if len(elems) == 0 {
+		if orig.IsListType() {
+			return cty.ListValEmpty(orig.ElementType())
+		}
+		return cty.EmptyTupleVal
+	}
+	if orig.IsListType() && sameElementTypes(elems) {
+		return cty.ListVal(elems)
+	}
+	return cty.TupleVal(elems)
+}
+
+func sameElementTypes(elems []cty.Value) bool {
+	for _, elem := range elems {
+		ty := elem.Type()
+		if ty == cty.DynamicPseudoType || !ty.Equals(elems[0].Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+func deepMerge(a, b cty.Value) cty.Value {
+	au, aMarks := a.Unmark()
+	bu, bMarks := b.Unmark()
+	switch {
+	case bu.IsKnown() && bu.IsNull():
+		return a.WithMarks(bMarks)
+	case au.IsKnown() && au.IsNull():
+		return b
+	case !bu.IsKnown():
+		// The unknown value could be null, in which case it wouldn't replace
+		// a, so we can only predict the type if both have the same type.
+		if !isMappingType(bu.Type()) && bu.Type().Equals(au.Type()) {
+			return cty.UnknownVal(bu.Type()).WithMarks(aMarks, bMarks)
+		}
+		return cty.DynamicVal.WithMarks(aMarks, bMarks)
+	case !isMappingType(bu.Type()):
+		return b
+	case !au.IsKnown() && (isMappingType(au.Type()) || au.Type() == cty.DynamicPseudoType):
+		return cty.DynamicVal.WithMarks(aMarks, bMarks)
+	case !au.IsKnown() || !isMappingType(au.Type()):
+		return b
+	}
+
+	elems := make(map[string]cty.Value)
+	for it := au.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		elems[k.AsString()] = v
+	}
+	for it := bu.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		if existing, exists := elems[k.AsString()]; exists {
+			elems[k.AsString()] = deepMerge(existing, v)
+		} else {
+			elems[k.AsString()] = v
+		}
+	}
+	return mappingVal(elems, au.Type().IsMapType() && bu.Type().IsMapType()).WithMarks(aMarks, bMarks)
+}
+
+func jsonMergePatch(target, patch cty.Value) cty.Value {
+	pu, pMarks := patch.Unmark()
+	if !pu.IsKnown() && (isMappingType(pu.Type()) || pu.Type() == cty.DynamicPseudoType) {
+		return cty.DynamicVal.WithMarks(pMarks)
+	}
+	if !pu.IsKnown() || pu.IsNull() || !isMappingType(pu.Type()) {
+		return patch
+	}
+
+	tu, tMarks := target.Unmark()
+	if !tu.IsKnown() {
+		return cty.DynamicVal.WithMarks(tMarks, pMarks)
+	}
+	elems := make(map[string]cty.Value)
+	asMap := pu.Type().IsMapType()
+	if !tu.IsNull() && isMappingType(tu.Type()) {
+		for it := tu.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			elems[k.AsString()] = v
+		}
+		asMap = asMap && tu.Type().IsMapType()
+	} else {
+		tMarks = nil
+	}
+
+	for it := pu.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		vu, vMarks := v.Unmark()
+		switch {
+		case !vu.IsKnown():
+			// We can't tell whether the key will be removed.
+			return cty.DynamicVal.WithMarks(tMarks, pMarks, vMarks)
+		case vu.IsNull():
+			delete(elems, k.AsString())
+		default:
+			existing, exists := elems[k.AsString()]
+			if !exists {
+				existing = cty.NullVal(cty.DynamicPseudoType)
+			}
+			elems[k.AsString()] = jsonMergePatch(existing, v)
+		}
+	}
+	return mappingVal(elems, asMap).WithMarks(tMarks, pMarks)
+}
+
+// errPatchUnknown is returned by the JSON Patch operations when the result
+// depends on a value that isn't known yet.
+var errPatchUnknown = errors.New("patch depends on unknown value")
+
+// parseJSONPointer splits a JSON Pointer, as defined in RFC 6901, into its
+// unescaped reference tokens.
+func parseJSONPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid path %q: must be empty or start with \"/\"", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	for i, tok := range tokens {
+		tokens[i] = unescape.Replace(tok)
+	}
+	return tokens, nil
+}
+
+// sequenceIndex parses an array index from a JSON Pointer token. If
+// allowEnd is set, the index may be equal to the length of the sequence,
+// and "-" refers to that position.
+func sequenceIndex(tok string, length int, allowEnd bool) (int, bool) {
+	if tok == "-" && allowEnd {
+		return length, true
+	}
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(tok)
+	if err != nil || idx < 0 || idx > length || (idx == length && !allowEnd) {
+		return 0, false
+	}
+	return idx, true
+}
+
+// jsonPatchGet returns the value at the given path, with the marks of all
+// of the values containing it.
+func jsonPatchGet(doc cty.Value, ptr string) (cty.Value, error) {
+	tokens, err := parseJSONPointer(ptr)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	var pathMarks []cty.ValueMarks
+	for _, tok := range tokens {
+		v, vMarks := doc.Unmark()
+		pathMarks = append(pathMarks, vMarks)
+		if !v.IsKnown() {
+			return cty.NilVal, errPatchUnknown
+		}
+		var exists bool
+		doc, exists = jsonPatchElement(v, tok)
+		if !exists {
+			return cty.NilVal, fmt.Errorf("path %q does not exist", ptr)
+		}
+	}
+	return doc.WithMarks(pathMarks...), nil
+}
+
+// jsonPatchElement returns the element of the given known and unmarked
+// value that the given token refers to, if it exists.
+func jsonPatchElement(v cty.Value, tok string) (cty.Value, bool) {
+	ty := v.Type()
+	switch {
+	case v.IsNull():
+		return cty.NilVal, false
+	case isMappingType(ty):
+		return mappingElement(v, tok)
+	case ty.IsListType() || ty.IsTupleType():
+		idx, ok := sequenceIndex(tok, v.LengthInt(), false)
+		if !ok {
+			return cty.NilVal, false
+		}
+		return v.Index(cty.NumberIntVal(int64(idx))), true
+	default:
+		return cty.NilVal, false
+	}
+}
+
+// jsonPatchUpdate rebuilds doc with the container at the parent of the
+// given path replaced by the result of calling fn with that container and
+// the last token of the path. The path must not be empty.
+func jsonPatchUpdate(doc cty.Value, ptr string, fn func(container cty.Value, tok string) (cty.Value, error)) (cty.Value, error) {
+	tokens, err := parseJSONPointer(ptr)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	var update func(cur cty.Value, tokens []string) (cty.Value, error)
+	update = func(cur cty.Value, tokens []string) (cty.Value, error) {
+		v, vMarks := cur.Unmark()
+		if !v.IsKnown() {
+			return cty.NilVal, errPatchUnknown
+		}
+		if len(tokens) == 1 {
+			if v.IsNull() {
+				return cty.NilVal, fmt.Errorf("path %q does not exist", ptr)
+			}
+			ret, err := fn(v, tokens[0])
+			if err != nil {
+				return cty.NilVal, err
+			}
+			return ret.WithMarks(vMarks), nil
+		}
+		child, exists := jsonPatchElement(v, tokens[0])
+		if !exists {
+			return cty.NilVal, fmt.Errorf("path %q does not exist", ptr)
+		}
+		child, err := update(child, tokens[1:])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return jsonPatchSetElement(v, tokens[0], child, false).WithMarks(vMarks), nil
+	}
+	return update(doc, tokens)
+}
+
+// jsonPatchSetElement returns a copy of the given known and unmarked value
+// with the element that the given token refers to set to elem. If insert
+// is set, elem is inserted into lists and tuples rather than replacing the
+// existing element. The caller must check that the token is valid.
+func jsonPatchSetElement(v cty.Value, tok string, elem cty.Value, insert bool) cty.Value {
+	ty := v.Type()
+	if isMappingType(ty) {
+		elems := v.AsValueMap()
+		if elems == nil {
+			elems = make(map[string]cty.Value)
+		}
+		elems[tok] = elem
+		return mappingValOf(elems, ty)
+	}
+	elems := v.AsValueSlice()
+	idx, _ := sequenceIndex(tok, len(elems), insert)
+	if insert {
+		elems = slices.Insert(elems, idx, elem)
+	} else {
+		elems[idx] = elem
+	}
+	return sequenceVal(elems, ty)
+}
+
+func jsonPatchAdd(doc cty.Value, ptr string, value cty.Value) (cty.Value, error) {
+	if ptr == "" {
+		return value, nil
+	}
+	return jsonPatchUpdate(doc, ptr, func(container cty.Value, tok string) (cty.Value, error) {
+		ty := container.Type()
+		switch {
+		case isMappingType(ty):
+		case ty.IsListType() || ty.IsTupleType():
+			if _, ok := sequenceIndex(tok, container.LengthInt(), true); !ok {
+				return cty.NilVal, fmt.Errorf("path %q does not refer to a valid index", ptr)
+			}
+		default:
+			return cty.NilVal, fmt.Errorf("path %q does not refer to an element of an object, map, list or tuple", ptr)
+		}
+		return jsonPatchSetElement(container, tok, value, true), nil
+	})
+}
+
+func jsonPatchRemove(doc cty.Value, ptr string) (cty.Value, error) {
+	if ptr == "" {
+		return cty.NilVal, errors.New("cannot remove the whole document")
+	}
+	return jsonPatchUpdate(doc, ptr, func(container cty.Value, tok string) (cty.Value, error) {
+		if _, exists := jsonPatchElement(container, tok); !exists {
+			return cty.NilVal, fmt.Errorf("path %q does not exist", ptr)
+		}
+		if isMappingType(container.Type()) {
+			elems := container.AsValueMap()
+			delete(elems, tok)
+			return mappingValOf(elems, container.Type()), nil
+		}
+		elems := container.AsValueSlice()
+		idx, _ := sequenceIndex(tok, len(elems), false)
+		return sequenceVal(slices.Delete(elems, idx, idx+1), container.Type()), nil
+	})
+}
+
+func jsonPatchReplace(doc cty.Value, ptr string, value cty.Value) (cty.Value, error) {
+	if ptr == "" {
+		return value, nil
+	}
+	return jsonPatchUpdate(doc, ptr, func(container cty.Value, tok string) (cty.Value, error) {
+		if _, exists := jsonPatchElement(container, tok); !exists {
+			return cty.NilVal, fmt.Errorf("path %q does not exist", ptr)
+		}
+		return jsonPatchSetElement(container, tok, value, false), nil
+	})
+}
+
+func jsonPatchMove(doc cty.Value, from, ptr string) (cty.Value, error) {
+	if from == ptr {
+		_, err := jsonPatchGet(doc, from)
+		return doc, err
+	}
+	if strings.HasPrefix(ptr, from+"/") {
+		return cty.NilVal, fmt.Errorf("cannot move %q into itself", from)
+	}
+	value, err := jsonPatchGet(doc, from)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	doc, err = jsonPatchRemove(doc, from)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return jsonPatchAdd(doc, ptr, value)
+}
+
+func jsonPatchCopy(doc cty.Value, from, ptr string) (cty.Value, error) {
+	value, err := jsonPatchGet(doc, from)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return jsonPatchAdd(doc, ptr, value)
+}
+
+func jsonPatchTest(doc cty.Value, ptr string, value cty.Value) error {
+	got, err := jsonPatchGet(doc, ptr)
+	if err != nil {
+		return err
+	}
+	// Values are compared by their JSON representation, so that for
+	// example a map and an object with the same elements are equal.
+	got, _ = got.UnmarkDeep()
+	value, _ = value.UnmarkDeep()
+	if !got.IsWhollyKnown() || !value.IsWhollyKnown() {
+		return errPatchUnknown
+	}
+	gotJSON, err := ctyjson.Marshal(got, got.Type())
+	if err != nil {
+		return err
+	}
+	wantJSON, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(gotJSON, wantJSON) {
+		return fmt.Errorf("the value at %q does not match", ptr)
+	}
+	return nil
+}
+
+// QueryFunc constructs a function that evaluates a JMESPath expression
+// against a value, returning the result.
+var QueryFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+			AllowNull:        true,
+		},
+		{
+			Name: "expression",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		val := args[0]
+
+		jp, err := jmespath.Compile(args[1].AsString())
+		if err != nil {
+			return cty.DynamicVal, function.NewArgErrorf(1, "invalid query expression: %s", err)
+		}
+		// JMESPath expressions can inspect any part of the value, including
+		// through filters and functions, so we can't predict which parts of
+		// the result would depend on an unknown value.
+		if !val.IsWhollyKnown() {
+			return cty.DynamicVal, nil
+		}
+
+		data, err := queryData(val)
+		if err != nil {
+			return cty.DynamicVal, function.NewArgError(0, err)
+		}
+		result, err := jp.Search(data)
+		if err != nil {
+			return cty.DynamicVal, fmt.Errorf("failed to evaluate query: %w", err)
+		}
+		return queryResultVal(result)
+	},
+})
+
+// queryData converts a wholly-known, unmarked value into the JSON-like
+// representation that the JMESPath interpreter expects.
+func queryData(val cty.Value) (interface{}, error) {
+	if val.IsNull() {
+		return nil, nil
+	}
+	buf, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	if err := json.Unmarshal(buf, &data); err != nil {
+		// Should never happen, since we just produced this JSON ourselves.
+		return nil, err
+	}
+	return data, nil
+}
+
+// queryResultVal converts the result of a JMESPath query back into a value,
+// using the same types that jsondecode would produce for the equivalent
+// JSON.
+func queryResultVal(result interface{}) (cty.Value, error) {
+	if result == nil {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+	buf, err := json.Marshal(result)
+	if err != nil {
+		return cty.DynamicVal, err
+	}
+	ty, err := ctyjson.ImpliedType(buf)
+	if err != nil {
+		return cty.DynamicVal, err
+	}
+	return ctyjson.Unmarshal(buf, ty)
+}
+
 // ListFunc constructs a function that takes an arbitrary number of arguments
 // and returns a list containing those values in the same order.
 //
@@ -684,6 +1348,21 @@ func AnyTrue(collection cty.Value) (cty.Value, error) {
 	return AnyTrueFunc.Call([]cty.Value{collection})
 }
 
+// DeepMerge recursively merges the given maps or objects.
+func DeepMerge(args ...cty.Value) (cty.Value, error) {
+	return DeepMergeFunc.Call(args)
+}
+
+// JSONPatch applies a sequence of RFC 6902 JSON Patch operations to a value.
+func JSONPatch(doc, ops cty.Value) (cty.Value, error) {
+	return JSONPatchFunc.Call([]cty.Value{doc, ops})
+}
+
+// JSONMergePatch applies an RFC 7396 JSON Merge Patch to a value.
+func JSONMergePatch(doc, patch cty.Value) (cty.Value, error) {
+	return JSONMergePatchFunc.Call([]cty.Value{doc, patch})
+}
+
 // Coalesce takes any number of arguments and returns the first one that isn't empty.
 func Coalesce(args ...cty.Value) (cty.Value, error) {
 	return CoalesceFunc.Call(args)
@@ -727,6 +1406,12 @@ func One(list cty.Value) (cty.Value, error) {
 	return OneFunc.Call([]cty.Value{list})
 }
 
+// Query evaluates a JMESPath expression against a value, returning the
+// result.
+func Query(value, expression cty.Value) (cty.Value, error) {
+	return QueryFunc.Call([]cty.Value{value, expression})
+}
+
 // Sum adds numbers in a list, set, or tuple
 func Sum(list cty.Value) (cty.Value, error) {
 	return SumFunc.Call([]cty.Value{list})
@@ -8,6 +8,7 @@ package funcs
 import (
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/opentofu/opentofu/internal/lang/marks"
@@ -1860,3 +1861,559 @@ func TestTranspose(t *testing.T) {
 		})
 	}
 }
+
+func TestDeepMerge(t *testing.T) {
+	tests := []struct {
+		Values []cty.Value
+		Want   cty.Value
+		Err    bool
+	}{
+		{ // nested objects are merged, later values win
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("web"),
+					"settings": cty.ObjectVal(map[string]cty.Value{
+						"port":    cty.NumberIntVal(80),
+						"timeout": cty.NumberIntVal(30),
+					}),
+					"zones": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"settings": cty.ObjectVal(map[string]cty.Value{
+						"port": cty.NumberIntVal(8080),
+					}),
+					"zones": cty.ListVal([]cty.Value{cty.StringVal("c")}),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"settings": cty.ObjectVal(map[string]cty.Value{
+					"port":    cty.NumberIntVal(8080),
+					"timeout": cty.NumberIntVal(30),
+				}),
+				"zones": cty.ListVal([]cty.Value{cty.StringVal("c")}),
+			}),
+			false,
+		},
+		{ // maps of the same type stay maps
+			[]cty.Value{
+				cty.MapVal(map[string]cty.Value{
+					"a": cty.MapVal(map[string]cty.Value{"x": cty.StringVal("1")}),
+				}),
+				cty.MapVal(map[string]cty.Value{
+					"a": cty.MapVal(map[string]cty.Value{"y": cty.StringVal("2")}),
+				}),
+			},
+			cty.MapVal(map[string]cty.Value{
+				"a": cty.MapVal(map[string]cty.Value{
+					"x": cty.StringVal("1"),
+					"y": cty.StringVal("2"),
+				}),
+			}),
+			false,
+		},
+		{ // null values don't replace existing values
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("default"),
+				}),
+				cty.NullVal(cty.EmptyObject),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.NullVal(cty.String),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("default"),
+			}),
+			false,
+		},
+		{ // marks are preserved where they were
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"db": cty.ObjectVal(map[string]cty.Value{
+						"host": cty.StringVal("localhost"),
+					}),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"db": cty.ObjectVal(map[string]cty.Value{
+						"password": cty.StringVal("hunter2").Mark(marks.Sensitive),
+					}),
+				}).Mark(marks.Ephemeral),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"db": cty.ObjectVal(map[string]cty.Value{
+					"host":     cty.StringVal("localhost"),
+					"password": cty.StringVal("hunter2").Mark(marks.Sensitive),
+				}),
+			}).Mark(marks.Ephemeral),
+			false,
+		},
+		{ // unknown nested objects make that part of the result unknown
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{"x": cty.StringVal("1")}),
+					"b": cty.StringVal("b"),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.UnknownVal(cty.EmptyObject),
+					"b": cty.UnknownVal(cty.String),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.DynamicVal,
+				"b": cty.UnknownVal(cty.String),
+			}),
+			false,
+		},
+		{ // non-map arguments are rejected
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("b")}),
+				cty.ListVal([]cty.Value{cty.StringVal("c")}),
+			},
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("deepmerge(%#v)", test.Values), func(t *testing.T) {
+			got, err := DeepMerge(test.Values...)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	op := func(attrs ...any) cty.Value {
+		m := make(map[string]cty.Value)
+		for i := 0; i < len(attrs); i += 2 {
+			v, ok := attrs[i+1].(cty.Value)
+			if !ok {
+				v = cty.StringVal(attrs[i+1].(string))
+			}
+			m[attrs[i].(string)] = v
+		}
+		return cty.ObjectVal(m)
+	}
+	doc := cty.ObjectVal(map[string]cty.Value{
+		"name":  cty.StringVal("web"),
+		"zones": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		"tags": cty.MapVal(map[string]cty.Value{
+			"env": cty.StringVal("prod"),
+		}),
+		"secret": cty.ObjectVal(map[string]cty.Value{
+			"key": cty.StringVal("abc"),
+		}).Mark(marks.Sensitive),
+	})
+
+	tests := map[string]struct {
+		Doc  cty.Value
+		Ops  []cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		"add, remove and replace": {
+			doc,
+			[]cty.Value{
+				op("op", "add", "path", "/zones/-", "value", cty.StringVal("c")),
+				op("op", "add", "path", "/zones/0", "value", cty.StringVal("z")),
+				op("op", "remove", "path", "/tags/env"),
+				op("op", "add", "path", "/tags/a~1b", "value", cty.StringVal("c")),
+				op("op", "replace", "path", "/name", "value", cty.NumberIntVal(1)),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.NumberIntVal(1),
+				"zones": cty.ListVal([]cty.Value{
+					cty.StringVal("z"), cty.StringVal("a"), cty.StringVal("b"), cty.StringVal("c"),
+				}),
+				"tags": cty.MapVal(map[string]cty.Value{
+					"a/b": cty.StringVal("c"),
+				}),
+				"secret": cty.ObjectVal(map[string]cty.Value{
+					"key": cty.StringVal("abc"),
+				}).Mark(marks.Sensitive),
+			}),
+			``,
+		},
+		"move, copy and test": {
+			doc,
+			[]cty.Value{
+				op("op", "test", "path", "/zones", "value", cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})),
+				op("op", "copy", "from", "/secret/key", "path", "/key"),
+				op("op", "move", "from", "/tags", "path", "/labels"),
+				op("op", "remove", "path", "/zones"),
+				op("op", "remove", "path", "/secret"),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"key":  cty.StringVal("abc").Mark(marks.Sensitive),
+				"labels": cty.MapVal(map[string]cty.Value{
+					"env": cty.StringVal("prod"),
+				}),
+			}),
+			``,
+		},
+		"replace whole document": {
+			doc,
+			[]cty.Value{
+				op("op", "replace", "path", "", "value", cty.StringVal("new")),
+			},
+			cty.StringVal("new"),
+			``,
+		},
+		"unknown value": {
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.UnknownVal(cty.EmptyObject),
+			}),
+			[]cty.Value{
+				op("op", "add", "path", "/a/b", "value", cty.StringVal("c")),
+			},
+			cty.DynamicVal,
+			``,
+		},
+		"failed test": {
+			doc,
+			[]cty.Value{
+				op("op", "test", "path", "/name", "value", cty.StringVal("db")),
+			},
+			cty.NilVal,
+			`operation 0 (test): the value at "/name" does not match`,
+		},
+		"missing path": {
+			doc,
+			[]cty.Value{
+				op("op", "remove", "path", "/nope"),
+			},
+			cty.NilVal,
+			`operation 0 (remove): path "/nope" does not exist`,
+		},
+		"index out of range": {
+			doc,
+			[]cty.Value{
+				op("op", "add", "path", "/zones/3", "value", cty.StringVal("c")),
+			},
+			cty.NilVal,
+			`operation 0 (add): path "/zones/3" does not refer to a valid index`,
+		},
+		"move into itself": {
+			doc,
+			[]cty.Value{
+				op("op", "move", "from", "/tags", "path", "/tags/inner"),
+			},
+			cty.NilVal,
+			`operation 0 (move): cannot move "/tags" into itself`,
+		},
+		"unsupported op": {
+			doc,
+			[]cty.Value{
+				op("op", "frobnicate", "path", "/name"),
+			},
+			cty.NilVal,
+			`operation 0 has unsupported op "frobnicate"`,
+		},
+		"missing value": {
+			doc,
+			[]cty.Value{
+				op("op", "add", "path", "/name"),
+			},
+			cty.NilVal,
+			`operation 0 (add) is missing the "value" attribute`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := JSONPatch(test.Doc, cty.TupleVal(test.Ops))
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestJSONMergePatch(t *testing.T) {
+	tests := []struct {
+		Doc   cty.Value
+		Patch cty.Value
+		Want  cty.Value
+	}{
+		{ // the example from RFC 7396
+			cty.ObjectVal(map[string]cty.Value{
+				"title": cty.StringVal("Goodbye!"),
+				"author": cty.ObjectVal(map[string]cty.Value{
+					"givenName":  cty.StringVal("John"),
+					"familyName": cty.StringVal("Doe"),
+				}),
+				"tags":    cty.TupleVal([]cty.Value{cty.StringVal("example"), cty.StringVal("sample")}),
+				"content": cty.StringVal("This will be unchanged"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"title":       cty.StringVal("Hello!"),
+				"phoneNumber": cty.StringVal("+01-123-456-7890"),
+				"author": cty.ObjectVal(map[string]cty.Value{
+					"familyName": cty.NullVal(cty.String),
+				}),
+				"tags": cty.TupleVal([]cty.Value{cty.StringVal("example")}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"title": cty.StringVal("Hello!"),
+				"author": cty.ObjectVal(map[string]cty.Value{
+					"givenName": cty.StringVal("John"),
+				}),
+				"tags":        cty.TupleVal([]cty.Value{cty.StringVal("example")}),
+				"content":     cty.StringVal("This will be unchanged"),
+				"phoneNumber": cty.StringVal("+01-123-456-7890"),
+			}),
+		},
+		{ // a non-object patch replaces the document
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("b"),
+			}),
+			cty.StringVal("c"),
+			cty.StringVal("c"),
+		},
+		{ // nulls in new objects are removed
+			cty.StringVal("a"),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{
+					"b": cty.NullVal(cty.String),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.EmptyObjectVal,
+			}),
+		},
+		{ // marks are preserved
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("b").Mark(marks.Sensitive),
+				"c": cty.StringVal("d"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"c": cty.StringVal("e").Mark(marks.Ephemeral),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("b").Mark(marks.Sensitive),
+				"c": cty.StringVal("e").Mark(marks.Ephemeral),
+			}),
+		},
+		{ // an unknown patch value might remove its key
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("b"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.UnknownVal(cty.String),
+			}),
+			cty.DynamicVal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("jsonmergepatch(%#v, %#v)", test.Doc, test.Patch), func(t *testing.T) {
+			got, err := JSONMergePatch(test.Doc, test.Patch)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	doc := cty.ObjectVal(map[string]cty.Value{
+		"reservations": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"instances": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"id":    cty.StringVal("i-1"),
+						"state": cty.StringVal("running"),
+						"cpus":  cty.NumberIntVal(2),
+					}),
+					cty.ObjectVal(map[string]cty.Value{
+						"id":    cty.StringVal("i-2"),
+						"state": cty.StringVal("stopped"),
+						"cpus":  cty.NumberIntVal(4),
+					}),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"instances": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"id":    cty.StringVal("i-3"),
+						"state": cty.StringVal("running"),
+						"cpus":  cty.NumberIntVal(8),
+					}),
+				}),
+			}),
+		}),
+		"tags": cty.MapVal(map[string]cty.Value{
+			"env": cty.StringVal("prod"),
+		}),
+	})
+
+	tests := []struct {
+		Value      cty.Value
+		Expression cty.Value
+		Want       cty.Value
+		Err        string
+	}{
+		{
+			doc,
+			cty.StringVal("tags.env"),
+			cty.StringVal("prod"),
+			``,
+		},
+		{
+			doc,
+			cty.StringVal("reservations[].instances[].id"),
+			cty.TupleVal([]cty.Value{cty.StringVal("i-1"), cty.StringVal("i-2"), cty.StringVal("i-3")}),
+			``,
+		},
+		{
+			doc,
+			cty.StringVal("reservations[].instances[?state == 'running'].id"),
+			cty.TupleVal([]cty.Value{
+				cty.TupleVal([]cty.Value{cty.StringVal("i-1")}),
+				cty.TupleVal([]cty.Value{cty.StringVal("i-3")}),
+			}),
+			``,
+		},
+		{
+			doc,
+			cty.StringVal("reservations[].instances[] | [?cpus > `2`].{id: id, cpus: cpus}"),
+			cty.TupleVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"id":   cty.StringVal("i-2"),
+					"cpus": cty.NumberIntVal(4),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"id":   cty.StringVal("i-3"),
+					"cpus": cty.NumberIntVal(8),
+				}),
+			}),
+			``,
+		},
+		{
+			doc,
+			cty.StringVal("sum(reservations[].instances[].cpus)"),
+			cty.NumberIntVal(14),
+			``,
+		},
+		{
+			doc,
+			cty.StringVal("missing.attribute"),
+			cty.NullVal(cty.DynamicPseudoType),
+			``,
+		},
+		{
+			cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			cty.StringVal("[0]"),
+			cty.StringVal("a"),
+			``,
+		},
+		{
+			cty.NullVal(cty.DynamicPseudoType),
+			cty.StringVal("a"),
+			cty.NullVal(cty.DynamicPseudoType),
+			``,
+		},
+		{ // marks on any part of the value apply to the whole result
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("b"),
+				"c": cty.StringVal("d").Mark(marks.Sensitive),
+			}),
+			cty.StringVal("a"),
+			cty.StringVal("b").Mark(marks.Sensitive),
+			``,
+		},
+		{ // an unknown value anywhere makes the whole result unknown
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("b"),
+				"c": cty.UnknownVal(cty.String),
+			}),
+			cty.StringVal("a"),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			cty.UnknownVal(cty.EmptyObject),
+			cty.StringVal("a"),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			doc,
+			cty.UnknownVal(cty.String),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			doc,
+			cty.StringVal("reservations[?"),
+			cty.NilVal,
+			`invalid query expression: `,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.UnknownVal(cty.String),
+			}),
+			cty.StringVal("a.["),
+			cty.NilVal,
+			`invalid query expression: `,
+		},
+		{
+			doc,
+			cty.StringVal("abs(tags.env)"),
+			cty.NilVal,
+			`failed to evaluate query: `,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("query(%#v, %#v)", test.Value, test.Expression), func(t *testing.T) {
+			got, err := Query(test.Value, test.Expression)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.HasPrefix(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -6,18 +6,26 @@
 package funcs
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha3"
 	"crypto/sha512"
+	"crypto/x509"
 	"encoding/asn1"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/pem"
 	"fmt"
 	"hash"
 	"io"
 	"strings"
+	"time"
 
 	uuidv5 "github.com/google/uuid"
 	uuid "github.com/hashicorp/go-uuid"
@@ -25,6 +33,7 @@ import (
 	"github.com/zclconf/go-cty/cty/function"
 	"github.com/zclconf/go-cty/cty/gocty"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/blake2b"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -134,6 +143,61 @@ var BcryptFunc = function.New(&function.Spec{
 	},
 })
 
+// Blake2bFunc constructs a function that computes the BLAKE2b-512 hash of a
+// given string and encodes it with hexadecimal digits.
+var Blake2bFunc = makeStringHashFunction(newBlake2b512, hex.EncodeToString)
+
+func newBlake2b512() hash.Hash {
+	// blake2b.New512 can only fail when given a key that is too long.
+	h, _ := blake2b.New512(nil)
+	return h
+}
+
+// Ed25519VerifyFunc constructs a function that verifies an Ed25519 signature
+// of a given message.
+var Ed25519VerifyFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "public_key",
+			Type: cty.String,
+		},
+		{
+			Name: "message",
+			Type: cty.String,
+		},
+		{
+			Name: "signature",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		key, err := parsePublicKey(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgError(0, err)
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "public key must be an Ed25519 key, not %s", publicKeyAlgorithmName(key))
+		}
+		sig, err := base64.StdEncoding.DecodeString(args[2].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(2, "signature must be base64-encoded")
+		}
+
+		return cty.BoolVal(ed25519.Verify(edKey, []byte(args[1].AsString()), sig)), nil
+	},
+})
+
+// HmacSha256Func constructs a function that computes the HMAC-SHA256 of a
+// given message using a given key, and encodes it with hexadecimal digits.
+var HmacSha256Func = makeHmacFunction(sha256.New)
+
+// HmacSha512Func constructs a function that computes the HMAC-SHA512 of a
+// given message using a given key, and encodes it with hexadecimal digits.
+var HmacSha512Func = makeHmacFunction(sha512.New)
+
 // Md5Func constructs a function that computes the MD5 hash of a given string and encodes it with hexadecimal digits.
 var Md5Func = makeStringHashFunction(md5.New, hex.EncodeToString)
 
@@ -143,6 +207,55 @@ func MakeFileMd5Func(baseDir string) function.Function {
 	return makeFileHashFunction(baseDir, md5.New, hex.EncodeToString)
 }
 
+// PemDecodeFunc constructs a function that decodes all of the PEM blocks in
+// a given string.
+var PemDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.List(pemBlockType)),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		var blocks []cty.Value
+		rest := []byte(args[0].AsString())
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			headers := cty.MapValEmpty(cty.String)
+			if len(block.Headers) > 0 {
+				m := make(map[string]cty.Value, len(block.Headers))
+				for k, v := range block.Headers {
+					m[k] = cty.StringVal(v)
+				}
+				headers = cty.MapVal(m)
+			}
+			blocks = append(blocks, cty.ObjectVal(map[string]cty.Value{
+				"type":    cty.StringVal(block.Type),
+				"headers": headers,
+				"bytes":   cty.StringVal(base64.StdEncoding.EncodeToString(block.Bytes)),
+			}))
+		}
+		if len(blocks) == 0 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "no PEM blocks found")
+		}
+		return cty.ListVal(blocks), nil
+	},
+})
+
+// pemBlockType is the type of each element of the list returned by
+// PemDecodeFunc.
+var pemBlockType = cty.Object(map[string]cty.Type{
+	"type":    cty.String,
+	"headers": cty.Map(cty.String),
+	"bytes":   cty.String,
+})
+
 // RsaDecryptFunc constructs a function that decrypts an RSA-encrypted ciphertext.
 var RsaDecryptFunc = function.New(&function.Spec{
 	Params: []function.Parameter{
@@ -193,6 +306,45 @@ var RsaDecryptFunc = function.New(&function.Spec{
 	},
 })
 
+// RsaVerifyFunc constructs a function that verifies an RSA signature of a
+// given message, using the RSASSA-PKCS1-v1_5 scheme with SHA-256.
+var RsaVerifyFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "public_key",
+			Type: cty.String,
+		},
+		{
+			Name: "message",
+			Type: cty.String,
+		},
+		{
+			Name: "signature",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		key, err := parsePublicKey(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgError(0, err)
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "public key must be an RSA key, not %s", publicKeyAlgorithmName(key))
+		}
+		sig, err := base64.StdEncoding.DecodeString(args[2].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(2, "signature must be base64-encoded")
+		}
+
+		digest := sha256.Sum256([]byte(args[1].AsString()))
+		err = rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], sig)
+		return cty.BoolVal(err == nil), nil
+	},
+})
+
 // Sha1Func constructs a function that computes the SHA1 hash of a given string
 // and encodes it with hexadecimal digits.
 var Sha1Func = makeStringHashFunction(sha1.New, hex.EncodeToString)
@@ -223,6 +375,179 @@ func MakeFileSha512Func(baseDir string) function.Function {
 	return makeFileHashFunction(baseDir, sha512.New, hex.EncodeToString)
 }
 
+// Sha3_256Func constructs a function that computes the SHA3-256 hash of a
+// given string and encodes it with hexadecimal digits.
+var Sha3_256Func = makeStringHashFunction(func() hash.Hash { return sha3.New256() }, hex.EncodeToString)
+
+// Sha3_512Func constructs a function that computes the SHA3-512 hash of a
+// given string and encodes it with hexadecimal digits.
+var Sha3_512Func = makeStringHashFunction(func() hash.Hash { return sha3.New512() }, hex.EncodeToString)
+
+// X509DecodeFunc constructs a function that decodes the first PEM-encoded
+// X.509 certificate in a given string into an object describing it.
+var X509DecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "cert",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(x509CertificateType),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		rest := []byte(args[0].AsString())
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "no PEM-encoded CERTIFICATE block found")
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid certificate: %s", err)
+			}
+			return x509CertificateVal(cert), nil
+		}
+	},
+})
+
+// x509CertificateType is the type of object returned by X509DecodeFunc.
+var x509CertificateType = cty.Object(map[string]cty.Type{
+	"subject":              cty.String,
+	"issuer":               cty.String,
+	"serial_number":        cty.String,
+	"not_before":           cty.String,
+	"not_after":            cty.String,
+	"dns_names":            cty.List(cty.String),
+	"ip_addresses":         cty.List(cty.String),
+	"email_addresses":      cty.List(cty.String),
+	"uris":                 cty.List(cty.String),
+	"is_ca":                cty.Bool,
+	"public_key_algorithm": cty.String,
+	"signature_algorithm":  cty.String,
+	"sha256_fingerprint":   cty.String,
+})
+
+func x509CertificateVal(cert *x509.Certificate) cty.Value {
+	stringList := func(strs []string) cty.Value {
+		if len(strs) == 0 {
+			return cty.ListValEmpty(cty.String)
+		}
+		vals := make([]cty.Value, len(strs))
+		for i, s := range strs {
+			vals[i] = cty.StringVal(s)
+		}
+		return cty.ListVal(vals)
+	}
+
+	ips := make([]string, len(cert.IPAddresses))
+	for i, ip := range cert.IPAddresses {
+		ips[i] = ip.String()
+	}
+	uris := make([]string, len(cert.URIs))
+	for i, u := range cert.URIs {
+		uris[i] = u.String()
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"subject":              cty.StringVal(cert.Subject.String()),
+		"issuer":               cty.StringVal(cert.Issuer.String()),
+		"serial_number":        cty.StringVal(cert.SerialNumber.String()),
+		"not_before":           cty.StringVal(cert.NotBefore.UTC().Format(time.RFC3339)),
+		"not_after":            cty.StringVal(cert.NotAfter.UTC().Format(time.RFC3339)),
+		"dns_names":            stringList(cert.DNSNames),
+		"ip_addresses":         stringList(ips),
+		"email_addresses":      stringList(cert.EmailAddresses),
+		"uris":                 stringList(uris),
+		"is_ca":                cty.BoolVal(cert.IsCA),
+		"public_key_algorithm": cty.StringVal(cert.PublicKeyAlgorithm.String()),
+		"signature_algorithm":  cty.StringVal(cert.SignatureAlgorithm.String()),
+		"sha256_fingerprint":   cty.StringVal(hex.EncodeToString(fingerprint[:])),
+	})
+}
+
+func makeHmacFunction(hf func() hash.Hash) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "key",
+				Type: cty.String,
+			},
+			{
+				Name: "message",
+				Type: cty.String,
+			},
+		},
+		Type:         function.StaticReturnType(cty.String),
+		RefineResult: refineNotNull,
+		Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+			h := hmac.New(hf, []byte(args[0].AsString()))
+			h.Write([]byte(args[1].AsString()))
+			return cty.StringVal(hex.EncodeToString(h.Sum(nil))), nil
+		},
+	})
+}
+
+// parsePublicKey parses a public key given either as a PEM-encoded
+// "PUBLIC KEY", "RSA PUBLIC KEY" or "CERTIFICATE" block, or in the OpenSSH
+// authorized_keys format.
+func parsePublicKey(str string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(str))
+	if block == nil {
+		sshKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(str))
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: must be PEM-encoded or in OpenSSH authorized_keys format")
+		}
+		cryptoKey, ok := sshKey.(ssh.CryptoPublicKey)
+		if !ok {
+			return nil, fmt.Errorf("invalid public key: unsupported key type %s", sshKey.Type())
+		}
+		return cryptoKey.CryptoPublicKey(), nil
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		return key, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		return key, nil
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("invalid public key: unsupported PEM block type %q", block.Type)
+	}
+}
+
+// publicKeyAlgorithmName returns a name for the algorithm of a public key
+// returned by parsePublicKey, for use in error messages.
+func publicKeyAlgorithmName(key crypto.PublicKey) string {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return "an RSA key"
+	case *ecdsa.PublicKey:
+		return "an ECDSA key"
+	case ed25519.PublicKey:
+		return "an Ed25519 key"
+	default:
+		return fmt.Sprintf("a %T key", key)
+	}
+}
+
 func makeStringHashFunction(hf func() hash.Hash, enc func([]byte) string) function.Function {
 	return function.New(&function.Spec{
 		Params: []function.Parameter{
@@ -319,17 +644,53 @@ func Bcrypt(str cty.Value, cost ...cty.Value) (cty.Value, error) {
 	return BcryptFunc.Call(args)
 }
 
+// Blake2b computes the BLAKE2b-512 hash of a given string and encodes it with
+// hexadecimal digits.
+func Blake2b(str cty.Value) (cty.Value, error) {
+	return Blake2bFunc.Call([]cty.Value{str})
+}
+
+// Ed25519Verify verifies an Ed25519 signature of a given message, returning
+// true only if the signature is valid for the given public key.
+func Ed25519Verify(publicKey, message, signature cty.Value) (cty.Value, error) {
+	return Ed25519VerifyFunc.Call([]cty.Value{publicKey, message, signature})
+}
+
+// HmacSha256 computes the HMAC-SHA256 of a given message using a given key,
+// and encodes it with hexadecimal digits.
+func HmacSha256(key, message cty.Value) (cty.Value, error) {
+	return HmacSha256Func.Call([]cty.Value{key, message})
+}
+
+// HmacSha512 computes the HMAC-SHA512 of a given message using a given key,
+// and encodes it with hexadecimal digits.
+func HmacSha512(key, message cty.Value) (cty.Value, error) {
+	return HmacSha512Func.Call([]cty.Value{key, message})
+}
+
 // Md5 computes the MD5 hash of a given string and encodes it with hexadecimal digits.
 func Md5(str cty.Value) (cty.Value, error) {
 	return Md5Func.Call([]cty.Value{str})
 }
 
+// PemDecode decodes all of the PEM blocks in a given string.
+func PemDecode(str cty.Value) (cty.Value, error) {
+	return PemDecodeFunc.Call([]cty.Value{str})
+}
+
 // RsaDecrypt decrypts an RSA-encrypted ciphertext, returning the corresponding
 // cleartext.
 func RsaDecrypt(ciphertext, privatekey cty.Value) (cty.Value, error) {
 	return RsaDecryptFunc.Call([]cty.Value{ciphertext, privatekey})
 }
 
+// RsaVerify verifies an RSA signature of a given message, using the
+// RSASSA-PKCS1-v1_5 scheme with SHA-256, returning true only if the signature
+// is valid for the given public key.
+func RsaVerify(publicKey, message, signature cty.Value) (cty.Value, error) {
+	return RsaVerifyFunc.Call([]cty.Value{publicKey, message, signature})
+}
+
 // Sha1 computes the SHA1 hash of a given string and encodes it with hexadecimal digits.
 func Sha1(str cty.Value) (cty.Value, error) {
 	return Sha1Func.Call([]cty.Value{str})
@@ -344,3 +705,21 @@ func Sha256(str cty.Value) (cty.Value, error) {
 func Sha512(str cty.Value) (cty.Value, error) {
 	return Sha512Func.Call([]cty.Value{str})
 }
+
+// Sha3_256 computes the SHA3-256 hash of a given string and encodes it with
+// hexadecimal digits.
+func Sha3_256(str cty.Value) (cty.Value, error) {
+	return Sha3_256Func.Call([]cty.Value{str})
+}
+
+// Sha3_512 computes the SHA3-512 hash of a given string and encodes it with
+// hexadecimal digits.
+func Sha3_512(str cty.Value) (cty.Value, error) {
+	return Sha3_512Func.Call([]cty.Value{str})
+}
+
+// X509Decode decodes the first PEM-encoded X.509 certificate in a given
+// string.
+func X509Decode(cert cty.Value) (cty.Value, error) {
+	return X509DecodeFunc.Call([]cty.Value{cert})
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/zclconf/go-cty/cty"
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
 )
 
 func TestUUID(t *testing.T) {
@@ -282,6 +284,184 @@ func TestBcrypt(t *testing.T) {
 	}
 }
 
+func TestBlake2b(t *testing.T) {
+	tests := []struct {
+		String cty.Value
+		Want   cty.Value
+		Err    bool
+	}{
+		{
+			cty.StringVal("abc"),
+			cty.StringVal("ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"),
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("blake2b(%#v)", test.String), func(t *testing.T) {
+			got, err := Blake2b(test.String)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestEd25519Verify(t *testing.T) {
+	tests := []struct {
+		PublicKey cty.Value
+		Message   cty.Value
+		Signature cty.Value
+		Want      cty.Value
+		Err       string
+	}{
+		{ // PEM public key
+			cty.StringVal(Ed25519PublicKey),
+			cty.StringVal("hello"),
+			cty.StringVal(Ed25519Signature),
+			cty.True,
+			"",
+		},
+		{ // OpenSSH public key
+			cty.StringVal(Ed25519PublicKeyOpenSSH),
+			cty.StringVal("hello"),
+			cty.StringVal(Ed25519Signature),
+			cty.True,
+			"",
+		},
+		{ // certificate
+			cty.StringVal(Certificate),
+			cty.StringVal("hello"),
+			cty.StringVal(Ed25519Signature),
+			cty.True,
+			"",
+		},
+		{ // wrong message
+			cty.StringVal(Ed25519PublicKey),
+			cty.StringVal("goodbye"),
+			cty.StringVal(Ed25519Signature),
+			cty.False,
+			"",
+		},
+		{ // wrong key type
+			cty.StringVal(RSAPublicKey),
+			cty.StringVal("hello"),
+			cty.StringVal(Ed25519Signature),
+			cty.NilVal,
+			"public key must be an Ed25519 key, not an RSA key",
+		},
+		{
+			cty.StringVal("not a key"),
+			cty.StringVal("hello"),
+			cty.StringVal(Ed25519Signature),
+			cty.NilVal,
+			"invalid public key: must be PEM-encoded or in OpenSSH authorized_keys format",
+		},
+		{
+			cty.StringVal(Ed25519PublicKey),
+			cty.StringVal("hello"),
+			cty.StringVal("not base64!"),
+			cty.NilVal,
+			"signature must be base64-encoded",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("ed25519verify(%#v, %#v, %#v)", test.PublicKey, test.Message, test.Signature), func(t *testing.T) {
+			got, err := Ed25519Verify(test.PublicKey, test.Message, test.Signature)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				} else if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestHmacSha256(t *testing.T) {
+	tests := []struct {
+		Key     cty.Value
+		Message cty.Value
+		Want    cty.Value
+	}{
+		{ // test case 2 from RFC 4231
+			cty.StringVal("Jefe"),
+			cty.StringVal("what do ya want for nothing?"),
+			cty.StringVal("5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"),
+		},
+		{
+			cty.StringVal("Jefe").Mark(marks.Sensitive),
+			cty.StringVal("what do ya want for nothing?"),
+			cty.StringVal("5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843").Mark(marks.Sensitive),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("hmacsha256(%#v, %#v)", test.Key, test.Message), func(t *testing.T) {
+			got, err := HmacSha256(test.Key, test.Message)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestHmacSha512(t *testing.T) {
+	tests := []struct {
+		Key     cty.Value
+		Message cty.Value
+		Want    cty.Value
+	}{
+		{ // test case 2 from RFC 4231
+			cty.StringVal("Jefe"),
+			cty.StringVal("what do ya want for nothing?"),
+			cty.StringVal("164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737"),
+		},
+		{
+			cty.StringVal("Jefe").Mark(marks.Sensitive),
+			cty.StringVal("what do ya want for nothing?"),
+			cty.StringVal("164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737").Mark(marks.Sensitive),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("hmacsha512(%#v, %#v)", test.Key, test.Message), func(t *testing.T) {
+			got, err := HmacSha512(test.Key, test.Message)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
 func TestMd5(t *testing.T) {
 	tests := []struct {
 		String cty.Value
@@ -370,6 +550,84 @@ func TestFileMD5(t *testing.T) {
 	}
 }
 
+func TestPemDecode(t *testing.T) {
+	tests := []struct {
+		String cty.Value
+		Want   cty.Value
+		Err    string
+	}{
+		{
+			cty.StringVal(`leading text
+-----BEGIN MESSAGE-----
+Proc-Type: 4,ENCRYPTED
+
+aGVsbG8=
+-----END MESSAGE-----
+-----BEGIN DATA-----
+d29ybGQ=
+-----END DATA-----
+`),
+			cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"type": cty.StringVal("MESSAGE"),
+					"headers": cty.MapVal(map[string]cty.Value{
+						"Proc-Type": cty.StringVal("4,ENCRYPTED"),
+					}),
+					"bytes": cty.StringVal("aGVsbG8="),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"type":    cty.StringVal("DATA"),
+					"headers": cty.MapValEmpty(cty.String),
+					"bytes":   cty.StringVal("d29ybGQ="),
+				}),
+			}),
+			"",
+		},
+		{
+			cty.StringVal("-----BEGIN DATA-----\nd29ybGQ=\n-----END DATA-----\n").Mark(marks.Sensitive),
+			cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"type":    cty.StringVal("DATA"),
+					"headers": cty.MapValEmpty(cty.String),
+					"bytes":   cty.StringVal("d29ybGQ="),
+				}),
+			}).Mark(marks.Sensitive),
+			"",
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.UnknownVal(cty.List(pemBlockType)).RefineNotNull(),
+			"",
+		},
+		{
+			cty.StringVal("not PEM"),
+			cty.NilVal,
+			"no PEM blocks found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("pemdecode(%#v)", test.String), func(t *testing.T) {
+			got, err := PemDecode(test.String)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				} else if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
 func TestRsaDecrypt(t *testing.T) {
 	tests := []struct {
 		Ciphertext cty.Value
@@ -449,6 +707,73 @@ func TestRsaDecrypt(t *testing.T) {
 	}
 }
 
+func TestRsaVerify(t *testing.T) {
+	tests := []struct {
+		PublicKey cty.Value
+		Message   cty.Value
+		Signature cty.Value
+		Want      cty.Value
+		Err       string
+	}{
+		{ // PEM public key
+			cty.StringVal(RSAPublicKey),
+			cty.StringVal("hello"),
+			cty.StringVal(RSASignature),
+			cty.True,
+			"",
+		},
+		{ // wrong message
+			cty.StringVal(RSAPublicKey),
+			cty.StringVal("goodbye"),
+			cty.StringVal(RSASignature),
+			cty.False,
+			"",
+		},
+		{ // wrong key type
+			cty.StringVal(Ed25519PublicKey),
+			cty.StringVal("hello"),
+			cty.StringVal(RSASignature),
+			cty.NilVal,
+			"public key must be an RSA key, not an Ed25519 key",
+		},
+		{
+			cty.StringVal("not a key"),
+			cty.StringVal("hello"),
+			cty.StringVal(RSASignature),
+			cty.NilVal,
+			"invalid public key: must be PEM-encoded or in OpenSSH authorized_keys format",
+		},
+		{
+			cty.StringVal(RSAPublicKey),
+			cty.StringVal("hello"),
+			cty.StringVal("not base64!"),
+			cty.NilVal,
+			"signature must be base64-encoded",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("rsaverify(%#v, %#v, %#v)", test.PublicKey, test.Message, test.Signature), func(t *testing.T) {
+			got, err := RsaVerify(test.PublicKey, test.Message, test.Signature)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				} else if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
 func TestSha1(t *testing.T) {
 	tests := []struct {
 		String cty.Value
@@ -683,6 +1008,137 @@ func TestFileSHA512(t *testing.T) {
 	}
 }
 
+func TestSha3_256(t *testing.T) {
+	tests := []struct {
+		String cty.Value
+		Want   cty.Value
+		Err    bool
+	}{
+		{
+			cty.StringVal("abc"),
+			cty.StringVal("3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532"),
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("sha3_256(%#v)", test.String), func(t *testing.T) {
+			got, err := Sha3_256(test.String)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestSha3_512(t *testing.T) {
+	tests := []struct {
+		String cty.Value
+		Want   cty.Value
+		Err    bool
+	}{
+		{
+			cty.StringVal("abc"),
+			cty.StringVal("b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0"),
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("sha3_512(%#v)", test.String), func(t *testing.T) {
+			got, err := Sha3_512(test.String)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestX509Decode(t *testing.T) {
+	tests := []struct {
+		Cert cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			// The certificate may be preceded by other PEM blocks.
+			cty.StringVal("-----BEGIN DATA-----\nd29ybGQ=\n-----END DATA-----\n" + Certificate),
+			cty.ObjectVal(map[string]cty.Value{
+				"subject":              cty.StringVal("CN=example.com,O=Example Org"),
+				"issuer":               cty.StringVal("CN=example.com,O=Example Org"),
+				"serial_number":        cty.StringVal("4660"),
+				"not_before":           cty.StringVal("2025-01-01T00:00:00Z"),
+				"not_after":            cty.StringVal("2035-01-01T00:00:00Z"),
+				"dns_names":            cty.ListVal([]cty.Value{cty.StringVal("example.com"), cty.StringVal("www.example.com")}),
+				"ip_addresses":         cty.ListVal([]cty.Value{cty.StringVal("192.0.2.1")}),
+				"email_addresses":      cty.ListVal([]cty.Value{cty.StringVal("admin@example.com")}),
+				"uris":                 cty.ListValEmpty(cty.String),
+				"is_ca":                cty.False,
+				"public_key_algorithm": cty.StringVal("Ed25519"),
+				"signature_algorithm":  cty.StringVal("Ed25519"),
+				"sha256_fingerprint":   cty.StringVal("2ed4cc416c5c8bfba16710bc66f0ffa2e81bbab1852c9c556ab9315f55a918a9"),
+			}),
+			"",
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.UnknownVal(x509CertificateType).RefineNotNull(),
+			"",
+		},
+		{
+			cty.StringVal(PrivateKey),
+			cty.NilVal,
+			"no PEM-encoded CERTIFICATE block found",
+		},
+		{
+			cty.StringVal("-----BEGIN CERTIFICATE-----\nd29ybGQ=\n-----END CERTIFICATE-----\n"),
+			cty.NilVal,
+			"invalid certificate: x509: malformed certificate",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("x509decode(%#v)", test.Cert), func(t *testing.T) {
+			got, err := X509Decode(test.Cert)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				} else if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
 const (
 	CipherBase64 = "eczGaDhXDbOFRZGhjx2etVzWbRqWDlmq0bvNt284JHVbwCgObiuyX9uV0LSAMY707IEgMkExJqXmsB4OWKxvB7epRB9G/3+F+pcrQpODlDuL9oDUAsa65zEpYF0Wbn7Oh7nrMQncyUPpyr9WUlALl0gRWytOA23S+y5joa4M34KFpawFgoqTu/2EEH4Xl1zo+0fy73fEto+nfkUY+meuyGZ1nUx/+DljP7ZqxHBFSlLODmtuTMdswUbHbXbWneW51D7Jm7xB8nSdiA2JQNK5+Sg5x8aNfgvFTt/m2w2+qpsyFa5Wjeu6fZmXSl840CA07aXbk9vN4I81WmJyblD/ZA=="
 	PrivateKey   = `
@@ -800,4 +1256,35 @@ RXT7GwNQHIY8eDjDnsHxzrxd+raOxOZeKcMHj3XyjCX3NHfTscnsBPAGYpY/Wxzh
 T8UYnFu6RzkixElTf2rseEav7rkdKkI3LAeIZy7B0HulKKsmqVQ7
 -----END RSA PRIVATE KEY-----
 `
+	Certificate = `
+-----BEGIN CERTIFICATE-----
+MIIBWjCCAQygAwIBAgICEjQwBQYDK2VwMCwxFDASBgNVBAoTC0V4YW1wbGUgT3Jn
+MRQwEgYDVQQDEwtleGFtcGxlLmNvbTAeFw0yNTAxMDEwMDAwMDBaFw0zNTAxMDEw
+MDAwMDBaMCwxFDASBgNVBAoTC0V4YW1wbGUgT3JnMRQwEgYDVQQDEwtleGFtcGxl
+LmNvbTAqMAUGAytlcAMhADtqJ7zOtqQtYqOo0CpvDXNlMhV3HeJDpjrASKGLWdop
+o1IwUDAMBgNVHRMBAf8EAjAAMEAGA1UdEQQ5MDeCC2V4YW1wbGUuY29tgg93d3cu
+ZXhhbXBsZS5jb22BEWFkbWluQGV4YW1wbGUuY29thwTAAAIBMAUGAytlcANBAGO2
+1AWGzWVWJtQEJulntbkDiC+Nf4wHVqzDvxCK8dGh5kVwheBEkLEoHk7kmOyjWt3o
+0nSXPEC86JgZJr+QUgI=
+-----END CERTIFICATE-----
+`
+	Ed25519PublicKey = `
+-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEAO2onvM62pC1io6jQKm8Nc2UyFXcd4kOmOsBIoYtZ2ik=
+-----END PUBLIC KEY-----
+`
+	Ed25519PublicKeyOpenSSH = `ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDtqJ7zOtqQtYqOo0CpvDXNlMhV3HeJDpjrASKGLWdop`
+	Ed25519Signature        = `4lyHI9A5/o9F1snWqJF/qRvHVJE81Zb9NYpJOiGjy1kKZTe6vH3wQAq2GgVYnJw2tloUOHjLA0HU6eSEGcQ3DQ==`
+	RSAPublicKey            = `
+-----BEGIN PUBLIC KEY-----
+MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAgUElV5mwqkloIrM8ZNZ7
+2gSCcnSJt7+/Usa5G+D15YQUAdf9c1zEekTfHgDP+04nw/uFNFaE5v1RbHaPxhZY
+Vg5ZErNCa/hzn+x10xzcepeS3KPVXcxae4MR0BEegvqZqJzN9loXsNL/c3H/B+2G
+le3hTxjlWFb3F5qLgR+4Mf4ruhER1v6eHQa/nchi03MBpT4UeJ7MrL92hTJYLdpS
+yCqmr8yjxkKJDVC2uRrr+sTSxfh7r6v24u/vp/QTmBIAlNPgadVAZw17iNNb7vjV
+7Gwl/5gHXonCUKURaV++dBNLrHIZpqcAM8wHRph8mD1EfL9hsz77pHewxolBATV+
+7QIDAQAB
+-----END PUBLIC KEY-----
+`
+	RSASignature = `P4p2k0P5QVQ7bypz9dpNdbbhmme7ChLD5bmUCpy07o67K5lzIv++UCbYUOym97/K24t9PHk+ixgp/OZ89fbuZ6ECueQ1uHEsv3W3l8b/nnyOdR/V9sIF88EniekwKE9igC/7UOCWO2HxkrI8ySFQl/a6n/oqsIwl7GytyeYudGgEt75aTi6LsBhlyNWiEVao1/sKB8hETUr1ov1ILBKjQeTbyTzmHguWuR20tL9jqu6ZO6MoGioGmmv3it4onMrdJbU6KKKQxrvdLd4VoCSZrD7QrdJWHeRDXT9CXrmPX2+pq5bB1ycj6lH0zi2c5ncFlLat5PXUbYLRImeQ6IvP/A==`
 )
@@ -0,0 +1,482 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// TOMLDecodeFunc constructs a function that parses a string containing a
+// TOML document and returns an object representing its top-level table.
+var TOMLDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:        "str",
+			Type:        cty.String,
+			AllowMarked: true,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		str, strMarks := args[0].Unmark()
+		if !str.IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		val, err := tomlDecode(str.AsString(), strMarks)
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		return val.Type(), nil
+	},
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		str, strMarks := args[0].Unmark()
+		val, err := tomlDecode(str.AsString(), strMarks)
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(0, err)
+		}
+		return val.WithMarks(strMarks), nil
+	},
+})
+
+// TOMLEncodeFunc constructs a function that encodes an object or map as a
+// TOML document.
+var TOMLEncodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "val",
+			Type:             cty.DynamicPseudoType,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		val := args[0]
+		ty := val.Type()
+		if !ty.IsObjectType() && !ty.IsMapType() && ty != cty.DynamicPseudoType {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "a TOML document must be an object or a map, not %s", ty.FriendlyName())
+		}
+		if !val.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		doc, err := tomlEncodeValue(val, nil)
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(0, err)
+		}
+		var buf bytes.Buffer
+		enc := toml.NewEncoder(&buf)
+		enc.Indent = ""
+		if err := enc.Encode(doc); err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "failed to encode TOML: %s", err)
+		}
+		return cty.StringVal(buf.String()), nil
+	},
+})
+
+// XMLDecodeFunc constructs a function that parses a string containing an
+// XML document and returns an object representing its root element.
+//
+// Each element is represented as an object with the following attributes:
+//   - name: the element name, including its namespace prefix if any.
+//   - attributes: a map of the element's attributes, keyed by name including
+//     any namespace prefix. Namespace declarations are included as-is.
+//   - children: a tuple of the child elements, in document order.
+//   - text: the character data directly inside the element, including
+//     CDATA sections, with leading and trailing whitespace removed.
+//
+// Comments, processing instructions and directives are ignored.
+var XMLDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:        "str",
+			Type:        cty.String,
+			AllowMarked: true,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		str, strMarks := args[0].Unmark()
+		if !str.IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		val, err := xmlDecode(str.AsString(), strMarks)
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		return val.Type(), nil
+	},
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		str, strMarks := args[0].Unmark()
+		val, err := xmlDecode(str.AsString(), strMarks)
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(0, err)
+		}
+		return val.WithMarks(strMarks), nil
+	},
+})
+
+// HCLDecodeFunc constructs a function that parses a string containing
+// HCL native syntax attribute definitions, like a .tfvars file, and returns
+// an object with an attribute for each definition.
+//
+// The attribute values must be constant expressions: they cannot refer to
+// variables or call functions.
+var HCLDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:        "str",
+			Type:        cty.String,
+			AllowMarked: true,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		str, strMarks := args[0].Unmark()
+		if !str.IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		val, err := hclDecode(str.AsString(), strMarks)
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		return val.Type(), nil
+	},
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		str, strMarks := args[0].Unmark()
+		val, err := hclDecode(str.AsString(), strMarks)
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(0, err)
+		}
+		return val.WithMarks(strMarks), nil
+	},
+})
+
+// decodeError returns an error describing why the given source string
+// could not be decoded as the given format. The underlying error often
+// quotes parts of the source, so it's omitted when the source is sensitive
+// or ephemeral.
+func decodeError(format string, err error, strMarks cty.ValueMarks) error {
+	v := cty.DynamicVal.WithMarks(strMarks)
+	if marks.Has(v, marks.Sensitive) || marks.Has(v, marks.Ephemeral) {
+		return fmt.Errorf("invalid %s in %s", format, redactIfSensitiveOrEphemeral(nil, strMarks))
+	}
+	return fmt.Errorf("invalid %s: %w", format, err)
+}
+
+func tomlDecode(src string, strMarks cty.ValueMarks) (cty.Value, error) {
+	var raw map[string]interface{}
+	if _, err := toml.Decode(src, &raw); err != nil {
+		return cty.NilVal, decodeError("TOML", err, strMarks)
+	}
+	val, err := tomlValueToCty(raw)
+	if err != nil {
+		return cty.NilVal, decodeError("TOML", err, strMarks)
+	}
+	return val, nil
+}
+
+func tomlValueToCty(raw interface{}) (cty.Value, error) {
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		if len(raw) == 0 {
+			return cty.EmptyObjectVal, nil
+		}
+		attrs := make(map[string]cty.Value, len(raw))
+		for k, v := range raw {
+			av, err := tomlValueToCty(v)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attrs[k] = av
+		}
+		return cty.ObjectVal(attrs), nil
+	case []map[string]interface{}:
+		elems := make([]interface{}, len(raw))
+		for i, v := range raw {
+			elems[i] = v
+		}
+		return tomlValueToCty(elems)
+	case []interface{}:
+		if len(raw) == 0 {
+			return cty.EmptyTupleVal, nil
+		}
+		elems := make([]cty.Value, len(raw))
+		for i, v := range raw {
+			ev, err := tomlValueToCty(v)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elems[i] = ev
+		}
+		return cty.TupleVal(elems), nil
+	case string:
+		return cty.StringVal(raw), nil
+	case bool:
+		return cty.BoolVal(raw), nil
+	case int64:
+		return cty.NumberIntVal(raw), nil
+	case float64:
+		if math.IsNaN(raw) || math.IsInf(raw, 0) {
+			return cty.NilVal, fmt.Errorf("the number %v cannot be represented in OpenTofu", raw)
+		}
+		return cty.NumberFloatVal(raw), nil
+	case time.Time:
+		// TOML has separate types for dates and times with and without an
+		// offset, which the decoder distinguishes by the location's name.
+		layout := time.RFC3339Nano
+		switch raw.Location().String() {
+		case "datetime-local":
+			layout = "2006-01-02T15:04:05.999999999"
+		case "date-local":
+			layout = "2006-01-02"
+		case "time-local":
+			layout = "15:04:05.999999999"
+		}
+		return cty.StringVal(raw.Format(layout)), nil
+	default:
+		return cty.NilVal, fmt.Errorf("unsupported TOML value of type %T", raw)
+	}
+}
+
+// tomlEncodeValue converts the given wholly-known value into the Go
+// representation expected by the TOML encoder. Null object attributes and
+// map elements are omitted, since TOML has no representation of null.
+func tomlEncodeValue(val cty.Value, path cty.Path) (interface{}, error) {
+	if val.IsNull() {
+		if len(path) == 0 {
+			return nil, errors.New("a TOML document cannot be null")
+		}
+		return nil, fmt.Errorf("null value at %s cannot be represented in TOML", tfdiags.FormatCtyPath(path))
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return val.AsString(), nil
+	case ty == cty.Bool:
+		return val.True(), nil
+	case ty == cty.Number:
+		bf := val.AsBigFloat()
+		if i, acc := bf.Int64(); acc == big.Exact {
+			return i, nil
+		}
+		f, _ := bf.Float64()
+		return f, nil
+	case ty.IsObjectType() || ty.IsMapType():
+		ret := make(map[string]interface{}, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			if v.IsNull() {
+				continue
+			}
+			elemPath := path.Index(k)
+			if ty.IsObjectType() {
+				elemPath = path.GetAttr(k.AsString())
+			}
+			ev, err := tomlEncodeValue(v, elemPath)
+			if err != nil {
+				return nil, err
+			}
+			ret[k.AsString()] = ev
+		}
+		return ret, nil
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		ret := make([]interface{}, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			ev, err := tomlEncodeValue(v, path.Index(k))
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, ev)
+		}
+		return ret, nil
+	default:
+		return nil, fmt.Errorf("%s value at %s cannot be represented in TOML", ty.FriendlyName(), tfdiags.FormatCtyPath(path))
+	}
+}
+
+// xmlElement is an XML element under construction by xmlDecode.
+type xmlElement struct {
+	name     string
+	attrs    map[string]cty.Value
+	children []cty.Value
+	text     strings.Builder
+}
+
+func (e *xmlElement) value() cty.Value {
+	attrs := cty.MapValEmpty(cty.String)
+	if len(e.attrs) > 0 {
+		attrs = cty.MapVal(e.attrs)
+	}
+	children := cty.EmptyTupleVal
+	if len(e.children) > 0 {
+		children = cty.TupleVal(e.children)
+	}
+	return cty.ObjectVal(map[string]cty.Value{
+		"name":       cty.StringVal(e.name),
+		"attributes": attrs,
+		"children":   children,
+		"text":       cty.StringVal(strings.TrimSpace(e.text.String())),
+	})
+}
+
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+func xmlDecode(src string, strMarks cty.ValueMarks) (cty.Value, error) {
+	val, err := xmlDecodeDocument(src)
+	if err != nil {
+		return cty.NilVal, decodeError("XML", err, strMarks)
+	}
+	return val, nil
+}
+
+func xmlDecodeDocument(src string) (cty.Value, error) {
+	// We use raw tokens so that namespace prefixes are kept as written,
+	// rather than being replaced with the namespace URL, which means we must
+	// check for mismatched end elements ourselves.
+	dec := xml.NewDecoder(strings.NewReader(src))
+	var stack []*xmlElement
+	var root cty.Value
+	haveRoot := false
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 && haveRoot {
+				return cty.NilVal, fmt.Errorf("unexpected element <%s> after the root element", xmlName(tok.Name))
+			}
+			elem := &xmlElement{
+				name:  xmlName(tok.Name),
+				attrs: make(map[string]cty.Value, len(tok.Attr)),
+			}
+			for _, attr := range tok.Attr {
+				elem.attrs[xmlName(attr.Name)] = cty.StringVal(attr.Value)
+			}
+			stack = append(stack, elem)
+		case xml.EndElement:
+			name := xmlName(tok.Name)
+			if len(stack) == 0 {
+				return cty.NilVal, fmt.Errorf("unexpected end element </%s>", name)
+			}
+			elem := stack[len(stack)-1]
+			if elem.name != name {
+				return cty.NilVal, fmt.Errorf("element <%s> closed by </%s>", elem.name, name)
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				root = elem.value()
+				haveRoot = true
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, elem.value())
+			}
+		case xml.CharData:
+			if len(stack) == 0 {
+				if len(bytes.TrimSpace(tok)) != 0 {
+					return cty.NilVal, errors.New("unexpected text outside of the root element")
+				}
+				continue
+			}
+			stack[len(stack)-1].text.Write(tok)
+		}
+	}
+	if len(stack) != 0 {
+		return cty.NilVal, fmt.Errorf("element <%s> is not closed", stack[len(stack)-1].name)
+	}
+	if !haveRoot {
+		return cty.NilVal, errors.New("the document has no root element")
+	}
+	return root, nil
+}
+
+func hclDecode(src string, strMarks cty.ValueMarks) (cty.Value, error) {
+	val, diags := hclDecodeAttributes(src)
+	if diags.HasErrors() {
+		return cty.NilVal, decodeError("HCL", diags, strMarks)
+	}
+	return val, nil
+}
+
+func hclDecodeAttributes(src string) (cty.Value, hcl.Diagnostics) {
+	f, diags := hclsyntax.ParseConfig([]byte(src), "<hcldecode>", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	attrs, moreDiags := f.Body.JustAttributes()
+	diags = append(diags, moreDiags...)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	if len(attrs) == 0 {
+		return cty.EmptyObjectVal, diags
+	}
+
+	vals := make(map[string]cty.Value, len(attrs))
+	for name, attr := range attrs {
+		// With no evaluation context, any reference to a variable or call
+		// to a function is reported as an error.
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		vals[name] = val
+	}
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	return cty.ObjectVal(vals), diags
+}
+
+// TOMLDecode parses the given string as a TOML document.
+func TOMLDecode(str cty.Value) (cty.Value, error) {
+	return TOMLDecodeFunc.Call([]cty.Value{str})
+}
+
+// TOMLEncode encodes the given object or map as a TOML document.
+func TOMLEncode(val cty.Value) (cty.Value, error) {
+	return TOMLEncodeFunc.Call([]cty.Value{val})
+}
+
+// XMLDecode parses the given string as an XML document.
+func XMLDecode(str cty.Value) (cty.Value, error) {
+	return XMLDecodeFunc.Call([]cty.Value{str})
+}
+
+// HCLDecode parses the given string as HCL native syntax attribute
+// definitions.
+func HCLDecode(str cty.Value) (cty.Value, error) {
+	return HCLDecodeFunc.Call([]cty.Value{str})
+}
@@ -0,0 +1,386 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestTOMLDecode(t *testing.T) {
+	tests := []struct {
+		String cty.Value
+		Want   cty.Value
+		Err    string
+	}{
+		{
+			cty.StringVal(`
+title = "example"
+enabled = true
+ratio = 0.5
+ports = [80, 443]
+created = 1979-05-27T07:32:00Z
+day = 1979-05-27
+
+[owner]
+name = "Tom"
+
+[[servers]]
+host = "alpha"
+
+[[servers]]
+host = "beta"
+port = 8080
+`),
+			cty.ObjectVal(map[string]cty.Value{
+				"title":   cty.StringVal("example"),
+				"enabled": cty.True,
+				"ratio":   cty.NumberFloatVal(0.5),
+				"ports":   cty.TupleVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(443)}),
+				"created": cty.StringVal("1979-05-27T07:32:00Z"),
+				"day":     cty.StringVal("1979-05-27"),
+				"owner": cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("Tom"),
+				}),
+				"servers": cty.TupleVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"host": cty.StringVal("alpha"),
+					}),
+					cty.ObjectVal(map[string]cty.Value{
+						"host": cty.StringVal("beta"),
+						"port": cty.NumberIntVal(8080),
+					}),
+				}),
+			}),
+			``,
+		},
+		{
+			cty.StringVal(""),
+			cty.EmptyObjectVal,
+			``,
+		},
+		{
+			cty.StringVal("a = 1").Mark(marks.Sensitive),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.NumberIntVal(1),
+			}).Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			cty.StringVal("a = inf"),
+			cty.NilVal,
+			`invalid TOML: the number +Inf cannot be represented in OpenTofu`,
+		},
+		{
+			cty.StringVal("a = "),
+			cty.NilVal,
+			`invalid TOML: `,
+		},
+		{
+			cty.StringVal("password = ").Mark(marks.Sensitive),
+			cty.NilVal,
+			`invalid TOML in (sensitive value)`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("tomldecode(%#v)", test.String), func(t *testing.T) {
+			got, err := TOMLDecode(test.String)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.HasPrefix(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestTOMLEncode(t *testing.T) {
+	tests := []struct {
+		Val  cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"title": cty.StringVal("example"),
+				"ratio": cty.NumberFloatVal(0.5),
+				"count": cty.NumberIntVal(3),
+				"tags":  cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+				"unset": cty.NullVal(cty.String),
+				"owner": cty.MapVal(map[string]cty.Value{
+					"name": cty.StringVal("Tom"),
+				}),
+			}),
+			cty.StringVal(`count = 3
+ratio = 0.5
+tags = ["a", "b"]
+title = "example"
+
+[owner]
+name = "Tom"
+`),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("b").Mark(marks.Sensitive),
+			}),
+			cty.StringVal("a = \"b\"\n").Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.UnknownVal(cty.String),
+			}),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			cty.NilVal,
+			`a TOML document must be an object or a map, not list of string`,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ListVal([]cty.Value{cty.NullVal(cty.String)}),
+			}),
+			cty.NilVal,
+			`null value at .a[0] cannot be represented in TOML`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("tomlencode(%#v)", test.Val), func(t *testing.T) {
+			got, err := TOMLEncode(test.Val)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestXMLDecode(t *testing.T) {
+	xmlElem := func(name string, attrs map[string]string, text string, children ...cty.Value) cty.Value {
+		attrVals := cty.MapValEmpty(cty.String)
+		if len(attrs) > 0 {
+			m := make(map[string]cty.Value, len(attrs))
+			for k, v := range attrs {
+				m[k] = cty.StringVal(v)
+			}
+			attrVals = cty.MapVal(m)
+		}
+		childVals := cty.EmptyTupleVal
+		if len(children) > 0 {
+			childVals = cty.TupleVal(children)
+		}
+		return cty.ObjectVal(map[string]cty.Value{
+			"name":       cty.StringVal(name),
+			"attributes": attrVals,
+			"children":   childVals,
+			"text":       cty.StringVal(text),
+		})
+	}
+
+	tests := []struct {
+		String cty.Value
+		Want   cty.Value
+		Err    string
+	}{
+		{
+			cty.StringVal(`<?xml version="1.0" encoding="UTF-8"?>
+<!-- a manifest -->
+<manifest xmlns:app="urn:example" version="2">
+  <app:name>web</app:name>
+  <item id="1"/>
+  <item id="2"><![CDATA[<raw>]]></item>
+</manifest>
+`),
+			xmlElem("manifest", map[string]string{"xmlns:app": "urn:example", "version": "2"}, "",
+				xmlElem("app:name", nil, "web"),
+				xmlElem("item", map[string]string{"id": "1"}, ""),
+				xmlElem("item", map[string]string{"id": "2"}, "<raw>"),
+			),
+			``,
+		},
+		{
+			cty.StringVal(`<a>secret</a>`).Mark(marks.Sensitive),
+			xmlElem("a", nil, "secret").Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			cty.StringVal(`<a><b></a>`),
+			cty.NilVal,
+			`invalid XML: element <b> closed by </a>`,
+		},
+		{
+			cty.StringVal(`<a>`),
+			cty.NilVal,
+			`invalid XML: element <a> is not closed`,
+		},
+		{
+			cty.StringVal(`<a/><b/>`),
+			cty.NilVal,
+			`invalid XML: unexpected element <b> after the root element`,
+		},
+		{
+			cty.StringVal(`text`),
+			cty.NilVal,
+			`invalid XML: unexpected text outside of the root element`,
+		},
+		{
+			cty.StringVal(``),
+			cty.NilVal,
+			`invalid XML: the document has no root element`,
+		},
+		{
+			cty.StringVal(`<a>`).Mark(marks.Sensitive),
+			cty.NilVal,
+			`invalid XML in (sensitive value)`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("xmldecode(%#v)", test.String), func(t *testing.T) {
+			got, err := XMLDecode(test.String)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestHCLDecode(t *testing.T) {
+	tests := []struct {
+		String cty.Value
+		Want   cty.Value
+		Err    string
+	}{
+		{
+			cty.StringVal(`
+name     = "web"
+replicas = 3
+tags     = { env = "prod" }
+zones    = ["a", "b"]
+`),
+			cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("web"),
+				"replicas": cty.NumberIntVal(3),
+				"tags": cty.ObjectVal(map[string]cty.Value{
+					"env": cty.StringVal("prod"),
+				}),
+				"zones": cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			}),
+			``,
+		},
+		{
+			cty.StringVal(""),
+			cty.EmptyObjectVal,
+			``,
+		},
+		{
+			cty.StringVal(`a = "b"`).Mark(marks.Sensitive),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("b"),
+			}).Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			cty.StringVal(`a = var.b`),
+			cty.NilVal,
+			`invalid HCL: <hcldecode>:1,5-8: Variables not allowed;`,
+		},
+		{
+			cty.StringVal("block {\n}\n"),
+			cty.NilVal,
+			`invalid HCL: <hcldecode>:1,1-6: Unexpected "block" block;`,
+		},
+		{
+			cty.StringVal(`password = var.b`).Mark(marks.Sensitive),
+			cty.NilVal,
+			`invalid HCL in (sensitive value)`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("hcldecode(%#v)", test.String), func(t *testing.T) {
+			got, err := HCLDecode(test.String)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.HasPrefix(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
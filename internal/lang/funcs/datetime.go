@@ -7,6 +7,10 @@ package funcs
 
 import (
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/zclconf/go-cty/cty"
@@ -104,6 +108,111 @@ var TimeCmpFunc = function.New(&function.Spec{
 	},
 })
 
+// TimeZoneFunc constructs a function that converts a timestamp to the local
+// time of a given time zone.
+var TimeZoneFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "timestamp",
+			Type: cty.String,
+		},
+		{
+			Name: "zone",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		ts, err := parseTimestamp(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgError(0, err)
+		}
+		loc, err := loadLocation(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgError(1, err)
+		}
+
+		return cty.StringVal(ts.In(loc).Format(time.RFC3339)), nil
+	},
+})
+
+// ParseTimeFunc constructs a function that parses a timestamp written using
+// the same format syntax as the formatdate function, returning it in RFC 3339
+// syntax.
+var ParseTimeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "format",
+			Type: cty.String,
+		},
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		ts, err := parseTimeFormat(args[0].AsString(), args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+
+		return cty.StringVal(ts.Format(time.RFC3339)), nil
+	},
+})
+
+// TimeDiffFunc constructs a function that returns the duration between two
+// timestamps.
+var TimeDiffFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "timestamp_a",
+			Type: cty.String,
+		},
+		{
+			Name: "timestamp_b",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		tsA, err := parseTimestamp(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgError(0, err)
+		}
+		tsB, err := parseTimestamp(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgError(1, err)
+		}
+
+		return cty.StringVal(tsA.Sub(tsB).String()), nil
+	},
+})
+
+// DurationFunc constructs a function that converts an ISO 8601 duration,
+// like "P30D", into the duration syntax accepted by the timeadd function.
+var DurationFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		d, err := parseISO8601Duration(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgError(0, err)
+		}
+
+		return cty.StringVal(d.String()), nil
+	},
+})
+
 // Timestamp returns a string representation of the current date and time.
 //
 // In the OpenTofu language, timestamps are conventionally represented as
@@ -147,6 +256,35 @@ func TimeCmp(timestampA, timestampB cty.Value) (cty.Value, error) {
 	return TimeCmpFunc.Call([]cty.Value{timestampA, timestampB})
 }
 
+// TimeZone converts a timestamp to the local time of the time zone with the
+// given IANA name, like "Europe/Berlin".
+//
+// The result is a string in RFC 3339 format representing the same instant as
+// the given timestamp, but using the UTC offset that was in effect in the
+// given time zone at that instant.
+func TimeZone(timestamp, zone cty.Value) (cty.Value, error) {
+	return TimeZoneFunc.Call([]cty.Value{timestamp, zone})
+}
+
+// ParseTime parses a timestamp written using the same format syntax as the
+// formatdate function, returning it in RFC 3339 format.
+func ParseTime(format, str cty.Value) (cty.Value, error) {
+	return ParseTimeFunc.Call([]cty.Value{format, str})
+}
+
+// TimeDiff returns the duration from timestampB to timestampA, using the same
+// duration syntax accepted by TimeAdd. The result is negative if timestampA
+// is earlier than timestampB.
+func TimeDiff(timestampA, timestampB cty.Value) (cty.Value, error) {
+	return TimeDiffFunc.Call([]cty.Value{timestampA, timestampB})
+}
+
+// Duration converts an ISO 8601 duration, like "P30D" or "PT1H30M", into the
+// duration syntax accepted by TimeAdd.
+func Duration(str cty.Value) (cty.Value, error) {
+	return DurationFunc.Call([]cty.Value{str})
+}
+
 func parseTimestamp(ts string) (time.Time, error) {
 	t, err := time.Parse(time.RFC3339, ts)
 	if err != nil {
@@ -205,3 +343,268 @@ func parseTimestamp(ts string) (time.Time, error) {
 	}
 	return t, nil
 }
+
+// parseTimeFormat parses a timestamp written using the format syntax of the
+// formatdate function. The format must include at least the year, month and
+// day. If it doesn't include a UTC offset then the timestamp is assumed to be
+// in UTC.
+func parseTimeFormat(format, str string) (time.Time, error) {
+	const esc = '\''
+
+	year, month, day := -1, -1, -1
+	hour, hour12, minute, second := 0, -1, 0, 0
+	pm := -1
+	weekday := -1
+	loc := time.UTC
+
+	rest := str
+	for len(format) > 0 {
+		c := format[0]
+		switch {
+		case c == esc:
+			lit, n, err := dateFormatLiteral(format)
+			if err != nil {
+				return time.Time{}, function.NewArgError(0, err)
+			}
+			format = format[n:]
+			if !strings.HasPrefix(rest, lit) {
+				return time.Time{}, function.NewArgErrorf(1, "expected %q, but found %q", lit, rest)
+			}
+			rest = rest[len(lit):]
+
+		case strings.IndexByte("YMDEhHAamsZ", c) >= 0:
+			n := 1
+			for n < len(format) && format[n] == c {
+				n++
+			}
+			verb := format[:n]
+			format = format[n:]
+
+			var err error
+			switch {
+			case verb == "YYYY":
+				year, rest, err = parseDateNumber(rest, 4, 4, "year")
+			case verb == "YY":
+				year, rest, err = parseDateNumber(rest, 2, 2, "year")
+				// Two-digit years are in the range 1969 to 2068, as in
+				// most other date parsers.
+				if year >= 69 {
+					year += 1900
+				} else {
+					year += 2000
+				}
+			case verb == "M" || verb == "MM":
+				month, rest, err = parseDateNumber(rest, n, 2, "month")
+			case verb == "MMM" || verb == "MMMM":
+				month, rest, err = parseDateName(rest, n == 3, 12, func(i int) string { return time.Month(i + 1).String() }, "month")
+				month++
+			case verb == "D" || verb == "DD":
+				day, rest, err = parseDateNumber(rest, n, 2, "day of month")
+			case verb == "EEE" || verb == "EEEE":
+				weekday, rest, err = parseDateName(rest, n == 3, 7, func(i int) string { return time.Weekday(i).String() }, "day of week")
+			case verb == "h" || verb == "hh":
+				hour, rest, err = parseDateNumber(rest, n, 2, "hour")
+				if err == nil && hour > 23 {
+					err = fmt.Errorf("hour %d is out of range", hour)
+				}
+			case verb == "H" || verb == "HH":
+				hour12, rest, err = parseDateNumber(rest, n, 2, "hour")
+				if err == nil && (hour12 < 1 || hour12 > 12) {
+					err = fmt.Errorf("12-hour %d is out of range", hour12)
+				}
+			case verb == "AA" || verb == "aa":
+				pm, rest, err = parseDateName(rest, false, 2, func(i int) string { return [...]string{"AM", "PM"}[i] }, "AM/PM marker")
+			case verb == "m" || verb == "mm":
+				minute, rest, err = parseDateNumber(rest, n, 2, "minute")
+				if err == nil && minute > 59 {
+					err = fmt.Errorf("minute %d is out of range", minute)
+				}
+			case verb == "s" || verb == "ss":
+				second, rest, err = parseDateNumber(rest, n, 2, "second")
+				if err == nil && second > 59 {
+					err = fmt.Errorf("second %d is out of range", second)
+				}
+			case verb == "Z" && strings.HasPrefix(rest, "Z"):
+				rest = rest[1:]
+			case verb == "ZZZ" && strings.HasPrefix(rest, "UTC"):
+				rest = rest[3:]
+			case verb == "Z" || verb == "ZZZZZ":
+				loc, rest, err = parseDateOffset(rest, true)
+			case verb == "ZZZ" || verb == "ZZZZ":
+				loc, rest, err = parseDateOffset(rest, false)
+			default:
+				return time.Time{}, function.NewArgErrorf(0, "invalid date format verb %q", verb)
+			}
+			if err != nil {
+				return time.Time{}, function.NewArgError(1, err)
+			}
+
+		default:
+			// Any other character must appear literally.
+			if len(rest) == 0 || rest[0] != c {
+				return time.Time{}, function.NewArgErrorf(1, "expected %q, but found %q", format[:1], rest)
+			}
+			format = format[1:]
+			rest = rest[1:]
+		}
+	}
+	if rest != "" {
+		return time.Time{}, function.NewArgErrorf(1, "unexpected %q after the end of the timestamp", rest)
+	}
+
+	if year < 0 || month < 0 || day < 0 {
+		return time.Time{}, function.NewArgErrorf(0, "format must include the year, month and day")
+	}
+	switch {
+	case hour12 >= 0 && pm < 0:
+		return time.Time{}, function.NewArgErrorf(0, "format must include an AM/PM marker when using a 12-hour verb")
+	case hour12 >= 0:
+		hour = hour12%12 + pm*12
+	case pm >= 0:
+		return time.Time{}, function.NewArgErrorf(0, "format must include a 12-hour verb when using an AM/PM marker")
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, loc)
+	if t.Month() != time.Month(month) || t.Day() != day {
+		return time.Time{}, function.NewArgErrorf(1, "day %d is out of range for %s %d", day, time.Month(month), year)
+	}
+	if weekday >= 0 && t.Weekday() != time.Weekday(weekday) {
+		return time.Time{}, function.NewArgErrorf(1, "%s is not a %s", t.Format("2006-01-02"), time.Weekday(weekday))
+	}
+	return t, nil
+}
+
+// dateFormatLiteral returns the content of the quoted literal at the start of
+// a date format, and the length of the format string that it consumed.
+func dateFormatLiteral(format string) (string, int, error) {
+	const esc = '\''
+
+	if len(format) > 1 && format[1] == esc {
+		return "'", 2, nil
+	}
+	var buf strings.Builder
+	for i := 1; i < len(format); i++ {
+		if format[i] != esc {
+			buf.WriteByte(format[i])
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == esc {
+			buf.WriteByte(esc)
+			i++
+			continue
+		}
+		return buf.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("unterminated literal '")
+}
+
+// parseDateNumber parses a decimal number of between minDigits and
+// maxDigits digits from the start of str.
+func parseDateNumber(str string, minDigits, maxDigits int, what string) (int, string, error) {
+	n := 0
+	for n < maxDigits && n < len(str) && str[n] >= '0' && str[n] <= '9' {
+		n++
+	}
+	if n < minDigits {
+		return 0, str, fmt.Errorf("cannot use %q as %s", str, what)
+	}
+	v, _ := strconv.Atoi(str[:n])
+	return v, str[n:], nil
+}
+
+// parseDateName parses one of count English names, optionally abbreviated
+// to their first three letters, from the start of str, ignoring case.
+func parseDateName(str string, abbrev bool, count int, name func(int) string, what string) (int, string, error) {
+	for i := 0; i < count; i++ {
+		n := name(i)
+		if abbrev {
+			n = n[:3]
+		}
+		if len(str) >= len(n) && strings.EqualFold(str[:len(n)], n) {
+			return i, str[len(n):], nil
+		}
+	}
+	return 0, str, fmt.Errorf("cannot use %q as %s", str, what)
+}
+
+// parseDateOffset parses a UTC offset like "-0800" or, if colon is set,
+// "-08:00" from the start of str.
+func parseDateOffset(str string, colon bool) (*time.Location, string, error) {
+	errInvalid := fmt.Errorf("cannot use %q as UTC offset", str)
+
+	if len(str) == 0 || (str[0] != '+' && str[0] != '-') {
+		return nil, str, errInvalid
+	}
+	sign := 1
+	if str[0] == '-' {
+		sign = -1
+	}
+	hours, rest, err := parseDateNumber(str[1:], 2, 2, "UTC offset")
+	if err != nil {
+		return nil, str, errInvalid
+	}
+	if colon {
+		if !strings.HasPrefix(rest, ":") {
+			return nil, str, errInvalid
+		}
+		rest = rest[1:]
+	}
+	minutes, rest, err := parseDateNumber(rest, 2, 2, "UTC offset")
+	if err != nil || minutes > 59 {
+		return nil, str, errInvalid
+	}
+	return time.FixedZone("", sign*(hours*3600+minutes*60)), rest, nil
+}
+
+// iso8601DurationRe matches the ISO 8601 durations accepted by the duration
+// function. Years and months are not accepted because they don't have a
+// fixed length.
+var iso8601DurationRe = regexp.MustCompile(`^([-+]?)P(?:([0-9.,]+)W)?(?:([0-9.,]+)D)?(?:T(?:([0-9.,]+)H)?(?:([0-9.,]+)M)?(?:([0-9.,]+)S)?)?$`)
+
+// iso8601DurationUnits are the lengths of the units matched by each group of
+// iso8601DurationRe after the sign.
+var iso8601DurationUnits = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+
+// parseISO8601Duration parses an ISO 8601 duration, like "P30D" or
+// "PT1H30M". A day is always treated as exactly 24 hours.
+func parseISO8601Duration(str string) (time.Duration, error) {
+	m := iso8601DurationRe.FindStringSubmatch(str)
+	if m == nil || strings.HasSuffix(str, "T") {
+		if date, _, _ := strings.Cut(str, "T"); strings.ContainsAny(date, "YM") {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: years and months are not supported because they don't have a fixed length", str)
+		}
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", str)
+	}
+
+	var total float64
+	seen, fraction := false, false
+	for i, unit := range iso8601DurationUnits {
+		s := m[i+2]
+		if s == "" {
+			continue
+		}
+		if fraction {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: only the smallest unit may have a fraction", str)
+		}
+		s = strings.Replace(s, ",", ".", 1)
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", str)
+		}
+		seen = true
+		fraction = strings.Contains(s, ".")
+		total += v * float64(unit)
+	}
+	if !seen {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", str)
+	}
+	if total >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q: must be less than %s", str, time.Duration(math.MaxInt64).Truncate(time.Hour))
+	}
+
+	d := time.Duration(math.Round(total))
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
@@ -231,3 +231,330 @@ func TestMakeStaticTimestampFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeZone(t *testing.T) {
+	tests := []struct {
+		Time cty.Value
+		Zone cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			cty.StringVal("2024-01-15T12:00:00Z"),
+			cty.StringVal("Europe/Berlin"),
+			cty.StringVal("2024-01-15T13:00:00+01:00"),
+			``,
+		},
+		{ // daylight saving time
+			cty.StringVal("2024-07-15T12:00:00Z"),
+			cty.StringVal("Europe/Berlin"),
+			cty.StringVal("2024-07-15T14:00:00+02:00"),
+			``,
+		},
+		{
+			cty.StringVal("2024-07-15T14:00:00+02:00"),
+			cty.StringVal("America/New_York"),
+			cty.StringVal("2024-07-15T08:00:00-04:00"),
+			``,
+		},
+		{
+			cty.StringVal("2024-07-15T14:00:00+02:00"),
+			cty.StringVal("UTC"),
+			cty.StringVal("2024-07-15T12:00:00Z"),
+			``,
+		},
+		{
+			cty.StringVal("2024-07-15T12:00:00Z"),
+			cty.StringVal("Local"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`unknown time zone "Local"`,
+		},
+		{
+			cty.StringVal("2024-07-15T12:00:00Z"),
+			cty.StringVal("Europe/Atlantis"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`unknown time zone "Europe/Atlantis"`,
+		},
+		{
+			cty.StringVal("2024-07-15"),
+			cty.StringVal("Europe/Berlin"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`not a valid RFC3339 timestamp: missing required time introducer 'T'`,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.StringVal("Europe/Berlin"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("TimeZone(%#v, %#v)", test.Time, test.Zone), func(t *testing.T) {
+			got, err := TimeZone(test.Time, test.Zone)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got := err.Error(); got != test.Err {
+					t.Errorf("wrong error message\ngot:  %s\nwant: %s", got, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		Format cty.Value
+		Str    cty.Value
+		Want   cty.Value
+		Err    string
+	}{
+		{
+			cty.StringVal("DD MMM YYYY hh:mm ZZZ"),
+			cty.StringVal("02 Jan 2006 15:04 UTC"),
+			cty.StringVal("2006-01-02T15:04:00Z"),
+			``,
+		},
+		{
+			cty.StringVal("EEEE, MMMM D, YYYY 'at' H:mmaa"),
+			cty.StringVal("Tuesday, March 5, 2024 at 9:30pm"),
+			cty.StringVal("2024-03-05T21:30:00Z"),
+			``,
+		},
+		{
+			cty.StringVal("YYYY-MM-DD'T'hh:mm:ssZ"),
+			cty.StringVal("2024-03-05T09:30:15+05:30"),
+			cty.StringVal("2024-03-05T09:30:15+05:30"),
+			``,
+		},
+		{
+			cty.StringVal("YYYYMMDDhhmmssZZZZ"),
+			cty.StringVal("20240305093015-0800"),
+			cty.StringVal("2024-03-05T09:30:15-08:00"),
+			``,
+		},
+		{
+			cty.StringVal("M/D/YY HH AA"),
+			cty.StringVal("3/5/24 12 AM"),
+			cty.StringVal("2024-03-05T00:00:00Z"),
+			``,
+		},
+		{
+			cty.StringVal("DD/MM/YYYY"),
+			cty.StringVal("30/02/2024"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`day 30 is out of range for February 2024`,
+		},
+		{
+			cty.StringVal("EEE DD/MM/YYYY"),
+			cty.StringVal("Mon 05/03/2024"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`2024-03-05 is not a Monday`,
+		},
+		{
+			cty.StringVal("DD/MM/YYYY"),
+			cty.StringVal("05-03-2024"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`expected "/", but found "-03-2024"`,
+		},
+		{
+			cty.StringVal("DD/MM/YYYY"),
+			cty.StringVal("05/03/2024 12:00"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`unexpected " 12:00" after the end of the timestamp`,
+		},
+		{
+			cty.StringVal("hh:mm"),
+			cty.StringVal("12:00"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`format must include the year, month and day`,
+		},
+		{
+			cty.StringVal("YYYY-MM-DD HH:mm"),
+			cty.StringVal("2024-03-05 09:30"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`format must include an AM/PM marker when using a 12-hour verb`,
+		},
+		{
+			cty.StringVal("YYYY-MM-DD hh:mm"),
+			cty.StringVal("2024-03-05 25:00"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`hour 25 is out of range`,
+		},
+		{
+			cty.StringVal("YYYY-MM-DD 'T"),
+			cty.StringVal("2024-03-05 T"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`unterminated literal '`,
+		},
+		{
+			cty.StringVal("YYYYY-MM-DD"),
+			cty.StringVal("2024-03-05"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`invalid date format verb "YYYYY"`,
+		},
+		{
+			cty.StringVal("YYYY-MM-DD"),
+			cty.UnknownVal(cty.String),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("ParseTime(%#v, %#v)", test.Format, test.Str), func(t *testing.T) {
+			got, err := ParseTime(test.Format, test.Str)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got := err.Error(); got != test.Err {
+					t.Errorf("wrong error message\ngot:  %s\nwant: %s", got, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestTimeDiff(t *testing.T) {
+	tests := []struct {
+		TimeA cty.Value
+		TimeB cty.Value
+		Want  cty.Value
+		Err   string
+	}{
+		{
+			cty.StringVal("2024-03-05T12:00:00Z"),
+			cty.StringVal("2024-03-04T10:30:00Z"),
+			cty.StringVal("25h30m0s"),
+			``,
+		},
+		{
+			cty.StringVal("2024-03-04T10:30:00Z"),
+			cty.StringVal("2024-03-05T12:00:00Z"),
+			cty.StringVal("-25h30m0s"),
+			``,
+		},
+		{
+			cty.StringVal("2024-03-05T12:00:00Z"),
+			cty.StringVal("2024-03-05T13:00:00+01:00"),
+			cty.StringVal("0s"),
+			``,
+		},
+		{
+			cty.StringVal("2024-03-05T12:00:00Z"),
+			cty.StringVal("bloop"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`not a valid RFC3339 timestamp: cannot use "bloop" as year`,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.StringVal("2024-03-05T12:00:00Z"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("TimeDiff(%#v, %#v)", test.TimeA, test.TimeB), func(t *testing.T) {
+			got, err := TimeDiff(test.TimeA, test.TimeB)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got := err.Error(); got != test.Err {
+					t.Errorf("wrong error message\ngot:  %s\nwant: %s", got, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		Str  cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{cty.StringVal("P30D"), cty.StringVal("720h0m0s"), ``},
+		{cty.StringVal("P2W"), cty.StringVal("336h0m0s"), ``},
+		{cty.StringVal("PT1H30M"), cty.StringVal("1h30m0s"), ``},
+		{cty.StringVal("P1DT12H"), cty.StringVal("36h0m0s"), ``},
+		{cty.StringVal("PT1.5H"), cty.StringVal("1h30m0s"), ``},
+		{cty.StringVal("PT0,5S"), cty.StringVal("500ms"), ``},
+		{cty.StringVal("-PT15M"), cty.StringVal("-15m0s"), ``},
+		{cty.StringVal("PT0S"), cty.StringVal("0s"), ``},
+		{
+			cty.StringVal("P1Y"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`invalid ISO 8601 duration "P1Y": years and months are not supported because they don't have a fixed length`,
+		},
+		{
+			cty.StringVal("P1M"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`invalid ISO 8601 duration "P1M": years and months are not supported because they don't have a fixed length`,
+		},
+		{
+			cty.StringVal("PT1.5H30M"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`invalid ISO 8601 duration "PT1.5H30M": only the smallest unit may have a fraction`,
+		},
+		{cty.StringVal("P"), cty.UnknownVal(cty.String).RefineNotNull(), `invalid ISO 8601 duration "P"`},
+		{cty.StringVal("P1DT"), cty.UnknownVal(cty.String).RefineNotNull(), `invalid ISO 8601 duration "P1DT"`},
+		{cty.StringVal("1h"), cty.UnknownVal(cty.String).RefineNotNull(), `invalid ISO 8601 duration "1h"`},
+		{
+			cty.StringVal("P365000D"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			`invalid ISO 8601 duration "P365000D": must be less than 2562047h0m0s`,
+		},
+		{cty.UnknownVal(cty.String), cty.UnknownVal(cty.String).RefineNotNull(), ``},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("Duration(%#v)", test.Str), func(t *testing.T) {
+			got, err := Duration(test.Str)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got := err.Error(); got != test.Err {
+					t.Errorf("wrong error message\ngot:  %s\nwant: %s", got, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -78,6 +78,10 @@ var DescriptionList = map[string]descriptionEntry{
 			"The `cost` argument is optional and will default to 10 if unspecified.",
 		},
 	},
+	"blake2b": {
+		Description:      "`blake2b` computes the BLAKE2b-512 hash of a given string and encodes it with hexadecimal digits.",
+		ParamDescription: []string{""},
+	},
 	"can": {
 		Description:      "`can` evaluates the given expression and returns a boolean value indicating whether the expression produced a result without any errors.",
 		ParamDescription: []string{""},
@@ -104,6 +108,13 @@ var DescriptionList = map[string]descriptionEntry{
 			"`contained_ip_or_prefix` is either an IP address or an address prefix given in CIDR notation.",
 		},
 	},
+	"cidrexclude": {
+		Description: "`cidrexclude` removes a list of IP network address prefixes from a given prefix, and returns the smallest list of prefixes covering the remaining addresses.",
+		ParamDescription: []string{
+			"`prefix` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`excluded_prefixes` is a list of address prefixes given in CIDR notation.",
+		},
+	},
 	"cidrhost": {
 		Description: "`cidrhost` calculates a full host IP address for a given host number within a given IP network address prefix.",
 		ParamDescription: []string{
@@ -111,12 +122,40 @@ var DescriptionList = map[string]descriptionEntry{
 			"`hostnum` is a whole number that can be represented as a binary integer with no more than the number of digits remaining in the address after the given prefix.",
 		},
 	},
+	"cidrhosts": {
+		Description: "`cidrhosts` returns a list of the IP addresses within a given IP network address prefix, up to a given limit.",
+		ParamDescription: []string{
+			"`prefix` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`limit` is the maximum number of addresses to return.",
+		},
+	},
+	"cidrmerge": {
+		Description: "`cidrmerge` aggregates a list of IP network address prefixes into the smallest list of prefixes covering the same addresses.",
+		ParamDescription: []string{
+			"`prefixes` is a list of address prefixes given in CIDR notation.",
+		},
+	},
 	"cidrnetmask": {
 		Description: "`cidrnetmask` converts an IPv4 address prefix given in CIDR notation into a subnet mask address.",
 		ParamDescription: []string{
 			"`prefix` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
 		},
 	},
+	"cidrnextfree": {
+		Description: "`cidrnextfree` finds the first subnet of a given size within an IP network address prefix that doesn't overlap any of a list of prefixes that are already in use.",
+		ParamDescription: []string{
+			"`prefix` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`used_prefixes` is a list of address prefixes given in CIDR notation that are already allocated.",
+			"`newbits` is the number of additional bits with which to extend the prefix.",
+		},
+	},
+	"cidroverlaps": {
+		Description: "`cidroverlaps` determines whether two IP network address prefixes have any addresses in common.",
+		ParamDescription: []string{
+			"`prefix_a` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`prefix_b` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+		},
+	},
 	"cidrsubnet": {
 		Description: "`cidrsubnet` calculates a subnet address within given IP network address prefix.",
 		ParamDescription: []string{
@@ -157,6 +196,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`csvdecode` decodes a string containing CSV-formatted data and produces a list of maps representing that data.",
 		ParamDescription: []string{""},
 	},
+	"deepmerge": {
+		Description:      "`deepmerge` takes an arbitrary number of maps or objects, and returns a single map or object that contains a merged set of elements from all arguments, recursively merging nested maps and objects.",
+		ParamDescription: []string{""},
+	},
 	"dirname": {
 		Description:      "`dirname` takes a string containing a filesystem path and removes the last portion from it.",
 		ParamDescription: []string{""},
@@ -165,10 +208,18 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`distinct` takes a list and returns a new list with any duplicate elements removed.",
 		ParamDescription: []string{""},
 	},
+	"duration": {
+		Description:      "`duration` converts an [ISO 8601](https://en.wikipedia.org/wiki/ISO_8601#Durations) duration, like `P30D`, into the duration syntax accepted by `timeadd`.",
+		ParamDescription: []string{""},
+	},
 	"element": {
 		Description:      "`element` retrieves a single element from a list.",
 		ParamDescription: []string{"", ""},
 	},
+	"ed25519verify": {
+		Description:      "`ed25519verify` verifies an Ed25519 signature of a message, returning `true` only if the base64-encoded signature is valid for the given public key.",
+		ParamDescription: []string{"", "", ""},
+	},
 	"endswith": {
 		Description:      "`endswith` takes two values: a string to check and a suffix string. The function returns true if the first string ends with that exact suffix.",
 		ParamDescription: []string{"", ""},
@@ -237,6 +288,18 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`formatlist` produces a list of strings by formatting a number of other values according to a specification string.",
 		ParamDescription: []string{"", ""},
 	},
+	"hcldecode": {
+		Description:      "`hcldecode` parses a string containing HCL native syntax attribute definitions, like a `.tfvars` file, and produces an object with an attribute for each definition.",
+		ParamDescription: []string{""},
+	},
+	"hmacsha256": {
+		Description:      "`hmacsha256` computes the HMAC-SHA256 of a message using a secret key, and encodes it with hexadecimal digits.",
+		ParamDescription: []string{"", ""},
+	},
+	"hmacsha512": {
+		Description:      "`hmacsha512` computes the HMAC-SHA512 of a message using a secret key, and encodes it with hexadecimal digits.",
+		ParamDescription: []string{"", ""},
+	},
 	"indent": {
 		Description: "`indent` adds a given number of spaces to the beginnings of all but the first line in a given multi-line string.",
 		ParamDescription: []string{
@@ -267,6 +330,14 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`jsonencode` encodes a given value to a string using JSON syntax.",
 		ParamDescription: []string{""},
 	},
+	"jsonmergepatch": {
+		Description:      "`jsonmergepatch` applies a [JSON Merge Patch](https://www.rfc-editor.org/rfc/rfc7396) to a value.",
+		ParamDescription: []string{"", ""},
+	},
+	"jsonpatch": {
+		Description:      "`jsonpatch` applies a sequence of [JSON Patch](https://www.rfc-editor.org/rfc/rfc6902) operations to a value.",
+		ParamDescription: []string{"", ""},
+	},
 	"keys": {
 		Description: "`keys` takes a map and returns a list containing the keys from that map.",
 		ParamDescription: []string{
@@ -329,14 +400,26 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`parseint` parses the given string as a representation of an integer in the specified base and returns the resulting number. The base must be between 2 and 62 inclusive.",
 		ParamDescription: []string{"", ""},
 	},
+	"parsetime": {
+		Description:      "`parsetime` parses a timestamp written using the same format syntax as `formatdate`, returning it in [RFC 3339](https://tools.ietf.org/html/rfc3339) format.",
+		ParamDescription: []string{"", ""},
+	},
 	"pathexpand": {
 		Description:      "`pathexpand` takes a filesystem path that might begin with a `~` segment, and if so it replaces that segment with the current user's home directory path.",
 		ParamDescription: []string{""},
 	},
+	"pemdecode": {
+		Description:      "`pemdecode` decodes all of the PEM blocks in a string, returning a list of objects describing each block's type, headers and base64-encoded content.",
+		ParamDescription: []string{""},
+	},
 	"pow": {
 		Description:      "`pow` calculates an exponent, by raising its first argument to the power of the second argument.",
 		ParamDescription: []string{"", ""},
 	},
+	"query": {
+		Description:      "`query` evaluates a [JMESPath](https://jmespath.org/) expression against a value, supporting projections, filters and flattening, and returns the result.",
+		ParamDescription: []string{"", ""},
+	},
 	"range": {
 		Description:      "`range` generates a list of numbers using a start value, a limit value, and a step value.",
 		ParamDescription: []string{""},
@@ -361,6 +444,26 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`rsadecrypt` decrypts an RSA-encrypted ciphertext, returning the corresponding cleartext.",
 		ParamDescription: []string{"", ""},
 	},
+	"rsaverify": {
+		Description:      "`rsaverify` verifies an RSA signature of a message using the RSASSA-PKCS1-v1_5 scheme with SHA-256, returning `true` only if the base64-encoded signature is valid for the given public key.",
+		ParamDescription: []string{"", "", ""},
+	},
+	"semvercompare": {
+		Description:      "`semvercompare` compares two semantic versions, returning -1, 0 or 1 if the first is respectively lower than, equal to or higher than the second.",
+		ParamDescription: []string{"", ""},
+	},
+	"semvermatch": {
+		Description:      "`semvermatch` returns `true` if a semantic version meets a version constraint, and `false` otherwise.",
+		ParamDescription: []string{"", ""},
+	},
+	"semvermax": {
+		Description:      "`semvermax` returns the highest of a list of semantic versions that meets a version constraint, or `null` if none of them do.",
+		ParamDescription: []string{"", ""},
+	},
+	"semverparse": {
+		Description:      "`semverparse` parses a semantic version string and returns an object describing its parts.",
+		ParamDescription: []string{""},
+	},
 	"sensitive": {
 		Description:      "`sensitive` takes any value and returns a copy of it marked so that OpenTofu will treat it as sensitive, with the same meaning and behavior as for [sensitive input variables](/language/values/variables#suppressing-values-in-cli-output).",
 		ParamDescription: []string{""},
@@ -391,6 +494,14 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`sha256` computes the SHA256 hash of a given string and encodes it with hexadecimal digits.",
 		ParamDescription: []string{""},
 	},
+	"sha3_256": {
+		Description:      "`sha3_256` computes the SHA3-256 hash of a given string and encodes it with hexadecimal digits.",
+		ParamDescription: []string{""},
+	},
+	"sha3_512": {
+		Description:      "`sha3_512` computes the SHA3-512 hash of a given string and encodes it with hexadecimal digits.",
+		ParamDescription: []string{""},
+	},
 	"sha512": {
 		Description:      "`sha512` computes the SHA512 hash of a given string and encodes it with hexadecimal digits.",
 		ParamDescription: []string{""},
@@ -455,10 +566,18 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`timecmp` compares two timestamps and returns a number that represents the ordering of the instants those timestamps represent.",
 		ParamDescription: []string{"", ""},
 	},
+	"timediff": {
+		Description:      "`timediff` returns the duration between two timestamps, using the duration syntax accepted by `timeadd`.",
+		ParamDescription: []string{"", ""},
+	},
 	"timestamp": {
 		Description:      "`timestamp` returns a UTC timestamp string in [RFC 3339](https://tools.ietf.org/html/rfc3339) format.",
 		ParamDescription: []string{},
 	},
+	"timezone": {
+		Description:      "`timezone` converts a timestamp to the local time of the given [IANA time zone](https://www.iana.org/time-zones), like `Europe/Berlin`.",
+		ParamDescription: []string{"", ""},
+	},
 	"plantimestamp": {
 		Description:      "`plantimestamp` returns a UTC timestamp string in [RFC 3339](https://tools.ietf.org/html/rfc3339) format, fixed to a constant time representing the time of the plan.",
 		ParamDescription: []string{},
@@ -479,6 +598,14 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`tomap` converts its argument to a map value.",
 		ParamDescription: []string{""},
 	},
+	"tomldecode": {
+		Description:      "`tomldecode` parses a string as a [TOML](https://toml.io/) document and produces a representation of its top-level table.",
+		ParamDescription: []string{""},
+	},
+	"tomlencode": {
+		Description:      "`tomlencode` encodes a given object or map as a [TOML](https://toml.io/) document.",
+		ParamDescription: []string{""},
+	},
 	"tonumber": {
 		Description:      "`tonumber` converts its argument to a number value.",
 		ParamDescription: []string{""},
@@ -546,6 +673,14 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`values` takes a map and returns a list containing the values of the elements in that map.",
 		ParamDescription: []string{""},
 	},
+	"x509decode": {
+		Description:      "`x509decode` decodes the first PEM-encoded X.509 certificate in a string, returning an object describing its subject, issuer, validity period, subject alternative names and fingerprint.",
+		ParamDescription: []string{""},
+	},
+	"xmldecode": {
+		Description:      "`xmldecode` parses a string as an XML document and produces a representation of its root element.",
+		ParamDescription: []string{""},
+	},
 	"yamldecode": {
 		Description:      "`yamldecode` parses a string as a subset of YAML, and produces a representation of its value.",
 		ParamDescription: []string{""},
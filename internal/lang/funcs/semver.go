@@ -0,0 +1,189 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+
+	"github.com/apparentlymart/go-versions/versions"
+	"github.com/apparentlymart/go-versions/versions/constraints"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// semverType is the type of object returned by SemverParseFunc.
+var semverType = cty.Object(map[string]cty.Type{
+	"major":      cty.Number,
+	"minor":      cty.Number,
+	"patch":      cty.Number,
+	"prerelease": cty.String,
+	"metadata":   cty.String,
+})
+
+// SemverParseFunc constructs a function that parses a semantic version
+// string into an object describing its parts.
+var SemverParseFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "version",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(semverType),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v, err := versions.ParseVersion(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid version: %s", err)
+		}
+		return cty.ObjectVal(map[string]cty.Value{
+			"major":      cty.NumberUIntVal(v.Major),
+			"minor":      cty.NumberUIntVal(v.Minor),
+			"patch":      cty.NumberUIntVal(v.Patch),
+			"prerelease": cty.StringVal(string(v.Prerelease)),
+			"metadata":   cty.StringVal(string(v.Metadata)),
+		}), nil
+	},
+})
+
+// SemverCompareFunc constructs a function that compares two semantic
+// versions, returning -1, 0 or 1 if the first is respectively lower than,
+// equal to or higher than the second. Build metadata is ignored.
+var SemverCompareFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "a",
+			Type: cty.String,
+		},
+		{
+			Name: "b",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Number),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		a, err := versions.ParseVersion(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid version: %s", err)
+		}
+		b, err := versions.ParseVersion(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "invalid version: %s", err)
+		}
+		switch {
+		case a.LessThan(b):
+			return cty.NumberIntVal(-1), nil
+		case a.GreaterThan(b):
+			return cty.NumberIntVal(1), nil
+		default:
+			return cty.NumberIntVal(0), nil
+		}
+	},
+})
+
+// SemverMatchFunc constructs a function that tests whether a semantic
+// version meets a version constraint, using the same constraint syntax as
+// provider version constraints.
+var SemverMatchFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "version",
+			Type: cty.String,
+		},
+		{
+			Name: "constraint",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v, err := versions.ParseVersion(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid version: %s", err)
+		}
+		allowed, err := semverConstraint(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(1, err)
+		}
+		return cty.BoolVal(allowed.Has(v)), nil
+	},
+})
+
+// SemverMaxFunc constructs a function that returns the highest of a list of
+// semantic versions that meets a version constraint, or null if none of
+// them do.
+var SemverMaxFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "versions",
+			Type: cty.List(cty.String),
+		},
+		{
+			Name: "constraint",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		allowed, err := semverConstraint(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(1, err)
+		}
+		if !args[0].IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		var best versions.Version
+		ret := cty.NullVal(retType)
+		for it := args[0].ElementIterator(); it.Next(); {
+			_, str := it.Element()
+			if str.IsNull() {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "versions must not be null")
+			}
+			v, err := versions.ParseVersion(str.AsString())
+			if err != nil {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid version %q: %s", str.AsString(), err)
+			}
+			if allowed.Has(v) && (ret.IsNull() || v.GreaterThan(best)) {
+				best = v
+				ret = str
+			}
+		}
+		return ret, nil
+	},
+})
+
+func semverConstraint(str string) (versions.Set, error) {
+	spec, err := constraints.ParseRubyStyleMulti(str)
+	if err != nil {
+		return versions.None, fmt.Errorf("invalid version constraint: %w", err)
+	}
+	return versions.MeetingConstraints(spec), nil
+}
+
+// SemverParse parses a semantic version string into an object describing
+// its parts.
+func SemverParse(version cty.Value) (cty.Value, error) {
+	return SemverParseFunc.Call([]cty.Value{version})
+}
+
+// SemverCompare compares two semantic versions.
+func SemverCompare(a, b cty.Value) (cty.Value, error) {
+	return SemverCompareFunc.Call([]cty.Value{a, b})
+}
+
+// SemverMatch tests whether a semantic version meets a version constraint.
+func SemverMatch(version, constraint cty.Value) (cty.Value, error) {
+	return SemverMatchFunc.Call([]cty.Value{version, constraint})
+}
+
+// SemverMax returns the highest of the given semantic versions that meets
+// a version constraint.
+func SemverMax(list, constraint cty.Value) (cty.Value, error) {
+	return SemverMaxFunc.Call([]cty.Value{list, constraint})
+}
@@ -0,0 +1,220 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestSemverParse(t *testing.T) {
+	tests := []struct {
+		Version cty.Value
+		Want    cty.Value
+		Err     bool
+	}{
+		{
+			cty.StringVal("1.2.3"),
+			cty.ObjectVal(map[string]cty.Value{
+				"major":      cty.NumberIntVal(1),
+				"minor":      cty.NumberIntVal(2),
+				"patch":      cty.NumberIntVal(3),
+				"prerelease": cty.StringVal(""),
+				"metadata":   cty.StringVal(""),
+			}),
+			false,
+		},
+		{
+			cty.StringVal("2.0.0-beta.1+build.5"),
+			cty.ObjectVal(map[string]cty.Value{
+				"major":      cty.NumberIntVal(2),
+				"minor":      cty.NumberIntVal(0),
+				"patch":      cty.NumberIntVal(0),
+				"prerelease": cty.StringVal("beta.1"),
+				"metadata":   cty.StringVal("build.5"),
+			}),
+			false,
+		},
+		{
+			cty.StringVal("1.2.3").Mark(marks.Sensitive),
+			cty.ObjectVal(map[string]cty.Value{
+				"major":      cty.NumberIntVal(1),
+				"minor":      cty.NumberIntVal(2),
+				"patch":      cty.NumberIntVal(3),
+				"prerelease": cty.StringVal(""),
+				"metadata":   cty.StringVal(""),
+			}).Mark(marks.Sensitive),
+			false,
+		},
+		{
+			cty.StringVal("v1.2.3"),
+			cty.NilVal,
+			true,
+		},
+		{
+			cty.StringVal("latest"),
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("semverparse(%#v)", test.Version), func(t *testing.T) {
+			got, err := SemverParse(test.Version)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	tests := []struct {
+		A, B cty.Value
+		Want cty.Value
+		Err  bool
+	}{
+		{cty.StringVal("1.2.3"), cty.StringVal("1.10.0"), cty.NumberIntVal(-1), false},
+		{cty.StringVal("1.10.0"), cty.StringVal("1.2.3"), cty.NumberIntVal(1), false},
+		{cty.StringVal("1.2.3"), cty.StringVal("1.2.3+build.1"), cty.NumberIntVal(0), false},
+		{cty.StringVal("1.0.0-rc.1"), cty.StringVal("1.0.0"), cty.NumberIntVal(-1), false},
+		{cty.StringVal("1.0.0-alpha"), cty.StringVal("1.0.0-beta"), cty.NumberIntVal(-1), false},
+		{cty.StringVal("1.0.0"), cty.StringVal("one"), cty.NilVal, true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("semvercompare(%#v, %#v)", test.A, test.B), func(t *testing.T) {
+			got, err := SemverCompare(test.A, test.B)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestSemverMatch(t *testing.T) {
+	tests := []struct {
+		Version, Constraint cty.Value
+		Want                cty.Value
+		Err                 bool
+	}{
+		{cty.StringVal("1.2.3"), cty.StringVal(">= 1.0, < 2.0"), cty.True, false},
+		{cty.StringVal("2.0.0"), cty.StringVal(">= 1.0, < 2.0"), cty.False, false},
+		{cty.StringVal("1.4.0"), cty.StringVal("~> 1.2"), cty.True, false},
+		{cty.StringVal("1.2.9"), cty.StringVal("~> 1.2.0"), cty.True, false},
+		{cty.StringVal("1.3.0"), cty.StringVal("~> 1.2.0"), cty.False, false},
+		{cty.StringVal("1.3.0"), cty.StringVal("!= 1.3.0"), cty.False, false},
+		// Prereleases only match constraints that mention them exactly.
+		{cty.StringVal("1.3.0-beta.1"), cty.StringVal(">= 1.0"), cty.False, false},
+		{cty.StringVal("1.3.0-beta.1"), cty.StringVal("1.3.0-beta.1"), cty.True, false},
+		{cty.StringVal("1.2.3"), cty.StringVal(""), cty.True, false},
+		{cty.UnknownVal(cty.String), cty.StringVal("~> 1.2"), cty.UnknownVal(cty.Bool).RefineNotNull(), false},
+		{cty.StringVal("1.2.3"), cty.StringVal("around 1.2"), cty.NilVal, true},
+		{cty.StringVal("1.x"), cty.StringVal("~> 1.2"), cty.NilVal, true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("semvermatch(%#v, %#v)", test.Version, test.Constraint), func(t *testing.T) {
+			got, err := SemverMatch(test.Version, test.Constraint)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestSemverMax(t *testing.T) {
+	versions := cty.ListVal([]cty.Value{
+		cty.StringVal("1.2.0"),
+		cty.StringVal("1.10.1"),
+		cty.StringVal("2.0.0-rc.1"),
+		cty.StringVal("1.9.7"),
+		cty.StringVal("0.9.0"),
+	})
+
+	tests := []struct {
+		Versions, Constraint cty.Value
+		Want                 cty.Value
+		Err                  bool
+	}{
+		{versions, cty.StringVal(""), cty.StringVal("1.10.1"), false},
+		{versions, cty.StringVal("< 1.10"), cty.StringVal("1.9.7"), false},
+		{versions, cty.StringVal("~> 0.9"), cty.StringVal("0.9.0"), false},
+		{versions, cty.StringVal(">= 3.0"), cty.NullVal(cty.String), false},
+		{cty.ListValEmpty(cty.String), cty.StringVal(""), cty.NullVal(cty.String), false},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("1.0.0"), cty.UnknownVal(cty.String)}),
+			cty.StringVal(""),
+			cty.UnknownVal(cty.String),
+			false,
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("1.0.0"), cty.StringVal("1.1.0").Mark(marks.Sensitive)}),
+			cty.StringVal(""),
+			cty.StringVal("1.1.0").Mark(marks.Sensitive),
+			false,
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("1.0.0"), cty.StringVal("latest")}),
+			cty.StringVal(""),
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("semvermax(%#v, %#v)", test.Versions, test.Constraint), func(t *testing.T) {
+			got, err := SemverMax(test.Versions, test.Constraint)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// tzdataZip is a copy of the IANA Time Zone Database. We embed our own copy
+// rather than using the time package's LoadLocation so that the results of
+// the timezone function don't depend on the time zone files that happen to
+// be installed on the current system. See tzdata/README.md for details.
+//
+//go:embed tzdata/zoneinfo.zip
+var tzdataZip []byte
+
+var (
+	tzdataOnce      sync.Once
+	tzdataFiles     map[string]*zip.File
+	tzdataErr       error
+	tzdataLocations sync.Map
+)
+
+// loadLocation returns the location with the given IANA time zone name, such
+// as "Europe/Berlin", from the embedded time zone database.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "UTC" {
+		return time.UTC, nil
+	}
+	if loc, ok := tzdataLocations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	tzdataOnce.Do(func() {
+		r, err := zip.NewReader(bytes.NewReader(tzdataZip), int64(len(tzdataZip)))
+		if err != nil {
+			tzdataErr = err
+			return
+		}
+		tzdataFiles = make(map[string]*zip.File, len(r.File))
+		for _, f := range r.File {
+			tzdataFiles[f.Name] = f
+		}
+	})
+	if tzdataErr != nil {
+		// Should never happen, since the archive is embedded at build time.
+		return nil, fmt.Errorf("failed to read the time zone database: %w", tzdataErr)
+	}
+
+	f, ok := tzdataFiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read time zone %q: %w", name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read time zone %q: %w", name, err)
+	}
+	loc, err := time.LoadLocationFromTZData(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read time zone %q: %w", name, err)
+	}
+	tzdataLocations.Store(name, loc)
+	return loc, nil
+}
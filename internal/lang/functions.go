@@ -7,6 +7,7 @@ package lang
 
 import (
 	"fmt"
+	"maps"
 
 	"github.com/hashicorp/hcl/v2/ext/tryfunc"
 	ctyyaml "github.com/zclconf/go-cty-yaml"
@@ -61,6 +62,10 @@ func (s *Scope) Functions() map[string]function.Function {
 		for _, name := range coreNames {
 			s.funcs[addrs.ParseFunction(name).FullyQualified().String()] = s.funcs[name]
 		}
+
+		if s.ModuleFunctions != nil {
+			maps.Copy(s.funcs, s.ModuleFunctions(maps.Clone(s.funcs)))
+		}
 	}
 	s.funcsLock.Unlock()
 
@@ -125,12 +130,18 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"base64sha256":     funcs.Base64Sha256Func,
 		"base64sha512":     funcs.Base64Sha512Func,
 		"bcrypt":           funcs.BcryptFunc,
+		"blake2b":          funcs.Blake2bFunc,
 		"can":              tryfunc.CanFunc,
 		"ceil":             stdlib.CeilFunc,
 		"chomp":            stdlib.ChompFunc,
 		"cidrcontains":     funcs.CidrContainsFunc,
+		"cidrexclude":      funcs.CidrExcludeFunc,
 		"cidrhost":         funcs.CidrHostFunc,
+		"cidrhosts":        funcs.CidrHostsFunc,
+		"cidrmerge":        funcs.CidrMergeFunc,
 		"cidrnetmask":      funcs.CidrNetmaskFunc,
+		"cidrnextfree":     funcs.CidrNextFreeFunc,
+		"cidroverlaps":     funcs.CidrOverlapsFunc,
 		"cidrsubnet":       funcs.CidrSubnetFunc,
 		"cidrsubnets":      funcs.CidrSubnetsFunc,
 		"coalesce":         funcs.CoalesceFunc,
@@ -139,9 +150,12 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"concat":           stdlib.ConcatFunc,
 		"contains":         stdlib.ContainsFunc,
 		"csvdecode":        stdlib.CSVDecodeFunc,
+		"deepmerge":        funcs.DeepMergeFunc,
 		"dirname":          funcs.DirnameFunc,
 		"distinct":         stdlib.DistinctFunc,
+		"duration":         funcs.DurationFunc,
 		"element":          stdlib.ElementFunc,
+		"ed25519verify":    funcs.Ed25519VerifyFunc,
 		"endswith":         funcs.EndsWithFunc,
 		"ephemeralasnull":  funcs.EphemeralAsNullFunc,
 		"chunklist":        stdlib.ChunklistFunc,
@@ -160,11 +174,16 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"format":           stdlib.FormatFunc,
 		"formatdate":       stdlib.FormatDateFunc,
 		"formatlist":       stdlib.FormatListFunc,
+		"hcldecode":        funcs.HCLDecodeFunc,
+		"hmacsha256":       funcs.HmacSha256Func,
+		"hmacsha512":       funcs.HmacSha512Func,
 		"indent":           stdlib.IndentFunc,
 		"index":            funcs.IndexFunc, // stdlib.IndexFunc is not compatible
 		"join":             stdlib.JoinFunc,
 		"jsondecode":       stdlib.JSONDecodeFunc,
 		"jsonencode":       stdlib.JSONEncodeFunc,
+		"jsonmergepatch":   funcs.JSONMergePatchFunc,
+		"jsonpatch":        funcs.JSONPatchFunc,
 		"keys":             stdlib.KeysFunc,
 		"length":           funcs.LengthFunc,
 		"list":             funcs.ListFunc,
@@ -179,14 +198,22 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"min":              stdlib.MinFunc,
 		"one":              funcs.OneFunc,
 		"parseint":         stdlib.ParseIntFunc,
+		"parsetime":        funcs.ParseTimeFunc,
 		"pathexpand":       funcs.PathExpandFunc,
+		"pemdecode":        funcs.PemDecodeFunc,
 		"pow":              stdlib.PowFunc,
+		"query":            funcs.QueryFunc,
 		"range":            stdlib.RangeFunc,
 		"regex":            stdlib.RegexFunc,
 		"regexall":         stdlib.RegexAllFunc,
 		"replace":          funcs.ReplaceFunc,
 		"reverse":          stdlib.ReverseListFunc,
 		"rsadecrypt":       funcs.RsaDecryptFunc,
+		"rsaverify":        funcs.RsaVerifyFunc,
+		"semvercompare":    funcs.SemverCompareFunc,
+		"semvermatch":      funcs.SemverMatchFunc,
+		"semvermax":        funcs.SemverMaxFunc,
+		"semverparse":      funcs.SemverParseFunc,
 		"sensitive":        funcs.SensitiveFunc,
 		"nonsensitive":     funcs.NonsensitiveFunc,
 		"issensitive":      funcs.IsSensitiveFunc,
@@ -196,6 +223,8 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"setunion":         stdlib.SetUnionFunc,
 		"sha1":             funcs.Sha1Func,
 		"sha256":           funcs.Sha256Func,
+		"sha3_256":         funcs.Sha3_256Func,
+		"sha3_512":         funcs.Sha3_512Func,
 		"sha512":           funcs.Sha512Func,
 		"signum":           stdlib.SignumFunc,
 		"slice":            stdlib.SliceFunc,
@@ -211,6 +240,8 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"timestamp":        funcs.TimestampFunc,
 		"timeadd":          stdlib.TimeAddFunc,
 		"timecmp":          funcs.TimeCmpFunc,
+		"timediff":         funcs.TimeDiffFunc,
+		"timezone":         funcs.TimeZoneFunc,
 		"title":            stdlib.TitleFunc,
 		"tostring":         funcs.MakeToFunc(cty.String),
 		"tonumber":         funcs.MakeToFunc(cty.Number),
@@ -218,6 +249,8 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"toset":            funcs.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
 		"tolist":           funcs.MakeToFunc(cty.List(cty.DynamicPseudoType)),
 		"tomap":            funcs.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+		"tomldecode":       funcs.TOMLDecodeFunc,
+		"tomlencode":       funcs.TOMLEncodeFunc,
 		"transpose":        funcs.TransposeFunc,
 		"trim":             stdlib.TrimFunc,
 		"trimprefix":       stdlib.TrimPrefixFunc,
@@ -230,6 +263,8 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 		"uuid":             funcs.UUIDFunc,
 		"uuidv5":           funcs.UUIDV5Func,
 		"values":           stdlib.ValuesFunc,
+		"x509decode":       funcs.X509DecodeFunc,
+		"xmldecode":        funcs.XMLDecodeFunc,
 		"yamldecode":       ctyyaml.YAMLDecodeFunc,
 		"yamlencode":       ctyyaml.YAMLEncodeFunc,
 		"zipmap":           stdlib.ZipmapFunc,
@@ -140,6 +140,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"blake2b": {
+			{
+				`blake2b("test")`,
+				cty.StringVal("a71079d42853dea26e453004338670a53814b78137ffbed07603a41d76a483aa9bc33b582f77d30a65e6f29a896c0411f38312e1d66e0bf16386c86a89bea572"),
+			},
+		},
+
 		"can": {
 			{
 				`can(true)`,
@@ -199,6 +206,15 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"cidrexclude": {
+			{
+				`cidrexclude("10.0.0.0/24", ["10.0.0.0/26", "10.0.0.128/25"])`,
+				cty.ListVal([]cty.Value{
+					cty.StringVal("10.0.0.64/26"),
+				}),
+			},
+		},
+
 		"cidrhost": {
 			{
 				`cidrhost("192.168.1.0/24", 5)`,
@@ -206,6 +222,25 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"cidrhosts": {
+			{
+				`cidrhosts("192.168.1.0/24", 2)`,
+				cty.ListVal([]cty.Value{
+					cty.StringVal("192.168.1.0"),
+					cty.StringVal("192.168.1.1"),
+				}),
+			},
+		},
+
+		"cidrmerge": {
+			{
+				`cidrmerge(["10.0.0.0/24", "10.0.1.0/24"])`,
+				cty.ListVal([]cty.Value{
+					cty.StringVal("10.0.0.0/23"),
+				}),
+			},
+		},
+
 		"cidrnetmask": {
 			{
 				`cidrnetmask("192.168.1.0/24")`,
@@ -213,6 +248,20 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"cidrnextfree": {
+			{
+				`cidrnextfree("10.0.0.0/16", ["10.0.0.0/24", "10.0.2.0/24"], 8)`,
+				cty.StringVal("10.0.1.0/24"),
+			},
+		},
+
+		"cidroverlaps": {
+			{
+				`cidroverlaps("10.0.0.0/16", "10.0.5.0/24")`,
+				cty.True,
+			},
+		},
+
 		"cidrsubnet": {
 			{
 				`cidrsubnet("192.168.2.0/20", 4, 6)`,
@@ -313,6 +362,18 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"deepmerge": {
+			{
+				`deepmerge({a = {b = 1, c = 2}}, {a = {c = 3}})`,
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{
+						"b": cty.NumberIntVal(1),
+						"c": cty.NumberIntVal(3),
+					}),
+				}),
+			},
+		},
+
 		"dirname": {
 			{
 				`dirname("testdata/hello.txt")`,
@@ -329,6 +390,24 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"duration": {
+			{
+				`duration("P30D")`,
+				cty.StringVal("720h0m0s"),
+			},
+			{
+				`timeadd("2024-01-01T00:00:00Z", duration("PT1H30M"))`,
+				cty.StringVal("2024-01-01T01:30:00Z"),
+			},
+		},
+
+		"ed25519verify": {
+			{
+				fmt.Sprintf("ed25519verify(%#v, \"hello\", %#v)", Ed25519PublicKey, Ed25519Signature),
+				cty.True,
+			},
+		},
+
 		"element": {
 			{
 				`element(["hello"], 0)`,
@@ -522,6 +601,30 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"hcldecode": {
+			{
+				`hcldecode("name = \"web\"\nports = [80, 443]\n")`,
+				cty.ObjectVal(map[string]cty.Value{
+					"name":  cty.StringVal("web"),
+					"ports": cty.TupleVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(443)}),
+				}),
+			},
+		},
+
+		"hmacsha256": {
+			{
+				`hmacsha256("key", "test")`,
+				cty.StringVal("02afb56304902c656fcb737cdd03de6205bb6d401da2812efd9b2d36a08af159"),
+			},
+		},
+
+		"hmacsha512": {
+			{
+				`hmacsha512("key", "test")`,
+				cty.StringVal("287a0fb89a7fbdfa5b5538636918e537a5b83065e4ff331268b7aaa115dde047a9b0f4fb5b828608fc0b6327f10055f7637b058e9e0dbb9e698901a3e6dd461c"),
+			},
+		},
+
 		"indent": {
 			{
 				fmt.Sprintf("indent(4, %#v)", Poem),
@@ -576,6 +679,25 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"jsonmergepatch": {
+			{
+				`jsonmergepatch({a = "b", c = "d"}, {a = null, e = "f"})`,
+				cty.ObjectVal(map[string]cty.Value{
+					"c": cty.StringVal("d"),
+					"e": cty.StringVal("f"),
+				}),
+			},
+		},
+
+		"jsonpatch": {
+			{
+				`jsonpatch({a = ["b"]}, [{op = "add", path = "/a/-", value = "c"}])`,
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.TupleVal([]cty.Value{cty.StringVal("b"), cty.StringVal("c")}),
+				}),
+			},
+		},
+
 		"keys": {
 			{
 				`keys({"hello"=1, "goodbye"=42})`,
@@ -701,6 +823,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"parsetime": {
+			{
+				`parsetime("DD MMM YYYY hh:mm ZZZ", "02 Jan 2006 15:04 UTC")`,
+				cty.StringVal("2006-01-02T15:04:00Z"),
+			},
+		},
+
 		"pathexpand": {
 			{
 				`pathexpand("~/test-file")`,
@@ -708,6 +837,19 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"pemdecode": {
+			{
+				`pemdecode("-----BEGIN DATA-----\naGVsbG8=\n-----END DATA-----\n")`,
+				cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"type":    cty.StringVal("DATA"),
+						"headers": cty.MapValEmpty(cty.String),
+						"bytes":   cty.StringVal("aGVsbG8="),
+					}),
+				}),
+			},
+		},
+
 		"plantimestamp": {
 			{
 				`plantimestamp()`,
@@ -722,6 +864,15 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"query": {
+			{
+				`query({"items" = [{"name" = "a", "on" = true}, {"name" = "b", "on" = false}]}, "items[?on].name")`,
+				cty.TupleVal([]cty.Value{
+					cty.StringVal("a"),
+				}),
+			},
+		},
+
 		"range": {
 			{
 				`range(3)`,
@@ -788,6 +939,41 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"rsaverify": {
+			{
+				fmt.Sprintf("rsaverify(%#v, \"hello\", %#v)", RSAPublicKey, RSASignature),
+				cty.True,
+			},
+		},
+
+		"semvercompare": {
+			{
+				`semvercompare("1.2.3", "1.10.0")`,
+				cty.NumberIntVal(-1),
+			},
+		},
+
+		"semvermatch": {
+			{
+				`semvermatch("1.4.0", "~> 1.2")`,
+				cty.True,
+			},
+		},
+
+		"semvermax": {
+			{
+				`semvermax(["1.2.0", "1.10.1", "2.0.0"], "< 2.0")`,
+				cty.StringVal("1.10.1"),
+			},
+		},
+
+		"semverparse": {
+			{
+				`semverparse("1.2.3-beta.1").prerelease`,
+				cty.StringVal("beta.1"),
+			},
+		},
+
 		"sensitive": {
 			{
 				`sensitive(1)`,
@@ -853,6 +1039,20 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"sha3_256": {
+			{
+				`sha3_256("test")`,
+				cty.StringVal("36f028580bb02cc8272a9a020f4200e346e276ae664e45ee80745574e2f5ab80"),
+			},
+		},
+
+		"sha3_512": {
+			{
+				`sha3_512("test")`,
+				cty.StringVal("9ece086e9bac491fac5c1d1046ca11d737b92a2b2ebd93f005d7b710110c0a678288166e7fbe796883a4f2e9b3ca9f484f521d0ce464345cc1aec96779149c14"),
+			},
+		},
+
 		"sha512": {
 			{
 				`sha512("test")`,
@@ -1018,6 +1218,20 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"timediff": {
+			{
+				`timediff("2017-11-23T00:00:00Z", "2017-11-22T00:00:00Z")`,
+				cty.StringVal("24h0m0s"),
+			},
+		},
+
+		"timezone": {
+			{
+				`timezone("2017-11-22T00:00:00Z", "Europe/Berlin")`,
+				cty.StringVal("2017-11-22T01:00:00+01:00"),
+			},
+		},
+
 		"title": {
 			{
 				`title("hello")`,
@@ -1051,6 +1265,25 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"tomldecode": {
+			{
+				`tomldecode("name = \"web\"\n[server]\nport = 8080\n")`,
+				cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("web"),
+					"server": cty.ObjectVal(map[string]cty.Value{
+						"port": cty.NumberIntVal(8080),
+					}),
+				}),
+			},
+		},
+
+		"tomlencode": {
+			{
+				`tomlencode({name = "web", server = {port = 8080}})`,
+				cty.StringVal("name = \"web\"\n\n[server]\nport = 8080\n"),
+			},
+		},
+
 		"tonumber": {
 			{
 				`tonumber("42")`,
@@ -1190,6 +1423,27 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"x509decode": {
+			{
+				`x509decode(file("certificate.pem")).not_after`,
+				cty.StringVal("2035-01-01T00:00:00Z"),
+			},
+		},
+
+		"xmldecode": {
+			{
+				`xmldecode("<app id=\"web\">hello</app>")`,
+				cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("app"),
+					"attributes": cty.MapVal(map[string]cty.Value{
+						"id": cty.StringVal("web"),
+					}),
+					"children": cty.EmptyTupleVal,
+					"text":     cty.StringVal("hello"),
+				}),
+			},
+		},
+
 		"yamldecode": {
 			{
 				`yamldecode("true")`,
@@ -1398,4 +1652,18 @@ Adam
 Had'em
 
 E.E. Cummings`
+	Ed25519PublicKey = `ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDtqJ7zOtqQtYqOo0CpvDXNlMhV3HeJDpjrASKGLWdop`
+	Ed25519Signature = `4lyHI9A5/o9F1snWqJF/qRvHVJE81Zb9NYpJOiGjy1kKZTe6vH3wQAq2GgVYnJw2tloUOHjLA0HU6eSEGcQ3DQ==`
+	RSAPublicKey     = `
+-----BEGIN PUBLIC KEY-----
+MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAgUElV5mwqkloIrM8ZNZ7
+2gSCcnSJt7+/Usa5G+D15YQUAdf9c1zEekTfHgDP+04nw/uFNFaE5v1RbHaPxhZY
+Vg5ZErNCa/hzn+x10xzcepeS3KPVXcxae4MR0BEegvqZqJzN9loXsNL/c3H/B+2G
+le3hTxjlWFb3F5qLgR+4Mf4ruhER1v6eHQa/nchi03MBpT4UeJ7MrL92hTJYLdpS
+yCqmr8yjxkKJDVC2uRrr+sTSxfh7r6v24u/vp/QTmBIAlNPgadVAZw17iNNb7vjV
+7Gwl/5gHXonCUKURaV++dBNLrHIZpqcAM8wHRph8mD1EfL9hsz77pHewxolBATV+
+7QIDAQAB
+-----END PUBLIC KEY-----
+`
+	RSASignature = `P4p2k0P5QVQ7bypz9dpNdbbhmme7ChLD5bmUCpy07o67K5lzIv++UCbYUOym97/K24t9PHk+ixgp/OZ89fbuZ6ECueQ1uHEsv3W3l8b/nnyOdR/V9sIF88EniekwKE9igC/7UOCWO2HxkrI8ySFQl/a6n/oqsIwl7GytyeYudGgEt75aTi6LsBhlyNWiEVao1/sKB8hETUr1ov1ILBKjQeTbyTzmHguWuR20tL9jqu6ZO6MoGioGmmv3it4onMrdJbU6KKKQxrvdLd4VoCSZrD7QrdJWHeRDXT9CXrmPX2+pq5bB1ycj6lH0zi2c5ncFlLat5PXUbYLRImeQ6IvP/A==`
 )
@@ -74,10 +74,19 @@ type Scope struct {
 	PlanTimestamp time.Time
 
 	ProviderFunctions ProviderFunction
+
+	// ModuleFunctions, if set, provides the functions declared by "function"
+	// blocks in the module that this scope belongs to.
+	ModuleFunctions ModuleFunctions
 }
 
 type ProviderFunction func(context.Context, addrs.ProviderFunction, tfdiags.SourceRange) (*function.Function, tfdiags.Diagnostics)
 
+// ModuleFunctions returns the functions declared in a module, keyed by their
+// fully-qualified names, given the built-in functions that are available to
+// their result expressions.
+type ModuleFunctions func(builtins map[string]function.Function) map[string]function.Function
+
 // SetActiveExperiments allows a caller to declare that a set of experiments
 // is active for the module that the receiving Scope belongs to, which might
 // then cause the scope to activate some additional experimental behaviors.
@@ -0,0 +1,350 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// CoverageKind describes the kind of configuration object that a
+// CoverageItem tracks.
+type CoverageKind string
+
+const (
+	CoverageResource           CoverageKind = "resource"
+	CoverageDataSource         CoverageKind = "data"
+	CoverageOutput             CoverageKind = "output"
+	CoverageVariableValidation CoverageKind = "variable_validation"
+	CoverageCheck              CoverageKind = "check"
+	CoveragePrecondition       CoverageKind = "precondition"
+	CoveragePostcondition      CoverageKind = "postcondition"
+	CoverageBranch             CoverageKind = "branch"
+)
+
+// CoverageKinds lists the kinds of coverage items in the order they should
+// be reported.
+var CoverageKinds = []CoverageKind{
+	CoverageResource,
+	CoverageDataSource,
+	CoverageOutput,
+	CoverageVariableValidation,
+	CoverageCheck,
+	CoveragePrecondition,
+	CoveragePostcondition,
+	CoverageBranch,
+}
+
+// CoverageItem is a single part of the configuration under test that can be
+// exercised by the run blocks.
+type CoverageItem struct {
+	Kind CoverageKind
+
+	// Address is the address of the configuration object the item belongs
+	// to, such as "module.child.test_resource.foo" or "var.name".
+	Address string
+
+	// Label distinguishes between multiple items that belong to the same
+	// configuration object, such as the individual preconditions of a
+	// resource or the two branches of a count argument. It is empty for
+	// items that represent the whole object.
+	Label string
+
+	DeclRange hcl.Range
+
+	// Runs is the number of run blocks that exercised this item.
+	Runs int
+
+	// module is the address of the module containing the object.
+	module addrs.Module
+}
+
+// String returns a description of the item suitable for showing to users.
+func (item CoverageItem) String() string {
+	if item.Label == "" {
+		return item.Address
+	}
+	return fmt.Sprintf("%s (%s)", item.Address, item.Label)
+}
+
+// Coverage records which parts of the configuration under test were
+// exercised by the run blocks in a test suite.
+//
+// The coverage is computed from the plans and states produced by each run
+// block. Resources, data sources and output values are exercised by any
+// run that includes at least one instance of them. Variable validations,
+// check blocks and custom conditions are exercised by any run that produced
+// a known result for the object they belong to: the check results only
+// track the aggregate status of each object, so all the conditions of an
+// object are considered exercised together. Resources and module calls that
+// use count or for_each have two branches, one for when there are no
+// instances and one for when there are some.
+//
+// Coverage is safe for concurrent use.
+type Coverage struct {
+	mu    sync.Mutex
+	items []*CoverageItem
+}
+
+// NewCoverage returns a Coverage that tracks the given configuration and all
+// of its child modules, with nothing exercised yet.
+func NewCoverage(config *configs.Config) *Coverage {
+	coverage := new(Coverage)
+	add := func(kind CoverageKind, module addrs.Module, addr fmt.Stringer, label string, rng hcl.Range) {
+		address := addr.String()
+		if !module.IsRoot() {
+			address = module.String() + "." + address
+		}
+		coverage.items = append(coverage.items, &CoverageItem{
+			Kind:      kind,
+			Address:   address,
+			Label:     label,
+			DeclRange: rng,
+			module:    module,
+		})
+	}
+	addBranches := func(module addrs.Module, addr fmt.Stringer, count, forEach hcl.Expression, rng hcl.Range) {
+		switch {
+		case count != nil:
+			add(CoverageBranch, module, addr, "count = 0", rng)
+			add(CoverageBranch, module, addr, "count > 0", rng)
+		case forEach != nil:
+			add(CoverageBranch, module, addr, "for_each empty", rng)
+			add(CoverageBranch, module, addr, "for_each not empty", rng)
+		}
+	}
+	addConditions := func(kind CoverageKind, module addrs.Module, addr fmt.Stringer, rules []*configs.CheckRule) {
+		for ix, rule := range rules {
+			add(kind, module, addr, fmt.Sprintf("%s %d", kind, ix+1), rule.DeclRange)
+		}
+	}
+
+	config.DeepEach(func(c *configs.Config) {
+		path := c.Path
+		mod := c.Module
+
+		for _, r := range mod.ManagedResources {
+			add(CoverageResource, path, r.Addr(), "", r.DeclRange)
+			addBranches(path, r.Addr(), r.Count, r.ForEach, r.DeclRange)
+			addConditions(CoveragePrecondition, path, r.Addr(), r.Preconditions)
+			addConditions(CoveragePostcondition, path, r.Addr(), r.Postconditions)
+		}
+		for _, r := range mod.DataResources {
+			add(CoverageDataSource, path, r.Addr(), "", r.DeclRange)
+			addBranches(path, r.Addr(), r.Count, r.ForEach, r.DeclRange)
+			addConditions(CoveragePrecondition, path, r.Addr(), r.Preconditions)
+			addConditions(CoveragePostcondition, path, r.Addr(), r.Postconditions)
+		}
+		for _, o := range mod.Outputs {
+			addr := addrs.OutputValue{Name: o.Name}
+			add(CoverageOutput, path, addr, "", o.DeclRange)
+			addConditions(CoveragePrecondition, path, addr, o.Preconditions)
+		}
+		for _, v := range mod.Variables {
+			addr := addrs.InputVariable{Name: v.Name}
+			for ix, rule := range v.Validations {
+				add(CoverageVariableValidation, path, addr, fmt.Sprintf("validation %d", ix+1), rule.DeclRange)
+			}
+		}
+		for _, check := range mod.Checks {
+			add(CoverageCheck, path, check.Addr(), "", check.DeclRange)
+		}
+		for _, call := range mod.ModuleCalls {
+			addBranches(path, addrs.ModuleCall{Name: call.Name}, call.Count, call.ForEach, call.DeclRange)
+		}
+	})
+
+	sort.SliceStable(coverage.items, func(i, j int) bool {
+		a, b := coverage.items[i], coverage.items[j]
+		if a.DeclRange.Filename != b.DeclRange.Filename {
+			return a.DeclRange.Filename < b.DeclRange.Filename
+		}
+		if a.DeclRange.Start.Line != b.DeclRange.Start.Line {
+			return a.DeclRange.Start.Line < b.DeclRange.Start.Line
+		}
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		return a.Label < b.Label
+	})
+	return coverage
+}
+
+// Record marks the items exercised by a single run block, given the plan it
+// produced and the state after it was applied. The state is nil for run
+// blocks that only created a plan.
+func (c *Coverage) Record(plan *plans.Plan, state *states.State) {
+	if c == nil || plan == nil {
+		return
+	}
+
+	exercised := make(map[string]bool)
+	modules := map[string]bool{addrs.RootModule.String(): true}
+	instances := make(map[string]bool)
+
+	addModule := func(module addrs.Module) {
+		for ; !module.IsRoot(); module = module.Parent() {
+			modules[module.String()] = true
+		}
+	}
+	addState := func(state *states.State) {
+		if state == nil {
+			return
+		}
+		for _, ms := range state.Modules {
+			addModule(ms.Addr.Module())
+			for _, rs := range ms.Resources {
+				if len(rs.Instances) == 0 {
+					continue
+				}
+				instances[rs.Addr.Config().String()] = true
+				addModule(rs.Addr.Module.Module())
+			}
+		}
+		for name := range state.RootModule().OutputValues {
+			exercised[coverageKey(CoverageOutput, addrs.OutputValue{Name: name}.String(), "")] = true
+		}
+	}
+	addChecks := func(results *states.CheckResults) {
+		if results == nil {
+			return
+		}
+		for _, elem := range results.ConfigResults.Elems {
+			known := false
+			for _, obj := range elem.Value.ObjectResults.Elems {
+				if obj.Value.Status != checks.StatusUnknown {
+					known = true
+					break
+				}
+			}
+			if !known {
+				continue
+			}
+			switch addr := elem.Key.(type) {
+			case addrs.ConfigResource:
+				addModule(addr.Module)
+				exercised[coverageKey(CoveragePrecondition, addr.String(), "")] = true
+				exercised[coverageKey(CoveragePostcondition, addr.String(), "")] = true
+			case addrs.ConfigOutputValue:
+				exercised[coverageKey(CoveragePrecondition, addr.String(), "")] = true
+			case addrs.ConfigInputVariable:
+				exercised[coverageKey(CoverageVariableValidation, addr.String(), "")] = true
+			case addrs.ConfigCheck:
+				exercised[coverageKey(CoverageCheck, addr.String(), "")] = true
+			}
+		}
+	}
+
+	addState(plan.PlannedState)
+	addState(state)
+	addChecks(plan.Checks)
+	if state != nil {
+		addChecks(state.CheckResults)
+	}
+	for _, change := range plan.Changes.Resources {
+		addModule(change.Addr.Module.Module())
+		if change.Action != plans.Delete && change.Action != plans.Forget {
+			instances[change.Addr.ConfigResource().String()] = true
+		}
+	}
+	for _, change := range plan.Changes.Outputs {
+		module := change.Addr.Module.Module()
+		addModule(module)
+		if change.Action != plans.Delete {
+			exercised[coverageKey(CoverageOutput, addrs.ConfigOutputValue{Module: module, OutputValue: change.Addr.OutputValue}.String(), "")] = true
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, item := range c.items {
+		if item.exercised(exercised, modules, instances) {
+			item.Runs++
+		}
+	}
+}
+
+func (item *CoverageItem) exercised(exercised, modules, instances map[string]bool) bool {
+	switch item.Kind {
+	case CoverageResource, CoverageDataSource:
+		return instances[item.Address]
+	case CoveragePrecondition, CoveragePostcondition, CoverageVariableValidation:
+		// The check results only track whole objects, so all the conditions
+		// of an object share the same result.
+		return exercised[coverageKey(item.Kind, item.Address, "")]
+	case CoverageBranch:
+		// Module calls are exercised if there are instances of the module,
+		// and resources are exercised if there are instances of the
+		// resource. In both cases we only know that the object was evaluated
+		// with no instances if the module containing it was evaluated.
+		present := instances[item.Address] || modules[item.Address]
+		if item.Label == "count > 0" || item.Label == "for_each not empty" {
+			return present
+		}
+		return !present && modules[item.module.String()]
+	default:
+		return exercised[coverageKey(item.Kind, item.Address, item.Label)]
+	}
+}
+
+func coverageKey(kind CoverageKind, address, label string) string {
+	return fmt.Sprintf("%s|%s|%s", kind, address, label)
+}
+
+// Items returns a copy of all the items tracked by the coverage, ordered by
+// their location in the configuration.
+func (c *Coverage) Items() []CoverageItem {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	items := make([]CoverageItem, len(c.items))
+	for ix, item := range c.items {
+		items[ix] = *item
+	}
+	return items
+}
+
+// CoverageTotals counts how many items of a coverage report were exercised.
+type CoverageTotals struct {
+	Exercised int
+	Total     int
+}
+
+// Percent returns the percentage of items that were exercised. A report with
+// no items is considered fully exercised.
+func (t CoverageTotals) Percent() float64 {
+	if t.Total == 0 {
+		return 100
+	}
+	return float64(t.Exercised) * 100 / float64(t.Total)
+}
+
+// Totals returns the overall totals across all the items, and the totals for
+// each kind of item that is present in the configuration.
+func (c *Coverage) Totals() (CoverageTotals, map[CoverageKind]CoverageTotals) {
+	var overall CoverageTotals
+	kinds := make(map[CoverageKind]CoverageTotals)
+	for _, item := range c.Items() {
+		totals := kinds[item.Kind]
+		totals.Total++
+		overall.Total++
+		if item.Runs > 0 {
+			totals.Exercised++
+			overall.Exercised++
+		}
+		kinds[item.Kind] = totals
+	}
+	return overall, kinds
+}
@@ -0,0 +1,121 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestCoverage(t *testing.T) {
+	resource := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_resource",
+		Name: "x",
+	}
+	config := &configs.Config{
+		Path: addrs.RootModule,
+		Module: &configs.Module{
+			ModuleCalls: map[string]*configs.ModuleCall{
+				"child": {
+					Name:    "child",
+					ForEach: hcl.StaticExpr(cty.EmptyObjectVal, hcl.Range{}),
+				},
+			},
+			Outputs: map[string]*configs.Output{
+				"out": {Name: "out"},
+			},
+		},
+		Children: map[string]*configs.Config{
+			"child": {
+				Path: addrs.Module{"child"},
+				Module: &configs.Module{
+					ManagedResources: map[string]*configs.Resource{
+						resource.String(): {
+							Mode:          resource.Mode,
+							Type:          resource.Type,
+							Name:          resource.Name,
+							Preconditions: []*configs.CheckRule{{}},
+						},
+					},
+				},
+			},
+		},
+	}
+	coverage := NewCoverage(config)
+
+	// The first run creates an instance of the child module, and evaluates
+	// the precondition of its resource.
+	module := addrs.RootModuleInstance.Child("child", addrs.StringKey("a"))
+	state := states.NewState()
+	state.EnsureModule(module).SetResourceInstanceCurrent(
+		resource.Instance(addrs.NoKey),
+		&states.ResourceInstanceObjectSrc{Status: states.ObjectReady, AttrsJSON: []byte("{}")},
+		addrs.AbsProviderConfig{
+			Module:   addrs.RootModule,
+			Provider: addrs.NewDefaultProvider("test"),
+		},
+		addrs.NoKey,
+	)
+	coverage.Record(&plans.Plan{
+		Changes:      plans.NewChanges(),
+		PlannedState: state,
+		Checks: &states.CheckResults{
+			ConfigResults: addrs.MakeMap(
+				addrs.MakeMapElem[addrs.ConfigCheckable](
+					resource.InModule(addrs.Module{"child"}),
+					&states.CheckResultAggregate{
+						Status: checks.StatusPass,
+						ObjectResults: addrs.MakeMap(
+							addrs.MakeMapElem[addrs.Checkable](
+								resource.Instance(addrs.NoKey).Absolute(module),
+								&states.CheckResultObject{Status: checks.StatusPass},
+							),
+						),
+					},
+				),
+			),
+		},
+	}, nil)
+
+	// The second run has no instances of the child module at all.
+	coverage.Record(&plans.Plan{
+		Changes:      plans.NewChanges(),
+		PlannedState: states.NewState(),
+	}, states.NewState())
+
+	got := make(map[string]int)
+	for _, item := range coverage.Items() {
+		got[item.String()] = item.Runs
+	}
+	want := map[string]int{
+		"module.child (for_each empty)":                 1,
+		"module.child (for_each not empty)":             1,
+		"output.out":                                    0,
+		"module.child.test_resource.x":                  1,
+		"module.child.test_resource.x (precondition 1)": 1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong coverage\n%s", diff)
+	}
+
+	overall, kinds := coverage.Totals()
+	if diff := cmp.Diff(CoverageTotals{Exercised: 4, Total: 5}, overall); diff != "" {
+		t.Errorf("wrong overall totals\n%s", diff)
+	}
+	if diff := cmp.Diff(CoverageTotals{Exercised: 2, Total: 2}, kinds[CoverageBranch]); diff != "" {
+		t.Errorf("wrong branch totals\n%s", diff)
+	}
+}
@@ -6,6 +6,8 @@
 package moduletest
 
 import (
+	"time"
+
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -18,5 +20,9 @@ type File struct {
 
 	Runs []*Run
 
+	// Duration is how long the test file took to execute, including the
+	// time spent cleaning up any infrastructure it created.
+	Duration time.Duration
+
 	Diagnostics tfdiags.Diagnostics
 }
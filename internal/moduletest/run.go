@@ -7,6 +7,8 @@ package moduletest
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 
@@ -28,6 +30,10 @@ type Run struct {
 	Index  int
 	Status Status
 
+	// Duration is how long the run block took to execute, or zero if it
+	// was not executed.
+	Duration time.Duration
+
 	Diagnostics tfdiags.Diagnostics
 }
 
@@ -341,3 +347,91 @@ func (run *Run) BuildExpectedFailuresAndSourceMaps() (addrs.Map[addrs.Referencea
 	}
 	return expectedFailures, sourceRanges
 }
+
+// ExpectedDiagnostics tracks which of the expect_diagnostics blocks of a run
+// block have been matched by the diagnostics produced so far.
+type ExpectedDiagnostics struct {
+	expected []*configs.TestExpectedDiagnostic
+	matched  []bool
+}
+
+// BuildExpectedDiagnostics returns the tracker for the expected diagnostics
+// of the run block, with none of them matched yet.
+func (run *Run) BuildExpectedDiagnostics() *ExpectedDiagnostics {
+	return &ExpectedDiagnostics{
+		expected: run.Config.ExpectDiagnostics,
+		matched:  make([]bool, len(run.Config.ExpectDiagnostics)),
+	}
+}
+
+// Filter removes the diagnostics that match any of the expected diagnostics
+// from the given set, and marks those expectations as matched. It also
+// returns true if any of the removed diagnostics were errors, in which case
+// the operation that produced them failed as expected and the run block
+// should not continue.
+func (e *ExpectedDiagnostics) Filter(originals tfdiags.Diagnostics) (tfdiags.Diagnostics, bool) {
+	var diags tfdiags.Diagnostics
+	var expectedError bool
+	for _, diag := range originals {
+		found := false
+		for ix, expected := range e.expected {
+			if expected.Matches(diag) {
+				e.matched[ix] = true
+				found = true
+			}
+		}
+		if !found {
+			diags = diags.Append(diag)
+			continue
+		}
+		if diag.Severity() == tfdiags.Error {
+			expectedError = true
+		}
+	}
+	return diags, expectedError
+}
+
+// Missing returns an error for each of the expected diagnostics that was not
+// matched by any of the diagnostics given to Filter.
+func (e *ExpectedDiagnostics) Missing() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for ix, expected := range e.expected {
+		if e.matched[ix] {
+			continue
+		}
+
+		var criteria []string
+		if expected.Summary != nil {
+			criteria = append(criteria, fmt.Sprintf("a summary matching %q", expected.Summary))
+		}
+		if expected.Detail != nil {
+			criteria = append(criteria, fmt.Sprintf("a detail matching %q", expected.Detail))
+		}
+		switch {
+		case expected.Filename != "" && expected.Line != 0:
+			criteria = append(criteria, fmt.Sprintf("a source at %s line %d", expected.Filename, expected.Line))
+		case expected.Filename != "":
+			criteria = append(criteria, fmt.Sprintf("a source in %s", expected.Filename))
+		case expected.Line != 0:
+			criteria = append(criteria, fmt.Sprintf("a source including line %d", expected.Line))
+		}
+		detail := fmt.Sprintf("The run block was expected to produce %s diagnostic", severityName(expected.Severity))
+		if len(criteria) > 0 {
+			detail += " with " + strings.Join(criteria, ", ")
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing expected diagnostic",
+			Detail:   detail + ", but did not.",
+			Subject:  expected.DeclRange.Ptr(),
+		})
+	}
+	return diags
+}
+
+func severityName(severity tfdiags.Severity) string {
+	if severity == tfdiags.Warning {
+		return "a warning"
+	}
+	return "an error"
+}
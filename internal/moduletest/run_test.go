@@ -6,6 +6,7 @@
 package moduletest
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -802,3 +803,69 @@ func createDiagnostics(populate func(diags tfdiags.Diagnostics) tfdiags.Diagnost
 	diags = populate(diags)
 	return diags
 }
+
+func TestRun_ExpectedDiagnostics(t *testing.T) {
+	run := Run{
+		Config: &configs.TestRun{
+			ExpectDiagnostics: []*configs.TestExpectedDiagnostic{
+				{
+					Severity: tfdiags.Error,
+					Summary:  regexp.MustCompile("^Invalid value"),
+					Filename: "main.tf",
+					Line:     2,
+				},
+				{
+					Severity: tfdiags.Warning,
+					Detail:   regexp.MustCompile("deprecated"),
+				},
+				{
+					Severity: tfdiags.Warning,
+					Summary:  regexp.MustCompile("never produced"),
+				},
+			},
+		},
+	}
+	expected := run.BuildExpectedDiagnostics()
+
+	subject := func(filename string, line int) *hcl.Range {
+		return &hcl.Range{
+			Filename: filename,
+			Start:    hcl.Pos{Line: line, Column: 1},
+			End:      hcl.Pos{Line: line + 1, Column: 1},
+		}
+	}
+
+	var input tfdiags.Diagnostics
+	input = input.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Deprecated attribute",
+		Detail:   "The attribute is deprecated.",
+	})
+	input = input.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid value for variable",
+		Subject:  subject("modules/child/main.tf", 1),
+	})
+	input = input.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid value for variable",
+		Subject:  subject("other.tf", 1),
+	})
+
+	diags, expectedError := expected.Filter(input)
+	if !expectedError {
+		t.Error("expected an error to be matched")
+	}
+	if len(diags) != 1 || diags[0].Source().Subject.Filename != "other.tf" {
+		t.Errorf("wrong remaining diagnostics: %s", diags.ErrWithWarnings())
+	}
+
+	missing := expected.Missing()
+	if len(missing) != 1 {
+		t.Fatalf("expected one missing diagnostic, got %d", len(missing))
+	}
+	want := `The run block was expected to produce a warning diagnostic with a summary matching "never produced", but did not.`
+	if diff := cmp.Diff(want, missing[0].Description().Detail); diff != "" {
+		t.Errorf("wrong detail\n%s", diff)
+	}
+}
@@ -9,4 +9,8 @@ type Suite struct {
 	Status Status
 
 	Files map[string]*File
+
+	// Coverage records which parts of the configuration under test were
+	// exercised by the suite. It is nil unless coverage was requested.
+	Coverage *Coverage
 }
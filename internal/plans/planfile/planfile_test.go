@@ -6,20 +6,25 @@
 package planfile
 
 import (
+	"errors"
 	"path/filepath"
 	"strings"
 	"syscall"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	tfversion "github.com/opentofu/opentofu/version"
@@ -108,6 +113,50 @@ func TestRoundtrip(t *testing.T) {
 		},
 	)
 
+	providerSchemasIn := map[addrs.Provider]providers.ProviderSchema{
+		addrs.NewDefaultProvider("boop"): {
+			Provider: providers.Schema{
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"region": {Type: cty.String, Optional: true},
+					},
+					BlockTypes: map[string]*configschema.NestedBlock{},
+				},
+			},
+			ResourceTypes: map[string]providers.Schema{
+				"boop_thing": {
+					Version: 2,
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"id":     {Type: cty.String, Computed: true},
+							"secret": {Type: cty.String, Optional: true, Sensitive: true},
+							"nested": {
+								NestedType: &configschema.Object{
+									Nesting: configschema.NestingList,
+									Attributes: map[string]*configschema.Attribute{
+										"value": {Type: cty.Number, Required: true},
+									},
+								},
+								Optional: true,
+							},
+						},
+						BlockTypes: map[string]*configschema.NestedBlock{},
+					},
+				},
+			},
+			DataSources:        map[string]providers.Schema{},
+			EphemeralResources: map[string]providers.Schema{},
+		},
+	}
+	provisionerSchemasIn := map[string]*configschema.Block{
+		"boop-exec": {
+			Attributes: map[string]*configschema.Attribute{
+				"command": {Type: cty.String, Required: true},
+			},
+			BlockTypes: map[string]*configschema.NestedBlock{},
+		},
+	}
+
 	planFn := filepath.Join(t.TempDir(), "tfplan")
 
 	err = Create(planFn, CreateArgs{
@@ -116,6 +165,8 @@ func TestRoundtrip(t *testing.T) {
 		StateFile:            stateFileIn,
 		Plan:                 planIn,
 		DependencyLocks:      locksIn,
+		ProviderSchemas:      providerSchemasIn,
+		ProvisionerSchemas:   provisionerSchemasIn,
 	}, encryption.PlanEncryptionDisabled())
 	if err != nil {
 		t.Fatalf("failed to create plan file: %s", err)
@@ -194,6 +245,56 @@ func TestRoundtrip(t *testing.T) {
 			t.Errorf("provider locks did not survive round-trip\n%s", diff)
 		}
 	})
+
+	t.Run("ReadProviderSchemas", func(t *testing.T) {
+		schemasOut, err := pr.ReadProviderSchemas()
+		if err != nil {
+			t.Fatalf("failed to read provider schemas: %s", err)
+		}
+		if diff := cmp.Diff(providerSchemasIn, schemasOut, ctydebug.CmpOptions); diff != "" {
+			t.Errorf("provider schemas did not survive round-trip\n%s", diff)
+		}
+	})
+
+	t.Run("ReadProvisionerSchemas", func(t *testing.T) {
+		schemasOut, err := pr.ReadProvisionerSchemas()
+		if err != nil {
+			t.Fatalf("failed to read provisioner schemas: %s", err)
+		}
+		if diff := cmp.Diff(provisionerSchemasIn, schemasOut, ctydebug.CmpOptions); diff != "" {
+			t.Errorf("provisioner schemas did not survive round-trip\n%s", diff)
+		}
+	})
+}
+
+func TestReadSchemas_notEmbedded(t *testing.T) {
+	planFn := filepath.Join(t.TempDir(), "tfplan")
+	err := Create(planFn, CreateArgs{
+		ConfigSnapshot:       configload.NewEmptySnapshot(),
+		PreviousRunStateFile: &statefile.File{State: states.NewState()},
+		StateFile:            &statefile.File{State: states.NewState()},
+		Plan: &plans.Plan{
+			Changes: plans.NewChanges(),
+			Backend: plans.Backend{
+				Type:   "local",
+				Config: plans.DynamicValue([]byte("config placeholder")),
+			},
+		},
+	}, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatalf("failed to create plan file: %s", err)
+	}
+
+	pr, err := Open(planFn, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatalf("failed to open plan file: %s", err)
+	}
+	if _, err := pr.ReadProviderSchemas(); !errors.Is(err, ErrNoSchemas) {
+		t.Errorf("wrong error for provider schemas: %v", err)
+	}
+	if _, err := pr.ReadProvisionerSchemas(); !errors.Is(err, ErrNoSchemas) {
+		t.Errorf("wrong error for provisioner schemas: %v", err)
+	}
 }
 
 func TestWrappedError(t *testing.T) {
@@ -0,0 +1,227 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planfile
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plugin6/convert"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfplugin6"
+)
+
+// The embedded schemas are stored as one file per provider or provisioner,
+// each containing a protocol buffers message from version 6 of the plugin
+// protocol, since that can represent everything our own schema model can.
+// The schemas directory itself is always written, so that we can tell the
+// difference between a plan that needed no schemas at all and a plan file
+// created without embedded schemas.
+const schemasDir = "tfschemas/"
+const providerSchemasDir = schemasDir + "providers/"
+const provisionerSchemasDir = schemasDir + "provisioners/"
+
+// ErrNoSchemas is returned by the schema-reading methods of Reader when the
+// plan file was created without any embedded schemas, such as by an older
+// version of OpenTofu.
+var ErrNoSchemas = errors.New("plan file does not include any embedded schemas")
+
+func writeSchemas(providerSchemas map[addrs.Provider]providers.ProviderSchema, provisionerSchemas map[string]*configschema.Block, zw *zip.Writer) error {
+	_, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     schemasDir,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	providerAddrs := make([]addrs.Provider, 0, len(providerSchemas))
+	for addr := range providerSchemas {
+		providerAddrs = append(providerAddrs, addr)
+	}
+	sort.Slice(providerAddrs, func(i, j int) bool {
+		return providerAddrs[i].LessThan(providerAddrs[j])
+	})
+	for _, addr := range providerAddrs {
+		msg := providerSchemaToProto(providerSchemas[addr])
+		if err := writeSchemaFile(providerSchemasDir+addr.String(), msg, zw); err != nil {
+			return fmt.Errorf("failed to write schema for %s: %w", addr, err)
+		}
+	}
+
+	names := make([]string, 0, len(provisionerSchemas))
+	for name := range provisionerSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		msg := convert.ConfigSchemaToProto(provisionerSchemas[name])
+		if err := writeSchemaFile(provisionerSchemasDir+name, msg, zw); err != nil {
+			return fmt.Errorf("failed to write schema for provisioner %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func writeSchemaFile(name string, msg proto.Message, zw *zip.Writer) error {
+	src, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// ReadProviderSchemas reads the provider schemas embedded in the plan file,
+// which cover only the parts of each provider's schema that are needed to
+// render the plan.
+//
+// If the plan file contains no embedded schemas, the returned error is
+// ErrNoSchemas.
+func (r *Reader) ReadProviderSchemas() (map[addrs.Provider]providers.ProviderSchema, error) {
+	if !r.hasSchemas() {
+		return nil, ErrNoSchemas
+	}
+
+	ret := make(map[addrs.Provider]providers.ProviderSchema)
+	for _, file := range r.zip.File {
+		if !strings.HasPrefix(file.Name, providerSchemasDir) {
+			continue
+		}
+		rawAddr := strings.TrimPrefix(file.Name, providerSchemasDir)
+		addr, diags := addrs.ParseProviderSourceString(rawAddr)
+		if diags.HasErrors() {
+			return nil, errUnusable(fmt.Errorf("invalid embedded provider schema %q: %w", rawAddr, diags.Err()))
+		}
+		var msg tfplugin6.GetProviderSchema_Response
+		if err := readSchemaFile(file, &msg); err != nil {
+			return nil, errUnusable(fmt.Errorf("failed to read embedded schema for %s: %w", addr, err))
+		}
+		ret[addr] = providerSchemaFromProto(&msg)
+	}
+	return ret, nil
+}
+
+// ReadProvisionerSchemas reads the provisioner schemas embedded in the plan
+// file.
+//
+// If the plan file contains no embedded schemas, the returned error is
+// ErrNoSchemas.
+func (r *Reader) ReadProvisionerSchemas() (map[string]*configschema.Block, error) {
+	if !r.hasSchemas() {
+		return nil, ErrNoSchemas
+	}
+
+	ret := make(map[string]*configschema.Block)
+	for _, file := range r.zip.File {
+		if !strings.HasPrefix(file.Name, provisionerSchemasDir) {
+			continue
+		}
+		name := strings.TrimPrefix(file.Name, provisionerSchemasDir)
+		var msg tfplugin6.Schema_Block
+		if err := readSchemaFile(file, &msg); err != nil {
+			return nil, errUnusable(fmt.Errorf("failed to read embedded schema for provisioner %q: %w", name, err))
+		}
+		ret[name] = convert.ProtoToConfigSchema(&msg)
+	}
+	return ret, nil
+}
+
+func (r *Reader) hasSchemas() bool {
+	for _, file := range r.zip.File {
+		if file.Name == schemasDir {
+			return true
+		}
+	}
+	return false
+}
+
+func readSchemaFile(file *zip.File, msg proto.Message) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(src, msg)
+}
+
+func providerSchemaToProto(schema providers.ProviderSchema) *tfplugin6.GetProviderSchema_Response {
+	ret := &tfplugin6.GetProviderSchema_Response{
+		Provider:                 schemaToProto(schema.Provider),
+		ResourceSchemas:          make(map[string]*tfplugin6.Schema, len(schema.ResourceTypes)),
+		DataSourceSchemas:        make(map[string]*tfplugin6.Schema, len(schema.DataSources)),
+		EphemeralResourceSchemas: make(map[string]*tfplugin6.Schema, len(schema.EphemeralResources)),
+	}
+	if schema.ProviderMeta.Block != nil {
+		ret.ProviderMeta = schemaToProto(schema.ProviderMeta)
+	}
+	for name, s := range schema.ResourceTypes {
+		ret.ResourceSchemas[name] = schemaToProto(s)
+	}
+	for name, s := range schema.DataSources {
+		ret.DataSourceSchemas[name] = schemaToProto(s)
+	}
+	for name, s := range schema.EphemeralResources {
+		ret.EphemeralResourceSchemas[name] = schemaToProto(s)
+	}
+	return ret
+}
+
+func schemaToProto(schema providers.Schema) *tfplugin6.Schema {
+	ret := &tfplugin6.Schema{
+		Version: schema.Version,
+		Block:   &tfplugin6.Schema_Block{},
+	}
+	if schema.Block != nil {
+		ret.Block = convert.ConfigSchemaToProto(schema.Block)
+	}
+	return ret
+}
+
+func providerSchemaFromProto(msg *tfplugin6.GetProviderSchema_Response) providers.ProviderSchema {
+	ret := providers.ProviderSchema{
+		ResourceTypes:      make(map[string]providers.Schema, len(msg.ResourceSchemas)),
+		DataSources:        make(map[string]providers.Schema, len(msg.DataSourceSchemas)),
+		EphemeralResources: make(map[string]providers.Schema, len(msg.EphemeralResourceSchemas)),
+	}
+	if msg.Provider != nil {
+		ret.Provider = convert.ProtoToProviderSchema(msg.Provider)
+	}
+	if msg.ProviderMeta != nil {
+		ret.ProviderMeta = convert.ProtoToProviderSchema(msg.ProviderMeta)
+	}
+	for name, s := range msg.ResourceSchemas {
+		ret.ResourceTypes[name] = convert.ProtoToProviderSchema(s)
+	}
+	for name, s := range msg.DataSourceSchemas {
+		ret.DataSources[name] = convert.ProtoToProviderSchema(s)
+	}
+	for name, s := range msg.EphemeralResourceSchemas {
+		ret.EphemeralResources[name] = convert.ProtoToEphemeralProviderSchema(s)
+	}
+	return ret
+}
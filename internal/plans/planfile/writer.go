@@ -12,10 +12,13 @@ import (
 	"os"
 	"time"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 )
 
@@ -46,6 +49,14 @@ type CreateArgs struct {
 	// checked prior to creating the plan, so we can make sure that all of the
 	// same dependencies are still available when applying the plan.
 	DependencyLocks *depsfile.Locks
+
+	// ProviderSchemas and ProvisionerSchemas, if either is set, are embedded
+	// in the plan file so that the plan can be rendered without access to
+	// the plugins that produced it. Callers should include only the parts
+	// of the schemas that are needed to render this particular plan, since
+	// complete provider schemas can be very large.
+	ProviderSchemas    map[addrs.Provider]providers.ProviderSchema
+	ProvisionerSchemas map[string]*configschema.Block
 }
 
 // Create creates a new plan file with the given filename, overwriting any
@@ -136,6 +147,14 @@ func Create(filename string, args CreateArgs, enc encryption.PlanEncryption) err
 		}
 	}
 
+	// tfschemas directory, containing the schemas needed to render the plan
+	if args.ProviderSchemas != nil || args.ProvisionerSchemas != nil {
+		err := writeSchemas(args.ProviderSchemas, args.ProvisionerSchemas, zw)
+		if err != nil {
+			return fmt.Errorf("failed to write embedded schemas: %w", err)
+		}
+	}
+
 	// Finish zip file
 	zw.Close()
 	// Encrypt payload
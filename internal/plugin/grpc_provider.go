@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/zclconf/go-cty/cty"
@@ -113,6 +114,7 @@ func (p *GRPCProvider) getProviderSchema(ctx context.Context) (resp providers.Ge
 	resp.ResourceTypes = make(map[string]providers.Schema)
 	resp.DataSources = make(map[string]providers.Schema)
 	resp.EphemeralResources = make(map[string]providers.Schema)
+	resp.ListResourceTypes = make(map[string]providers.Schema)
 	resp.Functions = make(map[string]providers.FunctionSpec)
 
 	protoResp, err := p.getProtoProviderSchema(ctx)
@@ -154,6 +156,10 @@ func (p *GRPCProvider) getProviderSchema(ctx context.Context) (resp providers.Ge
 		resp.EphemeralResources[name] = convert.ProtoToEphemeralProviderSchema(data)
 	}
 
+	for name, list := range protoResp.ListResourceSchemas {
+		resp.ListResourceTypes[name] = convert.ProtoToProviderSchema(list)
+	}
+
 	for name, fn := range protoResp.Functions {
 		resp.Functions[name] = convert.ProtoToFunctionSpec(fn)
 	}
@@ -910,6 +916,85 @@ func (p *GRPCProvider) ImportResourceState(ctx context.Context, r providers.Impo
 	return resp
 }
 
+func (p *GRPCProvider) ListResource(ctx context.Context, r providers.ListResourceRequest) (resp providers.ListResourceResponse) {
+	logger.Trace("GRPCProvider: ListResource")
+
+	schema := p.GetProviderSchema(ctx)
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	listSchema, ok := schema.ListResourceTypes[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("resource type %q does not support listing", r.TypeName))
+		return resp
+	}
+	resSchema, ok := schema.ResourceTypes[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown resource type %q", r.TypeName))
+		return resp
+	}
+	if resSchema.IdentitySchema == nil {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("no identity schema available for resource type %q", r.TypeName))
+		return resp
+	}
+
+	config, err := msgpack.Marshal(r.Config, listSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	stream, err := p.client.ListResource(ctx, &proto.ListResource_Request{
+		TypeName:              r.TypeName,
+		Config:                &proto.DynamicValue{Msgpack: config},
+		IncludeResourceObject: r.IncludeResourceObject,
+		Limit:                 r.Limit,
+	})
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+			return resp
+		}
+		resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(event.Diagnostic))
+
+		// An event that only carries diagnostics doesn't describe an object.
+		if event.Identity == nil || event.Identity.IdentityData == nil {
+			continue
+		}
+
+		result := providers.ListResourceResult{
+			DisplayName:    event.DisplayName,
+			ResourceObject: cty.NullVal(resSchema.Block.ImpliedType()),
+		}
+		result.Identity, err = decodeDynamicValue(event.Identity.IdentityData, resSchema.IdentitySchema.ImpliedType())
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(err)
+			continue
+		}
+		if event.ResourceObject != nil {
+			result.ResourceObject, err = decodeDynamicValue(event.ResourceObject, resSchema.Block.ImpliedType())
+			if err != nil {
+				resp.Diagnostics = resp.Diagnostics.Append(err)
+				continue
+			}
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp
+}
+
 func (p *GRPCProvider) MoveResourceState(ctx context.Context, r providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
 	var resp providers.MoveResourceStateResponse
 	logger.Trace("GRPCProvider: MoveResourceState")
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"slices"
 	"strings"
 	"testing"
@@ -1295,6 +1296,143 @@ func TestGRPCProvider_ImportResourceStateJSON(t *testing.T) {
 	}
 }
 
+// fakeListResourceClient returns the given events from a ListResource stream,
+// followed by io.EOF.
+type fakeListResourceClient struct {
+	grpc.ClientStream
+	events []*proto.ListResource_Event
+}
+
+func (c *fakeListResourceClient) Recv() (*proto.ListResource_Event, error) {
+	if len(c.events) == 0 {
+		return nil, io.EOF
+	}
+	event := c.events[0]
+	c.events = c.events[1:]
+	return event, nil
+}
+
+func TestGRPCProvider_ListResource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mockproto.NewMockProviderClient(ctrl)
+
+	schema := providerProtoSchema()
+	schema.ListResourceSchemas = map[string]*proto.Schema{
+		"resource": {
+			Block: &proto.Schema_Block{
+				Attributes: []*proto.Schema_Attribute{
+					{
+						Name:     "prefix",
+						Type:     []byte(`"string"`),
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+	client.EXPECT().GetProviderSchema(gomock.Any(), gomock.Any(), gomock.Any()).Return(schema, nil)
+	client.EXPECT().GetResourceIdentitySchemas(gomock.Any(), gomock.Any(), gomock.Any()).Return(&proto.GetResourceIdentitySchemas_Response{
+		IdentitySchemas: map[string]*proto.ResourceIdentitySchema{
+			"resource": {
+				IdentityAttributes: []*proto.ResourceIdentitySchema_IdentityAttribute{
+					{
+						Name:              "id",
+						Type:              []byte(`"string"`),
+						RequiredForImport: true,
+					},
+				},
+			},
+		},
+	}, nil)
+
+	client.EXPECT().ListResource(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, req *proto.ListResource_Request, _ ...grpc.CallOption) (proto.Provider_ListResourceClient, error) {
+		if !req.IncludeResourceObject {
+			t.Error("expected IncludeResourceObject to be set")
+		}
+		if got, want := req.Limit, int64(10); got != want {
+			t.Errorf("wrong limit %d; want %d", got, want)
+		}
+		return &fakeListResourceClient{
+			events: []*proto.ListResource_Event{
+				{
+					DisplayName: "first",
+					Identity: &proto.ResourceIdentityData{
+						IdentityData: &proto.DynamicValue{Msgpack: []byte("\x81\xa2id\xa3one")},
+					},
+					ResourceObject: &proto.DynamicValue{Msgpack: []byte("\x81\xa4attr\xa3foo")},
+				},
+				{
+					Diagnostic: []*proto.Diagnostic{
+						{
+							Severity: proto.Diagnostic_WARNING,
+							Summary:  "Skipped an object",
+						},
+					},
+				},
+				{
+					DisplayName: "second",
+					Identity: &proto.ResourceIdentityData{
+						IdentityData: &proto.DynamicValue{Msgpack: []byte("\x81\xa2id\xa3two")},
+					},
+				},
+			},
+		}, nil
+	})
+
+	p := newGRPCProvider(client)
+	resp := p.ListResource(t.Context(), providers.ListResourceRequest{
+		TypeName: "resource",
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"prefix": cty.StringVal("f"),
+		}),
+		IncludeResourceObject: true,
+		Limit:                 10,
+	})
+	checkDiags(t, resp.Diagnostics)
+
+	if got, want := len(resp.Diagnostics), 1; got != want {
+		t.Fatalf("wrong number of diagnostics %d; want %d", got, want)
+	}
+
+	expected := []providers.ListResourceResult{
+		{
+			DisplayName: "first",
+			Identity: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.StringVal("one"),
+			}),
+			ResourceObject: cty.ObjectVal(map[string]cty.Value{
+				"attr": cty.StringVal("foo"),
+			}),
+		},
+		{
+			DisplayName: "second",
+			Identity: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.StringVal("two"),
+			}),
+			ResourceObject: cty.NullVal(cty.Object(map[string]cty.Type{
+				"attr": cty.String,
+			})),
+		},
+	}
+	if !cmp.Equal(expected, resp.Results, typeComparer, valueComparer, equateEmpty) {
+		t.Fatal(cmp.Diff(expected, resp.Results, typeComparer, valueComparer, equateEmpty))
+	}
+}
+
+func TestGRPCProvider_ListResourceUnsupported(t *testing.T) {
+	client := mockProviderClient(t)
+	p := newGRPCProvider(client)
+
+	resp := p.ListResource(t.Context(), providers.ListResourceRequest{
+		TypeName: "resource",
+		Config:   cty.EmptyObjectVal,
+	})
+	checkDiagsHasError(t, resp.Diagnostics)
+}
+
 func TestGRPCProvider_ReadDataSource(t *testing.T) {
 	client := mockProviderClient(t)
 	p := newGRPCProvider(client)
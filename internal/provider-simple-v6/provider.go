@@ -222,6 +222,11 @@ func (s simple) ImportResourceState(context.Context, providers.ImportResourceSta
 	return resp
 }
 
+func (s simple) ListResource(context.Context, providers.ListResourceRequest) (resp providers.ListResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(errors.New("unsupported"))
+	return resp
+}
+
 func (s simple) ReadDataSource(_ context.Context, req providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
 	m := req.Config.AsValueMap()
 	m["id"] = cty.StringVal("static_id")
@@ -120,6 +120,11 @@ type Configured interface {
 	// ImportResourceState requests that the given resource be imported.
 	ImportResourceState(context.Context, ImportResourceStateRequest) ImportResourceStateResponse
 
+	// ListResource enumerates the existing remote objects of the given
+	// resource type that match the given filter configuration, for providers
+	// that declare a list schema for that type.
+	ListResource(context.Context, ListResourceRequest) ListResourceResponse
+
 	// ReadDataSource returns the data source's current state.
 	ReadDataSource(context.Context, ReadDataSourceRequest) ReadDataSourceResponse
 
@@ -190,6 +195,11 @@ type GetProviderSchemaResponse struct {
 
 	// EphemeralResources maps the ephemeral type name to that type's schema.
 	EphemeralResources map[string]Schema
+
+	// ListResourceTypes maps the name of each managed resource type that the
+	// provider can enumerate to the schema of the filter configuration that
+	// ListResource accepts for that type.
+	ListResourceTypes map[string]Schema
 }
 
 type ResourceIdentitySchema struct {
@@ -631,6 +641,49 @@ func (ir ImportedResource) AsInstanceObject() *states.ResourceInstanceObject {
 	}
 }
 
+type ListResourceRequest struct {
+	// TypeName is the name of the managed resource type to enumerate.
+	TypeName string
+
+	// Config is the filter configuration, conforming to the list schema
+	// of the resource type.
+	Config cty.Value
+
+	// IncludeResourceObject requests that the provider also return the full
+	// object for each result, and not only its identity.
+	IncludeResourceObject bool
+
+	// Limit is the maximum number of results the provider should return.
+	// Zero means that the provider may return all of the matching objects.
+	Limit int64
+}
+
+type ListResourceResponse struct {
+	// Results contains the remote objects that matched the request, in the
+	// order the provider returned them.
+	Results []ListResourceResult
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+// ListResourceResult describes a single existing remote object returned by
+// ListResource.
+type ListResourceResult struct {
+	// DisplayName is a human-readable name for the object, which might not
+	// be unique or valid as an OpenTofu identifier.
+	DisplayName string
+
+	// Identity is the resource identity of the object, conforming to the
+	// identity schema of the resource type.
+	Identity cty.Value
+
+	// ResourceObject is the full object, conforming to the resource type
+	// schema. It is null unless IncludeResourceObject was set in the request
+	// and the provider was able to return it.
+	ResourceObject cty.Value
+}
+
 type MoveResourceStateRequest struct {
 	// The address of the provider the resource is being moved from.
 	SourceProviderAddress string
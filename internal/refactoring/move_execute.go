@@ -72,17 +72,19 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 
 	log.Printf("[TRACE] refactoring.ApplyMoves: Processing 'moved' statements in the configuration\n%s", logging.Indent(g.String()))
 
-	recordOldAddr := func(oldAddr, newAddr addrs.AbsResourceInstance) {
+	recordOldAddr := func(oldAddr, newAddr addrs.AbsResourceInstance, oldProvider addrs.AbsProviderConfig) {
 		if prevMove, exists := ret.Changes.GetOk(oldAddr); exists {
 			// If the old address was _already_ the result of a move then
 			// we'll replace that entry so that our results summarize a chain
 			// of moves into a single entry.
 			ret.Changes.Remove(oldAddr)
 			oldAddr = prevMove.From
+			oldProvider = prevMove.FromProvider
 		}
 		ret.Changes.Put(newAddr, MoveSuccess{
-			From: oldAddr,
-			To:   newAddr,
+			From:         oldAddr,
+			To:           newAddr,
+			FromProvider: oldProvider,
 		})
 	}
 	recordBlockage := func(newAddr, wantedAddr addrs.AbsMoveable) {
@@ -123,7 +125,7 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 						for key := range rs.Instances {
 							oldInst := relAddr.Instance(key).Absolute(modAddr)
 							newInst := relAddr.Instance(key).Absolute(newAddr)
-							recordOldAddr(oldInst, newInst)
+							recordOldAddr(oldInst, newInst, rs.ProviderConfig)
 						}
 					}
 
@@ -157,7 +159,7 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 						for key := range rs.Instances {
 							oldInst := rAddr.Instance(key)
 							newInst := newAddr.Instance(key)
-							recordOldAddr(oldInst, newInst)
+							recordOldAddr(oldInst, newInst, rs.ProviderConfig)
 						}
 						state.MoveAbsResource(rAddr, newAddr)
 						continue
@@ -176,7 +178,7 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 								continue
 							}
 
-							recordOldAddr(iAddr, newAddr)
+							recordOldAddr(iAddr, newAddr, rs.ProviderConfig)
 
 							state.MoveAbsResourceInstance(iAddr, newAddr)
 							continue
@@ -319,6 +321,10 @@ func makeMoveResults() MoveResults {
 type MoveSuccess struct {
 	From addrs.AbsResourceInstance
 	To   addrs.AbsResourceInstance
+
+	// FromProvider is the provider configuration that managed the object at
+	// the From address before it moved.
+	FromProvider addrs.AbsProviderConfig
 }
 
 type MoveBlocked struct {
@@ -85,8 +85,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("foo.to"), MoveSuccess{
-						From: mustParseInstAddr("foo.from"),
-						To:   mustParseInstAddr("foo.to"),
+						From:         mustParseInstAddr("foo.from"),
+						To:           mustParseInstAddr("foo.to"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -113,8 +114,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("foo.from[0]"),
-						To:   mustParseInstAddr("foo.to[0]"),
+						From:         mustParseInstAddr("foo.from[0]"),
+						To:           mustParseInstAddr("foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -142,8 +144,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("foo.to"), MoveSuccess{
-						From: mustParseInstAddr("foo.from"),
-						To:   mustParseInstAddr("foo.to"),
+						From:         mustParseInstAddr("foo.from"),
+						To:           mustParseInstAddr("foo.to"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -171,8 +174,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.boo.foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("foo.from[0]"),
-						To:   mustParseInstAddr("module.boo.foo.to[0]"),
+						From:         mustParseInstAddr("foo.from[0]"),
+						To:           mustParseInstAddr("module.boo.foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -200,8 +204,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from[0]"),
-						To:   mustParseInstAddr("module.bar[0].foo.to[0]"),
+						From:         mustParseInstAddr("module.boo.foo.from[0]"),
+						To:           mustParseInstAddr("module.bar[0].foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -238,12 +243,14 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar.foo.from"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from"),
-						To:   mustParseInstAddr("module.bar.foo.from"),
+						From:         mustParseInstAddr("module.boo.foo.from"),
+						To:           mustParseInstAddr("module.bar.foo.from"),
+						FromProvider: providerAddr,
 					}),
 					addrs.MakeMapElem(mustParseInstAddr("module.bar.module.hoo.foo.from"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.module.hoo.foo.from"),
-						To:   mustParseInstAddr("module.bar.module.hoo.foo.from"),
+						From:         mustParseInstAddr("module.boo.module.hoo.foo.from"),
+						To:           mustParseInstAddr("module.bar.module.hoo.foo.from"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -272,8 +279,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.from[0]"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from[0]"),
-						To:   mustParseInstAddr("module.bar[0].foo.from[0]"),
+						From:         mustParseInstAddr("module.boo.foo.from[0]"),
+						To:           mustParseInstAddr("module.bar[0].foo.from[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -302,8 +310,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from[0]"),
-						To:   mustParseInstAddr("module.bar[0].foo.to[0]"),
+						From:         mustParseInstAddr("module.boo.foo.from[0]"),
+						To:           mustParseInstAddr("module.bar[0].foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -332,8 +341,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from[0]"),
-						To:   mustParseInstAddr("module.bar[0].foo.to[0]"),
+						From:         mustParseInstAddr("module.boo.foo.from[0]"),
+						To:           mustParseInstAddr("module.bar[0].foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -493,8 +503,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from"),
-						To:   mustParseInstAddr("module.bar[0].foo.to"),
+						From:         mustParseInstAddr("module.boo.foo.from"),
+						To:           mustParseInstAddr("module.bar[0].foo.to"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -534,10 +545,12 @@ func TestApplyMoves(t *testing.T) {
 					addrs.MakeMapElem(mustParseInstAddr("module.boo.foo.from"), MoveSuccess{
 						mustParseInstAddr("foo.from"),
 						mustParseInstAddr("module.boo.foo.from"),
+						providerAddr,
 					}),
 					addrs.MakeMapElem(mustParseInstAddr("module.boo.foo.to"), MoveSuccess{
 						mustParseInstAddr("module.bar[0].foo.to"),
 						mustParseInstAddr("module.boo.foo.to"),
+						providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -579,10 +592,12 @@ func TestApplyMoves(t *testing.T) {
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to"), MoveSuccess{
 						mustParseInstAddr("foo.from"),
 						mustParseInstAddr("module.bar[0].foo.to"),
+						providerAddr,
 					}),
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].bar.to"), MoveSuccess{
 						mustParseInstAddr("bar.from"),
 						mustParseInstAddr("module.bar[0].bar.to"),
+						providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -623,6 +638,7 @@ func TestApplyMoves(t *testing.T) {
 					addrs.MakeMapElem(mustParseInstAddr("module.boo.foo.from"), MoveSuccess{
 						mustParseInstAddr("module.bar[0].foo.from"),
 						mustParseInstAddr("module.boo.foo.from"),
+						providerAddr,
 					}),
 				),
 				Blocked: addrs.MakeMap(
@@ -0,0 +1,300 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactoring
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// MoveCandidate is a resource instance that might be one end of a move that
+// the configuration doesn't declare yet, along with the object value that
+// we'll compare against the candidates for the other end.
+//
+// For an orphaned instance the value is the object from the prior state, and
+// for an added instance it's the planned new object, which may contain
+// unknown values for attributes that the provider will decide during apply.
+type MoveCandidate struct {
+	Addr  addrs.AbsResourceInstance
+	Value cty.Value
+}
+
+// SuggestedMove is a move statement that SuggestMoves proposes, along with
+// an explanation of why it was proposed.
+//
+// From and To are either both addrs.AbsResourceInstance or both
+// addrs.AbsResource, the latter when all of the instances of one resource
+// are moving to the instances of another resource with the same keys.
+type SuggestedMove struct {
+	From addrs.AbsMoveable
+	To   addrs.AbsMoveable
+
+	// Confidence is a score between zero and one describing how closely the
+	// two objects match. For a whole-resource move it's the lowest
+	// confidence of any of the individual instances.
+	Confidence float64
+
+	// Reasons are short human-readable phrases explaining the score.
+	Reasons []string
+}
+
+// Weights for the different signals that contribute to the confidence score
+// of a possible move. The attribute values dominate because they are the
+// best evidence we have that two objects represent the same remote object.
+const (
+	suggestAttrWeight = 0.8
+	suggestNameWeight = 0.1
+	suggestKeyWeight  = 0.1
+)
+
+// SuggestMoves compares orphaned resource instances, which exist in the prior
+// state but are no longer declared in the configuration, with added resource
+// instances, which are declared in the configuration but not yet tracked in
+// the state, and proposes move statements for pairs that seem to represent
+// the same object.
+//
+// Only pairs of the same resource type are considered, and each orphaned or
+// added instance appears in at most one suggestion. Pairs with a confidence
+// lower than minConfidence are discarded.
+//
+// The result is ordered by the "from" address so that callers can present
+// it in a stable order.
+func SuggestMoves(orphaned, added []MoveCandidate, minConfidence float64) []SuggestedMove {
+	type pairing struct {
+		from, to   MoveCandidate
+		confidence float64
+		reasons    []string
+	}
+
+	var pairings []pairing
+	for _, from := range orphaned {
+		for _, to := range added {
+			confidence, reasons := scoreMove(from, to)
+			if confidence <= 0 || confidence < minConfidence {
+				continue
+			}
+			pairings = append(pairings, pairing{from, to, confidence, reasons})
+		}
+	}
+
+	// We assign greedily from the best match downwards, breaking ties by
+	// address so that the result doesn't depend on the input order.
+	sort.SliceStable(pairings, func(i, j int) bool {
+		if pairings[i].confidence != pairings[j].confidence {
+			return pairings[i].confidence > pairings[j].confidence
+		}
+		if a, b := pairings[i].from.Addr.String(), pairings[j].from.Addr.String(); a != b {
+			return a < b
+		}
+		return pairings[i].to.Addr.String() < pairings[j].to.Addr.String()
+	})
+
+	usedFrom := addrs.MakeSet[addrs.AbsResourceInstance]()
+	usedTo := addrs.MakeSet[addrs.AbsResourceInstance]()
+	var instanceMoves []SuggestedMove
+	for _, p := range pairings {
+		if usedFrom.Has(p.from.Addr) || usedTo.Has(p.to.Addr) {
+			continue
+		}
+		usedFrom.Add(p.from.Addr)
+		usedTo.Add(p.to.Addr)
+		instanceMoves = append(instanceMoves, SuggestedMove{
+			From:       p.from.Addr,
+			To:         p.to.Addr,
+			Confidence: p.confidence,
+			Reasons:    p.reasons,
+		})
+	}
+
+	ret := collapseResourceMoves(instanceMoves, orphaned, added)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].From.String() < ret[j].From.String()
+	})
+	return ret
+}
+
+// collapseResourceMoves replaces the instance moves between two resources
+// with a single whole-resource move when every instance of the orphaned
+// resource moves to the instance of the added resource with the same key,
+// which is what happens when a resource is renamed or moved into a module.
+func collapseResourceMoves(moves []SuggestedMove, orphaned, added []MoveCandidate) []SuggestedMove {
+	type resourcePair struct {
+		from, to string
+	}
+
+	instanceCount := make(map[string]int)
+	for _, c := range orphaned {
+		instanceCount[c.Addr.ContainingResource().String()]++
+	}
+	for _, c := range added {
+		instanceCount[c.Addr.ContainingResource().String()]++
+	}
+
+	groups := make(map[resourcePair][]SuggestedMove)
+	var order []resourcePair
+	for _, move := range moves {
+		from := move.From.(addrs.AbsResourceInstance)
+		to := move.To.(addrs.AbsResourceInstance)
+		pair := resourcePair{from.ContainingResource().String(), to.ContainingResource().String()}
+		if _, exists := groups[pair]; !exists {
+			order = append(order, pair)
+		}
+		groups[pair] = append(groups[pair], move)
+	}
+
+	var ret []SuggestedMove
+	for _, pair := range order {
+		group := groups[pair]
+		collapse := len(group) == instanceCount[pair.from] && len(group) == instanceCount[pair.to]
+		for _, move := range group {
+			if move.From.(addrs.AbsResourceInstance).Resource.Key != move.To.(addrs.AbsResourceInstance).Resource.Key {
+				collapse = false
+				break
+			}
+		}
+		if !collapse {
+			ret = append(ret, group...)
+			continue
+		}
+
+		confidence := group[0].Confidence
+		for _, move := range group[1:] {
+			confidence = min(confidence, move.Confidence)
+		}
+		reasons := []string{
+			fmt.Sprintf("all %d %s of %s match by key", len(group), pluralInstances(len(group)), pair.from),
+		}
+		if len(group) == 1 {
+			// With only one instance the instance-level explanation is
+			// still the most useful one.
+			reasons = group[0].Reasons
+		}
+		ret = append(ret, SuggestedMove{
+			From:       group[0].From.(addrs.AbsResourceInstance).ContainingResource(),
+			To:         group[0].To.(addrs.AbsResourceInstance).ContainingResource(),
+			Confidence: confidence,
+			Reasons:    reasons,
+		})
+	}
+	return ret
+}
+
+// scoreMove returns the confidence that the orphaned instance "from" and the
+// added instance "to" represent the same object, along with the reasons for
+// that score. It returns zero for pairs that can never be a valid move.
+func scoreMove(from, to MoveCandidate) (float64, []string) {
+	fromRes := from.Addr.Resource.Resource
+	toRes := to.Addr.Resource.Resource
+	if fromRes.Mode != addrs.ManagedResourceMode || toRes.Mode != addrs.ManagedResourceMode {
+		return 0, nil
+	}
+	if fromRes.Type != toRes.Type {
+		return 0, nil
+	}
+
+	matched, compared := compareObjectAttrs(from.Value, to.Value)
+	if compared == 0 {
+		// Without any attribute values to compare there's nothing to tell
+		// this pair apart from any other pair of the same type.
+		return 0, nil
+	}
+
+	var reasons []string
+	confidence := suggestAttrWeight * float64(matched) / float64(compared)
+	reasons = append(reasons, fmt.Sprintf("%d of %d known attribute values match", matched, compared))
+
+	if fromRes.Name == toRes.Name {
+		confidence += suggestNameWeight
+		reasons = append(reasons, "same resource name")
+	}
+	if from.Addr.Resource.Key == to.Addr.Resource.Key {
+		confidence += suggestKeyWeight
+		if from.Addr.Resource.Key != addrs.NoKey {
+			reasons = append(reasons, "same instance key")
+		}
+	} else if instanceKeysEquivalent(from.Addr.Resource.Key, to.Addr.Resource.Key) {
+		// A count index that became an equivalent for_each key, such as
+		// [0] becoming ["0"], is almost as good as the same key.
+		confidence += suggestKeyWeight / 2
+		reasons = append(reasons, "equivalent instance key")
+	}
+
+	return confidence, reasons
+}
+
+// compareObjectAttrs counts how many of the top-level attributes that are
+// known and non-null in both objects have equal values in each.
+func compareObjectAttrs(from, to cty.Value) (matched, compared int) {
+	from, _ = from.UnmarkDeep()
+	to, _ = to.UnmarkDeep()
+	if !from.Type().IsObjectType() || !to.Type().IsObjectType() {
+		return 0, 0
+	}
+	if from.IsNull() || to.IsNull() || !from.IsKnown() || !to.IsKnown() {
+		return 0, 0
+	}
+
+	for name := range to.Type().AttributeTypes() {
+		if !from.Type().HasAttribute(name) {
+			continue
+		}
+		fromAttr := from.GetAttr(name)
+		toAttr := to.GetAttr(name)
+		if fromAttr.IsNull() || toAttr.IsNull() {
+			continue
+		}
+		if !fromAttr.IsWhollyKnown() || !toAttr.IsWhollyKnown() {
+			continue
+		}
+		compared++
+		if fromAttr.Equals(toAttr).True() {
+			matched++
+		}
+	}
+	return matched, compared
+}
+
+// instanceKeysEquivalent returns true if the two keys are of different types
+// but have the same string representation, as happens when a resource
+// switches from count to for_each over a set of numbers.
+func instanceKeysEquivalent(a, b addrs.InstanceKey) bool {
+	if a == addrs.NoKey || b == addrs.NoKey {
+		return false
+	}
+	return strings.Trim(a.String(), `[]"`) == strings.Trim(b.String(), `[]"`)
+}
+
+func pluralInstances(n int) string {
+	if n == 1 {
+		return "instance"
+	}
+	return "instances"
+}
+
+// FormatSuggestedMoves renders the given suggestions as moved blocks, each
+// preceded by a comment explaining its confidence score, suitable for writing
+// into a new configuration file in the root module.
+func FormatSuggestedMoves(moves []SuggestedMove) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# __generated__ by OpenTofu\n")
+	buf.WriteString("# Please review these moved blocks before applying them.\n")
+	for _, move := range moves {
+		buf.WriteString("\n")
+		fmt.Fprintf(&buf, "# Confidence: %.0f%% (%s)\n", move.Confidence*100, strings.Join(move.Reasons, ", "))
+		buf.WriteString("moved {\n")
+		fmt.Fprintf(&buf, "  from = %s\n", move.From)
+		fmt.Fprintf(&buf, "  to = %s\n", move.To)
+		buf.WriteString("}\n")
+	}
+	return hclwrite.Format(buf.Bytes())
+}
@@ -0,0 +1,279 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactoring
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestSuggestMoves(t *testing.T) {
+	mustParseInstAddr := func(s string) addrs.AbsResourceInstance {
+		addr, err := addrs.ParseAbsResourceInstanceStr(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr
+	}
+	candidate := func(addr string, attrs map[string]cty.Value) MoveCandidate {
+		return MoveCandidate{
+			Addr:  mustParseInstAddr(addr),
+			Value: cty.ObjectVal(attrs),
+		}
+	}
+
+	// want is a simplified representation of a SuggestedMove that's easier
+	// to compare in the test table.
+	type want struct {
+		From, To   string
+		Confidence float64
+		Reasons    []string
+	}
+
+	tests := map[string]struct {
+		Orphaned      []MoveCandidate
+		Added         []MoveCandidate
+		MinConfidence float64
+		Want          []want
+	}{
+		"nothing to compare": {
+			Want: nil,
+		},
+		"rename": {
+			Orphaned: []MoveCandidate{
+				candidate("test_instance.a", map[string]cty.Value{
+					"id":   cty.StringVal("i-123"),
+					"size": cty.StringVal("large"),
+				}),
+			},
+			Added: []MoveCandidate{
+				candidate("test_instance.b", map[string]cty.Value{
+					"id":   cty.UnknownVal(cty.String),
+					"size": cty.StringVal("large"),
+				}),
+			},
+			Want: []want{
+				{
+					From:       "test_instance.a",
+					To:         "test_instance.b",
+					Confidence: 0.9,
+					Reasons:    []string{"1 of 1 known attribute values match"},
+				},
+			},
+		},
+		"wrapped into a module": {
+			Orphaned: []MoveCandidate{
+				candidate("test_instance.a[0]", map[string]cty.Value{
+					"size": cty.StringVal("large"),
+				}),
+				candidate("test_instance.a[1]", map[string]cty.Value{
+					"size": cty.StringVal("small"),
+				}),
+			},
+			Added: []MoveCandidate{
+				candidate("module.child.test_instance.a[1]", map[string]cty.Value{
+					"size": cty.StringVal("small"),
+				}),
+				candidate("module.child.test_instance.a[0]", map[string]cty.Value{
+					"size": cty.StringVal("large"),
+				}),
+			},
+			Want: []want{
+				{
+					From:       "test_instance.a",
+					To:         "module.child.test_instance.a",
+					Confidence: 1,
+					Reasons:    []string{"all 2 instances of test_instance.a match by key"},
+				},
+			},
+		},
+		"count to for_each": {
+			Orphaned: []MoveCandidate{
+				candidate("test_instance.a[0]", map[string]cty.Value{
+					"name": cty.StringVal("blue"),
+				}),
+				candidate("test_instance.a[1]", map[string]cty.Value{
+					"name": cty.StringVal("green"),
+				}),
+			},
+			Added: []MoveCandidate{
+				candidate(`test_instance.a["blue"]`, map[string]cty.Value{
+					"name": cty.StringVal("blue"),
+				}),
+				candidate(`test_instance.a["green"]`, map[string]cty.Value{
+					"name": cty.StringVal("green"),
+				}),
+			},
+			Want: []want{
+				{
+					From:       "test_instance.a[0]",
+					To:         `test_instance.a["blue"]`,
+					Confidence: 0.9,
+					Reasons:    []string{"1 of 1 known attribute values match", "same resource name"},
+				},
+				{
+					From:       "test_instance.a[1]",
+					To:         `test_instance.a["green"]`,
+					Confidence: 0.9,
+					Reasons:    []string{"1 of 1 known attribute values match", "same resource name"},
+				},
+			},
+		},
+		"equivalent keys": {
+			Orphaned: []MoveCandidate{
+				candidate("test_instance.a[0]", map[string]cty.Value{
+					"name": cty.StringVal("x"),
+				}),
+			},
+			Added: []MoveCandidate{
+				candidate(`test_instance.a["0"]`, map[string]cty.Value{
+					"name": cty.StringVal("x"),
+				}),
+			},
+			Want: []want{
+				{
+					From:       "test_instance.a[0]",
+					To:         `test_instance.a["0"]`,
+					Confidence: 0.95,
+					Reasons:    []string{"1 of 1 known attribute values match", "same resource name", "equivalent instance key"},
+				},
+			},
+		},
+		"different types never match": {
+			Orphaned: []MoveCandidate{
+				candidate("test_instance.a", map[string]cty.Value{
+					"size": cty.StringVal("large"),
+				}),
+			},
+			Added: []MoveCandidate{
+				candidate("test_other.a", map[string]cty.Value{
+					"size": cty.StringVal("large"),
+				}),
+			},
+			Want: nil,
+		},
+		"below minimum confidence": {
+			Orphaned: []MoveCandidate{
+				candidate("test_instance.a", map[string]cty.Value{
+					"size": cty.StringVal("large"),
+					"zone": cty.StringVal("a"),
+				}),
+			},
+			Added: []MoveCandidate{
+				candidate("test_instance.b", map[string]cty.Value{
+					"size": cty.StringVal("small"),
+					"zone": cty.StringVal("a"),
+				}),
+			},
+			MinConfidence: 0.6,
+			Want:          nil,
+		},
+		"best match wins": {
+			Orphaned: []MoveCandidate{
+				candidate("test_instance.old", map[string]cty.Value{
+					"size": cty.StringVal("large"),
+					"zone": cty.StringVal("a"),
+				}),
+			},
+			Added: []MoveCandidate{
+				candidate("test_instance.partial", map[string]cty.Value{
+					"size": cty.StringVal("small"),
+					"zone": cty.StringVal("a"),
+				}),
+				candidate("test_instance.exact", map[string]cty.Value{
+					"size": cty.StringVal("large"),
+					"zone": cty.StringVal("a"),
+				}),
+			},
+			Want: []want{
+				{
+					From:       "test_instance.old",
+					To:         "test_instance.exact",
+					Confidence: 0.9,
+					Reasons:    []string{"2 of 2 known attribute values match"},
+				},
+			},
+		},
+		"unknown and null values are ignored": {
+			Orphaned: []MoveCandidate{
+				candidate("test_instance.a", map[string]cty.Value{
+					"id":   cty.StringVal("i-123"),
+					"tags": cty.NullVal(cty.Map(cty.String)),
+				}),
+			},
+			Added: []MoveCandidate{
+				candidate("test_instance.b", map[string]cty.Value{
+					"id":   cty.UnknownVal(cty.String),
+					"tags": cty.MapValEmpty(cty.String),
+				}),
+			},
+			Want: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			moves := SuggestMoves(test.Orphaned, test.Added, test.MinConfidence)
+
+			var got []want
+			for _, move := range moves {
+				got = append(got, want{
+					From:       move.From.String(),
+					To:         move.To.String(),
+					Confidence: move.Confidence,
+					Reasons:    move.Reasons,
+				})
+			}
+			roundConfidence := cmp.Transformer("round", func(f float64) int64 {
+				return int64(f*1000 + 0.5)
+			})
+			if diff := cmp.Diff(test.Want, got, roundConfidence); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatSuggestedMoves(t *testing.T) {
+	moves := []SuggestedMove{
+		{
+			From:       addrs.RootModuleInstance.Resource(addrs.ManagedResourceMode, "test_instance", "a"),
+			To:         addrs.RootModuleInstance.Child("child", addrs.NoKey).Resource(addrs.ManagedResourceMode, "test_instance", "a"),
+			Confidence: 1,
+			Reasons:    []string{"all 2 instances of test_instance.a match by key"},
+		},
+		{
+			From:       addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "b", addrs.IntKey(0)),
+			To:         addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "b", addrs.StringKey("blue")),
+			Confidence: 0.9,
+			Reasons:    []string{"1 of 1 known attribute values match", "same resource name"},
+		},
+	}
+
+	got := string(FormatSuggestedMoves(moves))
+	want := `# __generated__ by OpenTofu
+# Please review these moved blocks before applying them.
+
+# Confidence: 100% (all 2 instances of test_instance.a match by key)
+moved {
+  from = test_instance.a
+  to   = module.child.test_instance.a
+}
+
+# Confidence: 90% (1 of 1 known attribute values match, same resource name)
+moved {
+  from = test_instance.b[0]
+  to   = test_instance.b["blue"]
+}
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
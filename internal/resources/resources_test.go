@@ -125,6 +125,11 @@ func (f *fakeProviderClient) ImportResourceState(context.Context, providers.Impo
 	panic("unimplemented")
 }
 
+// ListResource implements [providers.Interface].
+func (f *fakeProviderClient) ListResource(context.Context, providers.ListResourceRequest) providers.ListResourceResponse {
+	panic("unimplemented")
+}
+
 // MoveResourceState implements [providers.Interface].
 func (f *fakeProviderClient) MoveResourceState(context.Context, providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
 	panic("unimplemented")
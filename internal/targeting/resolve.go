@@ -0,0 +1,242 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package targeting
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Resolve finds all of the resources in the given configuration and prior
+// state that are matched by any of the given selectors, returning their
+// addresses in a form suitable for use as -target or -exclude addresses.
+//
+// Resources that are declared in the configuration are returned as
+// addrs.ConfigResource, so that they select every instance of the resource
+// across all module instances, including instances that don't exist yet.
+// Resources that are only present in the prior state are returned as
+// addrs.AbsResource.
+//
+// Glob selectors with concrete instance keys, like module.app["a"].aws_*,
+// must only select the matching instances, so they are evaluated against
+// the prior state and return addrs.AbsResource addresses for whole resources
+// or addrs.AbsResourceInstance addresses for individual instances. Tag
+// selectors can also only be evaluated against prior state, and so they
+// return addrs.AbsResourceInstance addresses.
+//
+// The flag argument is the name of the command line option the selectors
+// came from, used only for diagnostic messages. Resolve returns a warning for
+// each selector that doesn't match anything. Either config or state may be
+// nil, in which case they are treated as empty.
+func Resolve(selectors []Selector, config *configs.Config, state *states.State, flag string) ([]addrs.Targetable, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if len(selectors) == 0 {
+		return nil, diags
+	}
+
+	candidates := resolveCandidates(config, state)
+
+	seen := make(map[string]struct{})
+	var ret []addrs.Targetable
+	for _, selector := range selectors {
+		var matched []addrs.Targetable
+		switch {
+		case selector.Kind == TagSelector:
+			matched = selector.matchTags(state)
+		case selector.Kind == GlobSelector && selector.concreteKeys:
+			matched = selector.matchInstances(state)
+		default:
+			for _, c := range candidates {
+				if selector.matchCandidate(c) {
+					matched = append(matched, c.addr)
+				}
+			}
+		}
+
+		if len(matched) == 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				fmt.Sprintf("No resources match %s selector", flag),
+				fmt.Sprintf("The selector %q given in the -%s option does not match any resource in the configuration or the current state.", selector.Raw, flag),
+			))
+			continue
+		}
+
+		for _, addr := range matched {
+			key := addr.String()
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+			ret = append(ret, addr)
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].String() < ret[j].String()
+	})
+	return ret, diags
+}
+
+// candidate is a resource that a selector might match, along with the
+// metadata that the various selector kinds need to decide.
+type candidate struct {
+	addr     addrs.Targetable
+	mode     addrs.ResourceMode
+	typeName string
+	provider addrs.Provider
+}
+
+// resolveCandidates returns all of the resources declared in the given
+// configuration, followed by any resources in the given state that are not
+// already covered by a resource in the configuration.
+func resolveCandidates(config *configs.Config, state *states.State) []candidate {
+	var ret []candidate
+	declared := make(map[string]struct{})
+
+	if config != nil {
+		config.DeepEach(func(c *configs.Config) {
+			if c.Module == nil {
+				return
+			}
+			for _, rcs := range []map[string]*configs.Resource{c.Module.ManagedResources, c.Module.DataResources} {
+				for _, rc := range rcs {
+					addr := rc.Addr().InModule(c.Path)
+					declared[addr.String()] = struct{}{}
+					ret = append(ret, candidate{
+						addr:     addr,
+						mode:     rc.Mode,
+						typeName: rc.Type,
+						provider: rc.Provider,
+					})
+				}
+			}
+		})
+	}
+
+	if state != nil {
+		for _, ms := range state.Modules {
+			for _, rs := range ms.Resources {
+				if _, exists := declared[rs.Addr.Config().String()]; exists {
+					continue
+				}
+				ret = append(ret, candidate{
+					addr:     rs.Addr,
+					mode:     rs.Addr.Resource.Mode,
+					typeName: rs.Addr.Resource.Type,
+					provider: rs.ProviderConfig.Provider,
+				})
+			}
+		}
+	}
+
+	return ret
+}
+
+func (s Selector) matchCandidate(c candidate) bool {
+	switch s.Kind {
+	case GlobSelector:
+		if s.pattern.MatchString(c.addr.String()) {
+			return true
+		}
+		// A configuration resource has no instance keys in its address, but
+		// a glob that selects instance keys with [*] should still match
+		// it if there are not yet any instances in the state.
+		if cr, ok := c.addr.(addrs.ConfigResource); ok {
+			placeholder := placeholderInstances(cr)
+			return s.pattern.MatchString(placeholder.ContainingResource().String()) || s.pattern.MatchString(placeholder.String())
+		}
+		return false
+	case ProviderSelector:
+		return c.provider.Equals(s.Provider)
+	case TypeSelector:
+		return c.mode == s.Mode && c.typeName == s.Type
+	default:
+		return false
+	}
+}
+
+// placeholderInstances returns an instance of the given configuration
+// resource where each module call and the resource itself have a placeholder
+// instance key, so that glob patterns written against instance addresses can
+// match against a resource that has no instances yet.
+func placeholderInstances(addr addrs.ConfigResource) addrs.AbsResourceInstance {
+	var path addrs.ModuleInstance
+	for _, name := range addr.Module {
+		path = path.Child(name, addrs.StringKey("*"))
+	}
+	return addr.Resource.Instance(addrs.StringKey("*")).Absolute(path)
+}
+
+// matchInstances returns the addresses of the resources and resource
+// instances in the given state that the receiving glob selector matches. A
+// whole resource is returned as an addrs.AbsResource if the selector matches
+// its address, and otherwise each matching instance is returned as an
+// addrs.AbsResourceInstance.
+func (s Selector) matchInstances(state *states.State) []addrs.Targetable {
+	if state == nil {
+		return nil
+	}
+
+	var ret []addrs.Targetable
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			if s.pattern.MatchString(rs.Addr.String()) {
+				ret = append(ret, rs.Addr)
+				continue
+			}
+			for key := range rs.Instances {
+				if addr := rs.Addr.Instance(key); s.pattern.MatchString(addr.String()) {
+					ret = append(ret, addr)
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// matchTags returns the addresses of all of the resource instances in the
+// given state whose current object has a "tags" attribute matching the
+// receiving tag selector.
+func (s Selector) matchTags(state *states.State) []addrs.Targetable {
+	if state == nil {
+		return nil
+	}
+
+	var ret []addrs.Targetable
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key, is := range rs.Instances {
+				if is.Current == nil || len(is.Current.AttrsJSON) == 0 {
+					continue
+				}
+				var attrs struct {
+					Tags map[string]any `json:"tags"`
+				}
+				if err := json.Unmarshal(is.Current.AttrsJSON, &attrs); err != nil {
+					continue
+				}
+				value, exists := attrs.Tags[s.TagKey]
+				if !exists {
+					continue
+				}
+				if s.TagValue != nil {
+					if str, ok := value.(string); !ok || str != *s.TagValue {
+						continue
+					}
+				}
+				ret = append(ret, rs.Addr.Instance(key))
+			}
+		}
+	}
+	return ret
+}
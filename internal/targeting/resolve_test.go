@@ -0,0 +1,256 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package targeting
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestResolve(t *testing.T) {
+	config, state := testResolveFixture()
+
+	tests := map[string]struct {
+		selectors []string
+		want      []string
+		wantWarns int
+	}{
+		"glob over module instances": {
+			selectors: []string{"module.app[*].aws_*"},
+			want:      []string{"module.app.aws_instance.web", "module.app.aws_s3_bucket.logs"},
+		},
+		"glob over module instances matches orphans": {
+			selectors: []string{"module.app[*].google_*"},
+			want:      []string{`module.app["b"].google_compute_instance.old`},
+		},
+		"glob over keyed module instance": {
+			selectors: []string{`module.app["a"].aws_*`},
+			want:      []string{`module.app["a"].aws_instance.web`},
+		},
+		"glob over keyed module instance matches orphans": {
+			selectors: []string{`module.app["b"].*`},
+			want:      []string{`module.app["b"].aws_instance.web`, `module.app["b"].google_compute_instance.old`},
+		},
+		"glob over keyed module instance without instances": {
+			selectors: []string{`module.app["c"].aws_*`},
+			want:      nil,
+			wantWarns: 1,
+		},
+		"glob over keyed resource instance": {
+			selectors: []string{"aws_instance.*[1]"},
+			want:      []string{"aws_instance.bastion[1]"},
+		},
+		"glob over any resource instance": {
+			selectors: []string{"aws_instance.*[*]"},
+			want:      []string{"aws_instance.bastion"},
+		},
+		"glob in root module": {
+			selectors: []string{"aws_*"},
+			want:      []string{"aws_instance.bastion"},
+		},
+		"glob on resource name": {
+			selectors: []string{"module.app.aws_instance.*"},
+			want:      []string{"module.app.aws_instance.web"},
+		},
+		"provider": {
+			selectors: []string{"provider=hashicorp/google"},
+			want:      []string{`module.app["b"].google_compute_instance.old`},
+		},
+		"type": {
+			selectors: []string{"type=aws_instance"},
+			want:      []string{"aws_instance.bastion", "module.app.aws_instance.web"},
+		},
+		"data type": {
+			selectors: []string{"type=data.aws_ami"},
+			want:      []string{"data.aws_ami.base"},
+		},
+		"tag key": {
+			selectors: []string{"tag=Team"},
+			want:      []string{`module.app["a"].aws_instance.web`, `module.app["b"].aws_instance.web`},
+		},
+		"tag key and value": {
+			selectors: []string{"tag=Team=payments"},
+			want:      []string{`module.app["b"].aws_instance.web`},
+		},
+		"duplicates are removed": {
+			selectors: []string{"type=aws_instance", "aws_instance.*"},
+			want:      []string{"aws_instance.bastion", "module.app.aws_instance.web"},
+		},
+		"no match": {
+			selectors: []string{"type=azurerm_virtual_machine"},
+			want:      nil,
+			wantWarns: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var selectors []Selector
+			for _, raw := range test.selectors {
+				selector, diags := ParseSelector(raw)
+				if diags.HasErrors() {
+					t.Fatalf("unexpected error parsing %q: %s", raw, diags.Err())
+				}
+				selectors = append(selectors, selector)
+			}
+
+			resolved, diags := Resolve(selectors, config, state, "target")
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Err())
+			}
+			if got := len(diags); got != test.wantWarns {
+				t.Errorf("wrong number of warnings %d; want %d", got, test.wantWarns)
+			}
+
+			var got []string
+			for _, addr := range resolved {
+				got = append(got, addr.String())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolve_concreteKeys(t *testing.T) {
+	config, state := testResolveFixture()
+
+	// Globs with concrete instance keys must select only the matching
+	// instances, rather than the whole resource in every module instance.
+	var selectors []Selector
+	for _, raw := range []string{`module.app["a"].aws_*`, "aws_instance.*[1]"} {
+		selector, diags := ParseSelector(raw)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected error parsing %q: %s", raw, diags.Err())
+		}
+		selectors = append(selectors, selector)
+	}
+
+	resolved, diags := Resolve(selectors, config, state, "target")
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %s", diags.ErrWithWarnings())
+	}
+
+	want := []addrs.Targetable{
+		addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "bastion"}.Instance(addrs.IntKey(1)).Absolute(addrs.RootModuleInstance),
+		addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "web"}.Absolute(addrs.RootModuleInstance.Child("app", addrs.StringKey("a"))),
+	}
+	if diff := cmp.Diff(want, resolved); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := map[string]struct {
+		kind    SelectorKind
+		wantErr string
+	}{
+		"module.app[*].aws_*":           {kind: GlobSelector},
+		"*":                             {kind: GlobSelector},
+		"provider=hashicorp/aws":        {kind: ProviderSelector},
+		"type=aws_instance":             {kind: TypeSelector},
+		"type=data.aws_ami":             {kind: TypeSelector},
+		"tag=Name":                      {kind: TagSelector},
+		"tag=Name=web":                  {kind: TagSelector},
+		"provider=a/b/c/d":              {wantErr: "is not valid"},
+		"type=not a type":               {wantErr: "is not a valid resource type name"},
+		"tag==web":                      {wantErr: "must specify at least a tag key"},
+		"module.app[*]..aws_instance.*": {wantErr: "must have the same shape as a resource address"},
+		"aws_instance.web":              {wantErr: "Expected a glob pattern"},
+	}
+
+	for raw, test := range tests {
+		t.Run(raw, func(t *testing.T) {
+			got, diags := ParseSelector(raw)
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatalf("unexpected success")
+				}
+				if msg := diags.Err().Error(); !strings.Contains(msg, test.wantErr) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", msg, test.wantErr)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected error: %s", diags.Err())
+			}
+			if got.Kind != test.kind {
+				t.Errorf("wrong kind %d; want %d", got.Kind, test.kind)
+			}
+			if got.String() != raw {
+				t.Errorf("wrong string %q; want %q", got.String(), raw)
+			}
+		})
+	}
+}
+
+func testResolveFixture() (*configs.Config, *states.State) {
+	aws := addrs.NewDefaultProvider("aws")
+	google := addrs.NewDefaultProvider("google")
+
+	root := &configs.Config{
+		Module: &configs.Module{
+			ManagedResources: map[string]*configs.Resource{
+				"aws_instance.bastion": {Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "bastion", Provider: aws},
+			},
+			DataResources: map[string]*configs.Resource{
+				"data.aws_ami.base": {Mode: addrs.DataResourceMode, Type: "aws_ami", Name: "base", Provider: aws},
+			},
+		},
+		Children: map[string]*configs.Config{},
+	}
+	root.Root = root
+	root.Children["app"] = &configs.Config{
+		Root:   root,
+		Parent: root,
+		Path:   addrs.Module{"app"},
+		Module: &configs.Module{
+			ManagedResources: map[string]*configs.Resource{
+				"aws_instance.web":   {Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "web", Provider: aws},
+				"aws_s3_bucket.logs": {Mode: addrs.ManagedResourceMode, Type: "aws_s3_bucket", Name: "logs", Provider: aws},
+			},
+		},
+	}
+
+	state := states.BuildState(func(s *states.SyncState) {
+		for key, attrs := range map[string]string{
+			"a": `{"id":"i-a","tags":{"Team":"search"}}`,
+			"b": `{"id":"i-b","tags":{"Team":"payments"}}`,
+		} {
+			module := addrs.RootModuleInstance.Child("app", addrs.StringKey(key))
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "web"}.Instance(addrs.NoKey).Absolute(module),
+				&states.ResourceInstanceObjectSrc{Status: states.ObjectReady, AttrsJSON: []byte(attrs)},
+				addrs.AbsProviderConfig{Provider: aws, Module: addrs.RootModule},
+				addrs.NoKey,
+			)
+		}
+		for _, key := range []int{0, 1} {
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "bastion"}.Instance(addrs.IntKey(key)).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{Status: states.ObjectReady, AttrsJSON: []byte(`{"id":"bastion"}`)},
+				addrs.AbsProviderConfig{Provider: aws, Module: addrs.RootModule},
+				addrs.NoKey,
+			)
+		}
+		// This resource has been removed from the configuration.
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "google_compute_instance", Name: "old"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance.Child("app", addrs.StringKey("b"))),
+			&states.ResourceInstanceObjectSrc{Status: states.ObjectReady, AttrsJSON: []byte(`{"id":"old"}`)},
+			addrs.AbsProviderConfig{Provider: google, Module: addrs.RootModule},
+			addrs.NoKey,
+		)
+	})
+
+	return root, state
+}
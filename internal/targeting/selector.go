@@ -0,0 +1,198 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package targeting deals with the "selector" forms accepted by the -target
+// and -exclude options in addition to concrete addresses.
+//
+// A selector cannot be turned into an address on its own: it must be resolved
+// against the configuration and the prior state, which produces a list of
+// addrs.Targetable values that can then be used in the same way as addresses
+// given directly on the command line.
+package targeting
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// SelectorKind describes which criteria a Selector uses to match resources.
+type SelectorKind int
+
+const (
+	// InvalidSelector is the zero value of SelectorKind, and is never the
+	// kind of a valid selector.
+	InvalidSelector SelectorKind = iota
+
+	// GlobSelector matches resource addresses against a glob pattern, like
+	// module.app[*].aws_*.
+	GlobSelector
+
+	// ProviderSelector matches resources belonging to a particular provider,
+	// like provider=registry.opentofu.org/hashicorp/aws.
+	ProviderSelector
+
+	// TypeSelector matches resources of a particular resource type, like
+	// type=aws_instance or type=data.aws_ami.
+	TypeSelector
+
+	// TagSelector matches resource instances in the prior state whose "tags"
+	// attribute has a particular key, and optionally a particular value, like
+	// tag=Team=payments.
+	TagSelector
+)
+
+const (
+	providerSelectorPrefix = "provider="
+	typeSelectorPrefix     = "type="
+	tagSelectorPrefix      = "tag="
+)
+
+// Selector is a parsed selector expression given to -target or -exclude in
+// place of a concrete address.
+type Selector struct {
+	Kind SelectorKind
+
+	// Raw is the selector exactly as the user wrote it, used when returning
+	// diagnostics about the selector.
+	Raw string
+
+	// pattern and concreteKeys are populated only for GlobSelector.
+	// concreteKeys is true if the pattern has any instance keys other than
+	// [*], in which case it can only match instances that already exist.
+	pattern      *regexp.Regexp
+	concreteKeys bool
+
+	// Provider is populated only for ProviderSelector.
+	Provider addrs.Provider
+
+	// Mode and Type are populated only for TypeSelector.
+	Mode addrs.ResourceMode
+	Type string
+
+	// TagKey and TagValue are populated only for TagSelector. If TagValue is
+	// nil then only the presence of the key is tested.
+	TagKey   string
+	TagValue *string
+}
+
+// IsSelector returns true if the given raw -target or -exclude argument
+// should be parsed using ParseSelector rather than as an address.
+func IsSelector(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case strings.HasPrefix(raw, providerSelectorPrefix),
+		strings.HasPrefix(raw, typeSelectorPrefix),
+		strings.HasPrefix(raw, tagSelectorPrefix):
+		return true
+	default:
+		return strings.Contains(raw, "*")
+	}
+}
+
+// ParseSelector parses the given raw string as a selector. The caller should
+// typically first use IsSelector to decide whether a string is intended to be
+// a selector, because any string that IsSelector doesn't accept is rejected
+// by this function.
+func ParseSelector(raw string) (Selector, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	raw = strings.TrimSpace(raw)
+	ret := Selector{Raw: raw}
+
+	switch {
+	case strings.HasPrefix(raw, providerSelectorPrefix):
+		src := strings.TrimPrefix(raw, providerSelectorPrefix)
+		provider, err := addrs.ParseProviderSourceString(src)
+		if err != nil {
+			diags = diags.Append(invalidSelector(raw, fmt.Sprintf("The provider source address %q is not valid: %s.", src, err)))
+			return ret, diags
+		}
+		ret.Kind = ProviderSelector
+		ret.Provider = provider
+
+	case strings.HasPrefix(raw, typeSelectorPrefix):
+		typeName := strings.TrimPrefix(raw, typeSelectorPrefix)
+		ret.Mode = addrs.ManagedResourceMode
+		if after, ok := strings.CutPrefix(typeName, "data."); ok {
+			ret.Mode = addrs.DataResourceMode
+			typeName = after
+		}
+		if !hclsyntax.ValidIdentifier(typeName) {
+			diags = diags.Append(invalidSelector(raw, fmt.Sprintf("%q is not a valid resource type name.", typeName)))
+			return ret, diags
+		}
+		ret.Kind = TypeSelector
+		ret.Type = typeName
+
+	case strings.HasPrefix(raw, tagSelectorPrefix):
+		expr := strings.TrimPrefix(raw, tagSelectorPrefix)
+		key, value, hasValue := strings.Cut(expr, "=")
+		if key == "" {
+			diags = diags.Append(invalidSelector(raw, "A tag selector must specify at least a tag key, like tag=Name or tag=Name=web."))
+			return ret, diags
+		}
+		ret.Kind = TagSelector
+		ret.TagKey = key
+		if hasValue {
+			ret.TagValue = &value
+		}
+
+	case strings.Contains(raw, "*"):
+		// We validate the overall shape of the glob by substituting
+		// placeholders for the wildcards and making sure that the result is
+		// at least a valid traversal, so that typos are caught early rather
+		// than silently matching nothing.
+		probe := strings.ReplaceAll(raw, "[*]", "[0]")
+		probe = strings.ReplaceAll(probe, "*", "x")
+		_, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(probe), "", hcl.Pos{Line: 1, Column: 1})
+		if syntaxDiags.HasErrors() {
+			diags = diags.Append(invalidSelector(raw, "A glob selector must have the same shape as a resource address, using * to match any part of a name and [*] to match any instance key."))
+			return ret, diags
+		}
+		ret.Kind = GlobSelector
+		ret.pattern = globPattern(raw)
+		ret.concreteKeys = strings.Contains(strings.ReplaceAll(raw, "[*]", ""), "[")
+
+	default:
+		diags = diags.Append(invalidSelector(raw, "Expected a glob pattern containing *, or one of the prefixes provider=, type= or tag=."))
+	}
+
+	return ret, diags
+}
+
+// String returns the selector as originally written.
+func (s Selector) String() string {
+	return s.Raw
+}
+
+// globPattern converts a glob selector into a regular expression that
+// matches the string representation of an address.
+//
+// The pattern "[*]" matches any single instance key, while any other "*"
+// matches any run of characters within a single address step. The pattern
+// matches either a whole address or a leading portion of it that ends at a
+// step boundary, so that for example "module.app[*]" selects everything
+// declared in any instance of module.app, and "aws_*" selects every resource
+// whose type begins with aws_ in the root module.
+func globPattern(raw string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(raw)
+	quoted = strings.ReplaceAll(quoted, `\[\*\]`, `\[[^\]]+\]`)
+	quoted = strings.ReplaceAll(quoted, `\*`, `[^.\[\]]*`)
+	return regexp.MustCompile(`^` + quoted + `(?:[.\[].*)?$`)
+}
+
+func invalidSelector(raw, detail string) tfdiags.Diagnostic {
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		fmt.Sprintf("Invalid target selector %q", raw),
+		detail,
+	)
+}
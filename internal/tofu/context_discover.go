@@ -0,0 +1,284 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// DiscoverOpts are the options for DiscoverResources.
+type DiscoverOpts struct {
+	// ResourceType is the managed resource type whose existing objects
+	// should be listed.
+	ResourceType string
+
+	// ProviderConfig selects the provider configuration in the root module
+	// to list the objects with. If LocalName is empty then the provider is
+	// implied from the resource type, as for a resource block without a
+	// provider argument.
+	ProviderConfig addrs.LocalProviderConfig
+
+	// Filters are the filter arguments to send to the provider, keyed by
+	// attribute name in the list schema of the resource type.
+	Filters map[string]string
+
+	// Limit is the maximum number of objects to list, or zero for all of
+	// the objects that the provider returns.
+	Limit int64
+
+	// SetVariables are the variables set outside of the configuration,
+	// such as on the command line, in variables files, etc.
+	SetVariables InputValues
+}
+
+// DiscoverResult is the result of DiscoverResources.
+type DiscoverResult struct {
+	// Resources are the listed objects that aren't tracked in the state yet,
+	// each with a unique resource address in the root module that isn't
+	// used by the configuration or the state.
+	Resources []*DiscoveredResource
+
+	// Managed are the addresses of the resource instances in the state that
+	// already track one of the listed objects.
+	Managed []addrs.AbsResourceInstance
+}
+
+// DiscoveredResource is an existing remote object that could be imported.
+type DiscoveredResource struct {
+	// Addr is the proposed address for the object.
+	Addr addrs.AbsResourceInstance
+
+	// DisplayName is the name the provider gave for the object.
+	DisplayName string
+
+	// Identity is the resource identity to import the object by.
+	Identity cty.Value
+
+	// Config is the generated body of a resource block for the object,
+	// without the surrounding block. It's empty if the provider didn't
+	// return the full object.
+	Config string
+}
+
+// DiscoverResources asks the provider for the given resource type to list the
+// existing remote objects of that type, and returns the ones that aren't
+// already tracked in the given state so that the caller can generate import
+// blocks for them.
+//
+// The provider must support listing objects of the resource type, and the
+// resource type must have an identity schema.
+func (c *Context) DiscoverResources(ctx context.Context, config *configs.Config, prevRunState *states.State, opts *DiscoverOpts) (*DiscoverResult, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	defer c.acquireRun("discover")()
+
+	if !hclsyntax.ValidIdentifier(opts.ResourceType) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid resource type",
+			fmt.Sprintf("%q is not a valid resource type name.", opts.ResourceType),
+		))
+		return nil, diags
+	}
+
+	providerConfig := opts.ProviderConfig
+	if providerConfig.LocalName == "" {
+		resourceAddr := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: opts.ResourceType}
+		providerConfig.LocalName = resourceAddr.ImpliedProvider()
+	}
+	providerAddr := config.Module.ProviderForLocalConfig(providerConfig)
+
+	node := &nodeDiscoverResources{
+		TypeName:       opts.ResourceType,
+		ProviderAddr:   providerAddr,
+		ProviderConfig: providerConfig,
+		Filters:        opts.Filters,
+		Limit:          opts.Limit,
+	}
+
+	log.Printf("[DEBUG] Building and walking discover graph")
+
+	// We walk the same graph as an import, which never plans any changes or
+	// refreshes existing objects, but still configures the providers using
+	// everything they might depend on.
+	state := prevRunState.DeepCopy()
+	providerFunctionTracker := make(ProviderFunctionMapping)
+	builder := &PlanGraphBuilder{
+		Config:                  config,
+		State:                   state,
+		RootVariableValues:      opts.SetVariables,
+		Plugins:                 c.plugins,
+		Operation:               walkImport,
+		ProviderFunctionTracker: providerFunctionTracker,
+		discoverResources:       node,
+	}
+
+	graph, graphDiags := builder.Build(ctx, addrs.RootModuleInstance)
+	diags = diags.Append(graphDiags)
+	if graphDiags.HasErrors() {
+		return nil, diags
+	}
+
+	_, walkDiags := c.walk(ctx, graph, walkImport, &graphWalkOpts{
+		Config:                  config,
+		InputState:              state,
+		ProviderFunctionTracker: providerFunctionTracker,
+	})
+	diags = diags.Append(walkDiags)
+	if walkDiags.HasErrors() {
+		return nil, diags
+	}
+
+	result := &DiscoverResult{}
+	usedNames := discoverUsedNames(config, prevRunState, opts.ResourceType)
+	for _, obj := range node.results {
+		if managed, ok := discoverManagedInstance(prevRunState, node.schema, opts.ResourceType, obj.Identity); ok {
+			log.Printf("[TRACE] DiscoverResources: %s already tracks %q", managed, obj.DisplayName)
+			result.Managed = append(result.Managed, managed)
+			continue
+		}
+
+		addr := addrs.RootModuleInstance.ResourceInstance(
+			addrs.ManagedResourceMode,
+			opts.ResourceType,
+			discoverResourceName(obj.DisplayName, usedNames),
+			addrs.NoKey,
+		)
+		resource := &DiscoveredResource{
+			Addr:        addr,
+			DisplayName: obj.DisplayName,
+			Identity:    obj.Identity,
+		}
+		if !obj.ResourceObject.IsNull() {
+			var genDiags tfdiags.Diagnostics
+			resource.Config, genDiags = generateResourceConfig(addr, node.ResolvedProvider.ProviderConfig, obj.ResourceObject, node.schema.Block)
+			diags = diags.Append(genDiags)
+		}
+		result.Resources = append(result.Resources, resource)
+	}
+
+	return result, diags
+}
+
+// discoverManagedInstance returns the address of a resource instance of the
+// given type in the state whose current object is the remote object with the
+// given identity, if any.
+//
+// Objects that were created or imported before their provider supported
+// resource identities only have an identity in the state once they have been
+// refreshed, so for those we fall back on comparing the "id" attribute when
+// the identity schema has one.
+func discoverManagedInstance(state *states.State, schema providers.Schema, typeName string, identity cty.Value) (addrs.AbsResourceInstance, bool) {
+	if state == nil || schema.IdentitySchema == nil {
+		return addrs.AbsResourceInstance{}, false
+	}
+	identityType := schema.IdentitySchema.ImpliedType()
+
+	var id cty.Value
+	if identityType.HasAttribute("id") && !identity.IsNull() {
+		id = identity.GetAttr("id")
+	}
+
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			if rs.Addr.Resource.Mode != addrs.ManagedResourceMode || rs.Addr.Resource.Type != typeName {
+				continue
+			}
+			for key, is := range rs.Instances {
+				if is.Current == nil {
+					continue
+				}
+				if len(is.Current.IdentityJSON) > 0 {
+					got, err := ctyjson.Unmarshal(is.Current.IdentityJSON, identityType)
+					if err == nil && got.RawEquals(identity) {
+						return rs.Addr.Instance(key), true
+					}
+					continue
+				}
+				if id == cty.NilVal || id.IsNull() {
+					continue
+				}
+				obj, err := is.Current.Decode(schema.Block.ImpliedType())
+				if err != nil || !obj.Value.Type().IsObjectType() || !obj.Value.Type().HasAttribute("id") {
+					continue
+				}
+				if got := obj.Value.GetAttr("id"); got.IsKnown() && !got.IsNull() && got.RawEquals(id) {
+					return rs.Addr.Instance(key), true
+				}
+			}
+		}
+	}
+	return addrs.AbsResourceInstance{}, false
+}
+
+// discoverUsedNames returns the names of the resources of the given type in
+// the root module of the configuration and the state, which generated
+// resource names must not collide with.
+func discoverUsedNames(config *configs.Config, state *states.State, typeName string) map[string]bool {
+	used := make(map[string]bool)
+	if config != nil && config.Module != nil {
+		for _, rc := range config.Module.ManagedResources {
+			if rc.Type == typeName {
+				used[rc.Name] = true
+			}
+		}
+	}
+	if state != nil {
+		if ms := state.RootModule(); ms != nil {
+			for _, rs := range ms.Resources {
+				if rs.Addr.Resource.Mode == addrs.ManagedResourceMode && rs.Addr.Resource.Type == typeName {
+					used[rs.Addr.Resource.Name] = true
+				}
+			}
+		}
+	}
+	return used
+}
+
+// discoverResourceName turns the display name of a discovered object into a
+// valid resource name that isn't in the given set of used names, and adds it
+// to the set.
+func discoverResourceName(displayName string, used map[string]bool) string {
+	var buf strings.Builder
+	lastUnderscore := false
+	for _, r := range strings.ToLower(displayName) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			buf.WriteRune(r)
+			lastUnderscore = false
+		case !lastUnderscore:
+			buf.WriteRune('_')
+			lastUnderscore = true
+		}
+	}
+	name := strings.Trim(buf.String(), "_-")
+	switch {
+	case name == "":
+		name = "discovered"
+	case name[0] >= '0' && name[0] <= '9':
+		name = "resource_" + name
+	}
+
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	used[candidate] = true
+	return candidate
+}
@@ -0,0 +1,240 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plugins"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func discoverTestProvider() *MockProvider {
+	p := &MockProvider{}
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		Provider: providers.Schema{
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"region": {Type: cty.String, Required: true},
+				},
+			},
+		},
+		ResourceTypes: map[string]providers.Schema{
+			"test_thing": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":   {Type: cty.String, Computed: true},
+						"name": {Type: cty.String, Optional: true},
+					},
+				},
+				IdentitySchema: &configschema.Object{
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Required: true},
+					},
+					Nesting: configschema.NestingSingle,
+				},
+			},
+			"test_other": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+		ListResourceTypes: map[string]providers.Schema{
+			"test_thing": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"prefix": {Type: cty.String, Optional: true},
+						"max":    {Type: cty.Number, Optional: true},
+					},
+				},
+			},
+		},
+	}
+	return p
+}
+
+func TestContext2DiscoverResources(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "region" {
+  default = "eu"
+}
+
+provider "test" {
+  region = var.region
+}
+
+resource "test_thing" "web" {
+}
+`,
+	})
+
+	providerAddr := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_thing.db"), &states.ResourceInstanceObjectSrc{
+			Status:       states.ObjectReady,
+			AttrsJSON:    []byte(`{"id":"i-2","name":"db"}`),
+			IdentityJSON: []byte(`{"id":"i-2"}`),
+		}, providerAddr, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("module.child.test_thing.legacy"), &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"id":"i-3","name":"legacy"}`),
+		}, providerAddr, addrs.NoKey)
+	})
+
+	p := discoverTestProvider()
+	p.ListResourceFn = func(req providers.ListResourceRequest) providers.ListResourceResponse {
+		if got, want := p.ConfigureProviderRequest.Config.GetAttr("region"), cty.StringVal("eu"); !got.RawEquals(want) {
+			t.Errorf("wrong provider configuration\ngot:  %#v\nwant: %#v", got, want)
+		}
+		wantConfig := cty.ObjectVal(map[string]cty.Value{
+			"prefix": cty.StringVal("w"),
+			"max":    cty.NumberIntVal(5),
+		})
+		if !req.Config.RawEquals(wantConfig) {
+			t.Errorf("wrong filter configuration\ngot:  %#v\nwant: %#v", req.Config, wantConfig)
+		}
+		if !req.IncludeResourceObject {
+			t.Error("expected the full objects to be requested")
+		}
+
+		object := func(id, name string) cty.Value {
+			return cty.ObjectVal(map[string]cty.Value{
+				"id":   cty.StringVal(id),
+				"name": cty.StringVal(name),
+			})
+		}
+		identity := func(id string) cty.Value {
+			return cty.ObjectVal(map[string]cty.Value{
+				"id": cty.StringVal(id),
+			})
+		}
+		return providers.ListResourceResponse{
+			Results: []providers.ListResourceResult{
+				{DisplayName: "web", Identity: identity("i-1"), ResourceObject: object("i-1", "web")},
+				{DisplayName: "db", Identity: identity("i-2"), ResourceObject: object("i-2", "db")},
+				{DisplayName: "legacy", Identity: identity("i-3"), ResourceObject: object("i-3", "legacy")},
+				{DisplayName: "Cache Server", Identity: identity("i-4"), ResourceObject: cty.NullVal(object("", "").Type())},
+			},
+		}
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	result, diags := ctx.DiscoverResources(t.Context(), m, state, &DiscoverOpts{
+		ResourceType: "test_thing",
+		Filters: map[string]string{
+			"prefix": "w",
+			"max":    "5",
+		},
+	})
+	assertNoErrors(t, diags)
+
+	if !p.ListResourceCalled {
+		t.Fatal("ListResource was not called")
+	}
+
+	var gotManaged []string
+	for _, addr := range result.Managed {
+		gotManaged = append(gotManaged, addr.String())
+	}
+	if got, want := strings.Join(gotManaged, ", "), "test_thing.db, module.child.test_thing.legacy"; got != want {
+		t.Errorf("wrong managed instances\ngot:  %s\nwant: %s", got, want)
+	}
+
+	if got, want := len(result.Resources), 2; got != want {
+		t.Fatalf("wrong number of discovered resources %d; want %d", got, want)
+	}
+
+	web := result.Resources[0]
+	if got, want := web.Addr.String(), "test_thing.web_2"; got != want {
+		t.Errorf("wrong address\ngot:  %s\nwant: %s", got, want)
+	}
+	if !strings.Contains(web.Config, `name = "web"`) {
+		t.Errorf("generated config is missing the name attribute:\n%s", web.Config)
+	}
+	if strings.Contains(web.Config, "id") {
+		t.Errorf("generated config includes a computed attribute:\n%s", web.Config)
+	}
+
+	cache := result.Resources[1]
+	if got, want := cache.Addr.String(), "test_thing.cache_server"; got != want {
+		t.Errorf("wrong address\ngot:  %s\nwant: %s", got, want)
+	}
+	if cache.Config != "" {
+		t.Errorf("unexpected config generated without a resource object:\n%s", cache.Config)
+	}
+	if got, want := cache.Identity, cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("i-4")}); !got.RawEquals(want) {
+		t.Errorf("wrong identity\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2DiscoverResources_unsupported(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "test" {
+  region = "eu"
+}
+`,
+	})
+
+	p := discoverTestProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	_, diags := ctx.DiscoverResources(t.Context(), m, states.NewState(), &DiscoverOpts{
+		ResourceType: "test_other",
+	})
+	if !diags.HasErrors() {
+		t.Fatal("expected an error")
+	}
+	if got, want := diags.Err().Error(), "Resource type does not support discovery"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+	if p.ListResourceCalled {
+		t.Error("ListResource should not have been called")
+	}
+}
+
+func TestDiscoverResourceName(t *testing.T) {
+	used := map[string]bool{"web": true}
+	tests := []struct {
+		DisplayName string
+		Want        string
+	}{
+		{"web", "web_2"},
+		{"web", "web_3"},
+		{"My Bucket (prod)", "my_bucket_prod"},
+		{"arn:aws:s3:::logs", "arn_aws_s3_logs"},
+		{"2024-backups", "resource_2024-backups"},
+		{"", "discovered"},
+		{"???", "discovered_2"},
+	}
+	for _, test := range tests {
+		if got := discoverResourceName(test.DisplayName, used); got != test.Want {
+			t.Errorf("wrong name for %q\ngot:  %s\nwant: %s", test.DisplayName, got, test.Want)
+		}
+	}
+}
@@ -1824,6 +1824,110 @@ func TestContext2Plan_movedResourceToDifferentType(t *testing.T) {
 	}
 }
 
+func TestContext2Plan_movedResourceToDifferentTypeSourceProvider(t *testing.T) {
+	SkipExperimental(t, ExperimentalFeatureMoved)
+
+	oldAddr := mustResourceInstanceAddr("old_object.old")
+	newAddr := mustResourceInstanceAddr("new_object.new")
+	oldProviderAddr := addrs.AbsProviderConfig{
+		Provider: addrs.MustParseProviderSourceString("example.com/acme/old"),
+		Module:   addrs.RootModule,
+	}
+	newProvider := addrs.MustParseProviderSourceString("example.com/acme/new")
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			terraform {
+				required_providers {
+					new = {
+						source = "example.com/acme/new"
+					}
+				}
+			}
+			resource "new_object" "new" {
+				test_number = 1
+			}
+			moved {
+				from = old_object.old
+				to   = new_object.new
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(oldAddr, &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"test_number": 1}`),
+		}, oldProviderAddr, addrs.NoKey)
+	})
+
+	schema := constructProviderSchemaForTesting(map[string]*configschema.Attribute{
+		"test_number": {
+			Type:     cty.Number,
+			Required: true,
+		},
+	})
+	provider := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			// The mock only moves state from the types it has a schema for.
+			ResourceTypes: map[string]providers.Schema{
+				"new_object": schema,
+				"old_object": schema,
+			},
+		},
+		MoveResourceStateResponse: &providers.MoveResourceStateResponse{
+			TargetState: cty.ObjectVal(map[string]cty.Value{
+				"test_number": cty.NumberIntVal(1),
+			}),
+		},
+	}
+
+	// The previous provider is still needed for its schema, as the previous
+	// run state refers to it.
+	oldProvider := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{
+				"old_object": schema,
+			},
+		},
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			oldProviderAddr.Provider: testProviderFuncFixed(oldProvider),
+			newProvider:              testProviderFuncFixed(provider),
+		}, nil),
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode: plans.NormalMode,
+	})
+	assertNoErrors(t, diags)
+
+	if !provider.MoveResourceStateCalled {
+		t.Fatal("MoveResourceState was not called")
+	}
+	// The provider must be told which provider the object came from, rather
+	// than the one implied by the type name of the previous address.
+	if got, want := provider.MoveResourceStateRequest.SourceProviderAddress, oldProviderAddr.Provider.String(); got != want {
+		t.Errorf("wrong source provider address\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := provider.MoveResourceStateRequest.SourceTypeName, "old_object"; got != want {
+		t.Errorf("wrong source type name\ngot:  %s\nwant: %s", got, want)
+	}
+
+	instPlan := plan.Changes.ResourceInstance(newAddr)
+	if instPlan == nil {
+		t.Fatalf("no plan for %s at all", newAddr)
+	}
+	if got, want := instPlan.PrevRunAddr, oldAddr; !got.Equal(want) {
+		t.Errorf("wrong previous run address\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := instPlan.Action, plans.NoOp; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
 func TestContext2Plan_movedResourceCollision(t *testing.T) {
 	SkipExperimental(t, ExperimentalFeatureMoved)
 
@@ -9398,3 +9502,174 @@ resource "test_resource" "example" {
 		t.Fatalf("expected to have exactly one resource change but got %d", changes)
 	}
 }
+
+func TestContext2Plan_moduleFunctions(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+function "greeting" {
+  parameter "name" {
+    type = string
+  }
+
+  variadic_parameter "titles" {
+    type = string
+  }
+
+  result = join(" ", concat(["Hello"], param.titles, [title(param.name)]))
+}
+
+locals {
+  name = "world"
+}
+
+resource "test_object" "a" {
+  test_string = module_fn::greeting(local.name, "dear")
+}
+
+module "child" {
+  source = "./child"
+}
+
+output "child" {
+  value = module.child.out
+}
+`,
+		"child/main.tf": `
+function "greeting" {
+  parameter "name" {}
+
+  result = "Goodbye ${param.name}"
+}
+
+output "out" {
+  value = module_fn::greeting("child")
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	schema := p.GetProviderSchemaResponse.ResourceTypes["test_object"]
+	change, err := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.a")).Decode(&schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := change.After.GetAttr("test_string"), cty.StringVal("Hello dear World"); !got.RawEquals(want) {
+		t.Errorf("wrong test_string\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	SkipExperimental(t, ExperimentalFeatureChanges)
+	out, err := plan.Changes.OutputValue(addrs.OutputValue{Name: "child"}.Absolute(addrs.RootModuleInstance)).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.After, cty.StringVal("Goodbye child"); !got.RawEquals(want) {
+		t.Errorf("wrong output\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2Plan_moduleFunctionsNotInherited(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+function "double" {
+  parameter "n" {
+    type = number
+  }
+
+  result = param.n * 2
+}
+
+module "child" {
+  source = "./child"
+}
+`,
+		"child/main.tf": `
+output "out" {
+  value = module_fn::double(2)
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("expected errors, got none")
+	}
+	if got, want := diags.Err().Error(), `There is no function named "double" declared in this module.`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Plan_outputTypeConstraint(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+module "child" {
+  source = "./child"
+}
+
+resource "test_object" "a" {
+  test_string = "${module.child.endpoint.host}:${module.child.endpoint.port}"
+}
+`,
+		"child/main.tf": `
+output "endpoint" {
+  type = object({
+    host = string
+    port = optional(number, 443)
+  })
+  value = {
+    host = "example.com"
+  }
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	schema := p.GetProviderSchemaResponse.ResourceTypes["test_object"]
+	change, err := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.a")).Decode(&schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := change.After.GetAttr("test_string"), cty.StringVal("example.com:443"); !got.RawEquals(want) {
+		t.Errorf("wrong test_string\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2Plan_outputTypeConstraintMismatch(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+output "ports" {
+  type  = list(number)
+  value = ["http", "https"]
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), `The value of output "ports" is not suitable for its type constraint`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/refactoring"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// MoveCandidates creates a plan for the given configuration and state and
+// returns the resource instances that the plan would destroy because they are
+// no longer declared in the configuration, along with the resource instances
+// that it would create, so that the caller can look for pairs that ought to
+// be connected by a moved block instead.
+//
+// The plan is always created in normal mode without refreshing, because we
+// want to compare the configuration with the state as it was last recorded,
+// and so only the SetVariables field of the given options is used.
+func (c *Context) MoveCandidates(ctx context.Context, config *configs.Config, prevRunState *states.State, opts *PlanOpts) (orphaned, added []refactoring.MoveCandidate, diags tfdiags.Diagnostics) {
+	planOpts := &PlanOpts{
+		Mode:        plans.NormalMode,
+		SkipRefresh: true,
+	}
+	if opts != nil {
+		planOpts.SetVariables = opts.SetVariables
+	}
+
+	plan, planDiags := c.Plan(ctx, config, prevRunState, planOpts)
+	diags = diags.Append(planDiags)
+	if planDiags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	schemas, schemaDiags := c.Schemas(ctx, config, plan.PriorState)
+	diags = diags.Append(schemaDiags)
+	if schemaDiags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	for _, rcs := range plan.Changes.Resources {
+		if rcs.Addr.Resource.Resource.Mode != addrs.ManagedResourceMode || rcs.DeposedKey != states.NotDeposed {
+			continue
+		}
+
+		var orphan bool
+		switch {
+		case rcs.Action == plans.Delete && isOrphanDeleteReason(rcs.ActionReason):
+			orphan = true
+		case rcs.Action == plans.Create:
+			orphan = false
+		default:
+			continue
+		}
+
+		schema, _ := schemas.ResourceTypeConfig(rcs.ProviderAddr.Provider, rcs.Addr.Resource.Resource.Mode, rcs.Addr.Resource.Resource.Type)
+		if schema == nil {
+			// Should never happen, because we just made a plan that
+			// includes this resource type.
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Missing resource type schema",
+				fmt.Sprintf("No schema is available for %s in %s. This is a bug in OpenTofu and should be reported.", rcs.Addr, rcs.ProviderAddr.Provider),
+			))
+			continue
+		}
+		change, err := rcs.Decode(schema)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to decode planned change",
+				fmt.Sprintf("Failed to decode the planned change for %s: %s.", rcs.Addr, err),
+			))
+			continue
+		}
+
+		if orphan {
+			orphaned = append(orphaned, refactoring.MoveCandidate{
+				Addr:  rcs.Addr,
+				Value: change.Before,
+			})
+		} else {
+			added = append(added, refactoring.MoveCandidate{
+				Addr:  rcs.Addr,
+				Value: change.After,
+			})
+		}
+	}
+
+	return orphaned, added, diags
+}
+
+// isOrphanDeleteReason returns true if the given reason means that a
+// resource instance is being deleted because it's no longer declared in the
+// configuration, which is what happens when an object is moved without a
+// corresponding moved block.
+func isOrphanDeleteReason(reason plans.ResourceInstanceChangeActionReason) bool {
+	switch reason {
+	case plans.ResourceInstanceDeleteBecauseNoResourceConfig,
+		plans.ResourceInstanceDeleteBecauseWrongRepetition,
+		plans.ResourceInstanceDeleteBecauseCountIndex,
+		plans.ResourceInstanceDeleteBecauseEachKey,
+		plans.ResourceInstanceDeleteBecauseNoModule:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plugins"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/refactoring"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestContext2MoveCandidates(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "kept" {
+  test_string = "kept"
+}
+
+resource "test_object" "renamed" {
+  test_string = "foo"
+}
+
+module "child" {
+  source = "./child"
+}
+`,
+		"child/main.tf": `
+resource "test_object" "wrapped" {
+  count = 2
+
+  test_number = count.index
+}
+`,
+	})
+
+	providerAddr := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_object.kept"), &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"test_string":"kept"}`),
+		}, providerAddr, addrs.NoKey)
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_object.original"), &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"test_string":"foo"}`),
+		}, providerAddr, addrs.NoKey)
+		for i := range 2 {
+			s.SetResourceInstanceCurrent(mustResourceInstanceAddr(fmt.Sprintf("test_object.wrapped[%d]", i)), &states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(fmt.Sprintf(`{"test_number":%d}`, i)),
+			}, providerAddr, addrs.NoKey)
+		}
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	orphaned, added, diags := ctx.MoveCandidates(context.Background(), m, state, nil)
+	assertNoErrors(t, diags)
+
+	if got, want := len(orphaned), 3; got != want {
+		t.Fatalf("wrong number of orphaned instances %d; want %d", got, want)
+	}
+	if got, want := len(added), 3; got != want {
+		t.Fatalf("wrong number of added instances %d; want %d", got, want)
+	}
+
+	moves := refactoring.SuggestMoves(orphaned, added, 0.5)
+	want := map[string]string{
+		"test_object.original": "test_object.renamed",
+		"test_object.wrapped":  "module.child.test_object.wrapped",
+	}
+	if got, want := len(moves), len(want); got != want {
+		t.Fatalf("wrong number of suggested moves %d; want %d\n%#v", got, want, moves)
+	}
+	for _, move := range moves {
+		if got, want := move.To.String(), want[move.From.String()]; got != want {
+			t.Errorf("wrong destination for %s\ngot:  %s\nwant: %s", move.From, got, want)
+		}
+	}
+}
@@ -3235,3 +3235,45 @@ resource "test_instance" "a" {
 		})
 	}
 }
+
+func TestContext2Validate_outputTypeConstraint(t *testing.T) {
+	// A typed output is known to have its declared type during validation,
+	// so references to attributes that the type doesn't have are caught
+	// before planning.
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+module "child" {
+  source = "./child"
+  host   = "example.com"
+}
+
+output "port" {
+  value = module.child.endpoint.prot
+}
+`,
+		"child/main.tf": `
+variable "host" {
+  type = string
+}
+
+output "endpoint" {
+  type = object({
+    host = string
+    port = optional(number, 443)
+  })
+  value = {
+    host = var.host
+  }
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+	diags := ctx.Validate(context.Background(), m)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), `Unsupported attribute`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -470,8 +470,11 @@ func (d *evaluationStateData) GetModule(_ context.Context, addr addrs.ModuleCall
 	// the structure is based on the configuration, so iterate through all the
 	// defined outputs, and add any instance state or changes we find.
 	for _, cfg := range outputConfigs {
-		// record the output names for validation
+		// record the output names and types for validation
 		unknownMap[cfg.Name] = cty.DynamicPseudoType
+		if cfg.Type != cty.NilType {
+			unknownMap[cfg.Name] = cfg.Type
+		}
 
 		// get all instance output for this path from the state
 		for key, outputStates := range stateMap {
@@ -594,8 +597,11 @@ func (d *evaluationStateData) GetModule(_ context.Context, addr addrs.ModuleCall
 			if callConfig.Enabled == nil {
 				// create the object if there wasn't one known
 				vals = map[string]cty.Value{}
-				for k := range outputConfigs {
+				for k, cfg := range outputConfigs {
 					vals[k] = cty.DynamicVal
+					if cfg.Type != cty.NilType {
+						vals[k] = cty.UnknownVal(cfg.Type)
+					}
 				}
 				ret = cty.ObjectVal(vals)
 			} else {
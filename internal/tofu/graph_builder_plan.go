@@ -99,6 +99,10 @@ type PlanGraphBuilder struct {
 	GenerateConfigPath string
 
 	ProviderFunctionTracker ProviderFunctionMapping
+
+	// discoverResources, if set, is a node that lists the existing remote
+	// objects of a resource type during the walk.
+	discoverResources *nodeDiscoverResources
 }
 
 // See GraphBuilder
@@ -207,6 +211,10 @@ func (b *PlanGraphBuilder) Steps() []GraphTransformer {
 		// Attach the configuration to any resources
 		&AttachResourceConfigTransformer{Config: b.Config},
 
+		// Add the node for discovering existing objects, if requested, so
+		// that it is connected to its provider below.
+		&discoverResourcesTransformer{Node: b.discoverResources},
+
 		// add providers
 		transformProviders(b.ConcreteProvider, b.Config, b.Operation),
 
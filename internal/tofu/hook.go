@@ -71,6 +71,13 @@ type Hook interface {
 	PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string, err error) (HookAction, error)
 	ProvisionOutput(addr addrs.AbsResourceInstance, typeName string, line string, configMarks cty.ValueMarks)
 
+	// PreConfigureProvider and PostConfigureProvider are called before and
+	// after a single instance of a provider configuration is configured. The
+	// error argument in PostConfigureProvider is the error, if any, that was
+	// returned from configuring the provider.
+	PreConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) (HookAction, error)
+	PostConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, err error) (HookAction, error)
+
 	// PreRefresh and PostRefresh are called before and after a single
 	// resource state is refreshed, respectively.
 	PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error)
@@ -181,6 +188,14 @@ func (*NilHook) PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typeNa
 func (*NilHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName string, line string, configMarks cty.ValueMarks) {
 }
 
+func (*NilHook) PreConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
+func (*NilHook) PostConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, err error) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
 func (*NilHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	return HookActionContinue, nil
 }
@@ -87,6 +87,19 @@ type MockHook struct {
 	ProvisionOutputMessage         string
 	ProvisionOutputConfigMarks     cty.ValueMarks
 
+	PreConfigureProviderCalled bool
+	PreConfigureProviderAddr   addrs.AbsProviderConfig
+	PreConfigureProviderKey    addrs.InstanceKey
+	PreConfigureProviderReturn HookAction
+	PreConfigureProviderError  error
+
+	PostConfigureProviderCalled      bool
+	PostConfigureProviderAddr        addrs.AbsProviderConfig
+	PostConfigureProviderKey         addrs.InstanceKey
+	PostConfigureProviderError       error
+	PostConfigureProviderReturn      HookAction
+	PostConfigureProviderReturnError error
+
 	PreRefreshCalled     bool
 	PreRefreshAddr       addrs.AbsResourceInstance
 	PreRefreshGen        states.Generation
@@ -297,6 +310,27 @@ func (h *MockHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName stri
 	h.ProvisionOutputConfigMarks = configMarks
 }
 
+func (h *MockHook) PreConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) (HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.PreConfigureProviderCalled = true
+	h.PreConfigureProviderAddr = addr
+	h.PreConfigureProviderKey = key
+	return h.PreConfigureProviderReturn, h.PreConfigureProviderError
+}
+
+func (h *MockHook) PostConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, err error) (HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.PostConfigureProviderCalled = true
+	h.PostConfigureProviderAddr = addr
+	h.PostConfigureProviderKey = key
+	h.PostConfigureProviderError = err
+	return h.PostConfigureProviderReturn, h.PostConfigureProviderReturnError
+}
+
 func (h *MockHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	h.Lock()
 	defer h.Unlock()
@@ -60,6 +60,14 @@ func (h *stopHook) PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typ
 func (h *stopHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName string, line string, configMarks cty.ValueMarks) {
 }
 
+func (h *stopHook) PreConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) (HookAction, error) {
+	return h.hook()
+}
+
+func (h *stopHook) PostConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, err error) (HookAction, error) {
+	return h.hook()
+}
+
 func (h *stopHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	return h.hook()
 }
@@ -101,6 +101,17 @@ func (h *testHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName stri
 	h.Calls = append(h.Calls, &testHookCall{"ProvisionOutput", addr.String()})
 }
 
+// PreConfigureProvider and PostConfigureProvider are not recorded, so that
+// the tests comparing the recorded calls only need to consider the calls
+// related to resource instances.
+func (h *testHook) PreConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
+func (h *testHook) PostConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, err error) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
 func (h *testHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
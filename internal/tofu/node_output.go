@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -371,6 +372,12 @@ func (n *NodeApplyableOutput) Execute(ctx context.Context, evalCtx EvalContext,
 		// depends_on expressions here too
 		diags = diags.Append(validateDependsOn(ctx, evalCtx, n.Config.DependsOn))
 
+		if val != cty.NilVal {
+			var convDiags tfdiags.Diagnostics
+			val, convDiags = convertOutputValue(n.Config, val)
+			diags = diags.Append(convDiags)
+		}
+
 		// Before checking for the sensitivity, we want to check for ephemerality, since it's
 		// a more restrictive mark.
 		if ephDiags := n.validateEphemerality(val); ephDiags.HasErrors() {
@@ -439,6 +446,31 @@ If you do intend to export this data, annotate the output value as sensitive by
 	return diags
 }
 
+// convertOutputValue converts the value of an output to the type constraint
+// of the output, if any, after applying the defaults of any optional
+// attributes.
+func convertOutputValue(cfg *configs.Output, val cty.Value) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if cfg.ConstraintType == cty.NilType {
+		return val, diags
+	}
+
+	if cfg.TypeDefaults != nil && !val.IsNull() {
+		val = cfg.TypeDefaults.Apply(val)
+	}
+	converted, err := convert.Convert(val, cfg.ConstraintType)
+	if err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid output value",
+			Detail:   fmt.Sprintf("The value of output %q is not suitable for its type constraint: %s.", cfg.Name, tfdiags.FormatError(err)),
+			Subject:  cfg.UsageRange().Ptr(),
+		})
+		return cty.UnknownVal(cfg.Type), diags
+	}
+	return converted, diags
+}
+
 func (n *NodeApplyableOutput) validateEphemerality(val cty.Value) (diags tfdiags.Diagnostics) {
 	if n.Config.Ephemeral && n.Addr.Module.IsRoot() {
 		diags = diags.Append(&hcl.Diagnostic{
@@ -241,9 +241,21 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx context.Context, evalCtx E
 		return diags
 	}
 
+	diags = diags.Append(evalCtx.Hook(func(h Hook) (HookAction, error) {
+		return h.PreConfigureProvider(n.Addr, providerKey)
+	}))
+	if diags.HasErrors() {
+		tracing.SetSpanError(span, diags)
+		return diags
+	}
+
 	provider, newDiags := evalCtx.Providers().NewConfiguredProvider(ctx, n.Addr.Provider, configVal)
 	diags = diags.Append(newDiags.InConfigBody(configBody, n.Addr.InstanceString(providerKey)))
 
+	diags = diags.Append(evalCtx.Hook(func(h Hook) (HookAction, error) {
+		return h.PostConfigureProvider(n.Addr, providerKey, newDiags.Err())
+	}))
+
 	n.instances[providerKey] = provider
 
 	if diags.HasErrors() && config == nil {
@@ -91,11 +91,11 @@ func (n *NodeAbstractProvider) AttachProviderConfigSchema(schema *configschema.B
 }
 
 // GraphNodeProvider (Partial Implementation)
-func (n *NodeAbstractProvider) MocksAndOverrides() (IsMocked bool, MockResources []*configs.MockResource, OverrideResources []*configs.OverrideResource) {
+func (n *NodeAbstractProvider) MocksAndOverrides() (IsMocked bool, MockResources []*configs.MockResource, OverrideResources []*configs.OverrideResource, MockGenerators *configs.MockGenerators) {
 	if n.Config == nil {
-		return false, nil, nil
+		return false, nil, nil, nil
 	}
-	return n.Config.IsMocked, n.Config.MockResources, n.Config.OverrideResources
+	return n.Config.IsMocked, n.Config.MockResources, n.Config.OverrideResources, n.Config.MockGenerators
 }
 
 // GraphNodeDotter impl.
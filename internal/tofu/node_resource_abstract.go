@@ -606,6 +606,21 @@ func isResourceMovedToDifferentType(newAddr, oldAddr addrs.AbsResourceInstance)
 	return newAddr.Resource.Resource.Type != oldAddr.Resource.Resource.Type
 }
 
+// resourceInstancePrevRunProvider returns the provider that managed the given
+// resource instance before it moved to its current address, or the zero value
+// if it didn't move.
+//
+// The moves have already been applied to the previous run state by the time
+// we get here, so we rely on the move results to remember the provider of
+// the previous address.
+func resourceInstancePrevRunProvider(evalCtx EvalContext, currentAddr addrs.AbsResourceInstance) addrs.Provider {
+	move, moved := evalCtx.MoveResults().Changes.GetOk(currentAddr)
+	if !moved {
+		return addrs.Provider{}
+	}
+	return move.FromProvider.Provider
+}
+
 // readResourceInstanceState reads the current object for a specific instance in
 // the state.
 func (n *NodeAbstractResourceInstance) readResourceInstanceState(ctx context.Context, evalCtx EvalContext, addr addrs.AbsResourceInstance) (*states.ResourceInstanceObject, tfdiags.Diagnostics) {
@@ -635,6 +650,7 @@ func (n *NodeAbstractResourceInstance) readResourceInstanceState(ctx context.Con
 	transformArgs := stateTransformArgs{
 		currentAddr:          addr,
 		prevAddr:             prevAddr,
+		prevProvider:         resourceInstancePrevRunProvider(evalCtx, addr),
 		provider:             provider,
 		objectSrc:            src,
 		currentSchema:        schema.Block,
@@ -699,6 +715,7 @@ func (n *NodeAbstractResourceInstance) readResourceInstanceStateDeposed(ctx cont
 	transformArgs := stateTransformArgs{
 		currentAddr:          addr,
 		prevAddr:             prevAddr,
+		prevProvider:         resourceInstancePrevRunProvider(evalCtx, addr),
 		provider:             provider,
 		objectSrc:            src,
 		currentSchema:        schema.Block,
@@ -3212,9 +3212,11 @@ func (n *NodeAbstractResourceInstance) getProvider(ctx context.Context, evalCtx
 
 	var isOverridden bool
 	var overrideValues map[string]cty.Value
+	var generators *configs.MockGenerators
 
 	if n.ResolvedProvider.IsMocked {
 		isOverridden = true
+		generators = n.ResolvedProvider.MockGenerators
 
 		// Mocked by the provider
 		for _, res := range n.ResolvedProvider.MockResources {
@@ -3228,6 +3230,7 @@ func (n *NodeAbstractResourceInstance) getProvider(ctx context.Context, evalCtx
 		for _, res := range n.ResolvedProvider.OverrideResources {
 			if res.TargetParsed.Equal(n.Addr.ConfigResource()) && res.Mode == n.Addr.Resource.Resource.Mode {
 				overrideValues = res.Values
+				generators = nil
 				break
 			}
 		}
@@ -3237,11 +3240,18 @@ func (n *NodeAbstractResourceInstance) getProvider(ctx context.Context, evalCtx
 		// Overridden in the currently running test (overrides any provider settings)
 		isOverridden = n.Config.IsOverridden
 		overrideValues = n.Config.OverrideValues
+		generators = nil
 	}
 
 	if isOverridden {
 		provider, err := newProviderForTestWithSchema(underlyingProvider, schema, overrideValues)
-		return provider, schema, err
+		// The instances are only expanded once the generators need them, as
+		// that only happens while planning or reading an instance that is
+		// in the configuration, and so is known to the expander.
+		instances := func() []addrs.AbsResourceInstance {
+			return evalCtx.InstanceExpander().ExpandModuleResource(n.Addr.Module.Module(), n.Addr.Resource.Resource)
+		}
+		return provider.withMockGenerators(generators, n.Addr, instances), schema, err
 	}
 
 	return underlyingProvider, schema, nil
@@ -0,0 +1,240 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+var (
+	_ GraphNodeModulePath       = (*nodeDiscoverResources)(nil)
+	_ GraphNodeProviderConsumer = (*nodeDiscoverResources)(nil)
+	_ GraphNodeExecutable       = (*nodeDiscoverResources)(nil)
+)
+
+// nodeDiscoverResources asks a provider to list the existing remote objects
+// of a managed resource type, so that they can be brought under management
+// with import blocks.
+//
+// The node consumes a provider configuration in the root module like a
+// resource would, so that the provider is configured before the node runs.
+// The results are kept in the node for the caller to collect once the walk
+// is complete.
+type nodeDiscoverResources struct {
+	// TypeName is the managed resource type to list.
+	TypeName string
+
+	// ProviderAddr is the provider whose configuration will be used, and
+	// ProviderConfig is the local address of that configuration in the root
+	// module.
+	ProviderAddr   addrs.Provider
+	ProviderConfig addrs.LocalProviderConfig
+
+	// Filters are the raw filter arguments given by the user, which are
+	// converted to the list schema of the resource type.
+	Filters map[string]string
+
+	// Limit is the maximum number of objects to request, or zero for all.
+	Limit int64
+
+	ResolvedProvider ResolvedProvider
+
+	// These are populated by Execute.
+	schema  providers.Schema
+	results []providers.ListResourceResult
+}
+
+func (n *nodeDiscoverResources) Name() string {
+	return fmt.Sprintf("%s (discover)", n.TypeName)
+}
+
+// GraphNodeModulePath
+func (n *nodeDiscoverResources) ModulePath() addrs.Module {
+	return addrs.RootModule
+}
+
+// GraphNodeProviderConsumer
+func (n *nodeDiscoverResources) ProvidedBy() RequestedProvider {
+	return RequestedProvider{
+		ProviderConfig: n.ProviderConfig,
+	}
+}
+
+// GraphNodeProviderConsumer
+func (n *nodeDiscoverResources) Provider() addrs.Provider {
+	return n.ProviderAddr
+}
+
+// GraphNodeProviderConsumer
+func (n *nodeDiscoverResources) SetProvider(resolved ResolvedProvider) {
+	n.ResolvedProvider = resolved
+}
+
+// GraphNodeExecutable
+func (n *nodeDiscoverResources) Execute(ctx context.Context, evalCtx EvalContext, _ walkOperation) (diags tfdiags.Diagnostics) {
+	if n.ResolvedProvider.KeyExpression != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported provider configuration",
+			fmt.Sprintf("Cannot discover %s objects using %s, because it uses for_each. Select a provider configuration without for_each instead.", n.TypeName, n.ResolvedProvider.ProviderConfig),
+		))
+		return diags
+	}
+	key := n.ResolvedProvider.KeyExact
+	if key == nil {
+		key = addrs.NoKey
+	}
+	provider, err := n.ResolvedProvider.Instance(key)
+	if err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+
+	schema, schemaDiags := evalCtx.Providers().GetProviderSchema(ctx, n.ResolvedProvider.ProviderConfig.Provider)
+	diags = diags.Append(schemaDiags)
+	if schemaDiags.HasErrors() {
+		return diags
+	}
+
+	resourceSchema, ok := schema.ResourceTypes[n.TypeName]
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid resource type",
+			fmt.Sprintf("The provider %s does not support resource type %q.", n.ResolvedProvider.ProviderConfig.Provider.ForDisplay(), n.TypeName),
+		))
+		return diags
+	}
+	listSchema, ok := schema.ListResourceTypes[n.TypeName]
+	if !ok || resourceSchema.IdentitySchema == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Resource type does not support discovery",
+			fmt.Sprintf("The provider %s cannot list existing objects of resource type %q, so they must be imported individually instead.", n.ResolvedProvider.ProviderConfig.Provider.ForDisplay(), n.TypeName),
+		))
+		return diags
+	}
+
+	config, filterDiags := discoverFilterConfig(listSchema.Block, n.Filters)
+	diags = diags.Append(filterDiags)
+	if filterDiags.HasErrors() {
+		return diags
+	}
+
+	log.Printf("[TRACE] nodeDiscoverResources: listing %s objects using %s", n.TypeName, n.ResolvedProvider.ProviderConfig)
+	resp := provider.ListResource(ctx, providers.ListResourceRequest{
+		TypeName:              n.TypeName,
+		Config:                config,
+		IncludeResourceObject: true,
+		Limit:                 n.Limit,
+	})
+	diags = diags.Append(resp.Diagnostics)
+	if resp.Diagnostics.HasErrors() {
+		return diags
+	}
+
+	n.schema = resourceSchema
+	n.results = resp.Results
+	return diags
+}
+
+// discoverFilterConfig builds the filter configuration object for a list
+// request from the raw filter arguments given on the command line.
+//
+// String attributes take the raw value as given, while attributes of any
+// other type take it as an HCL expression without any references, so that
+// numbers, booleans and collections can also be used as filters.
+func discoverFilterConfig(schema *configschema.Block, filters map[string]string) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if schema == nil {
+		schema = &configschema.Block{}
+	}
+	attrs := schema.EmptyValue().AsValueMap()
+	if attrs == nil {
+		attrs = make(map[string]cty.Value)
+	}
+
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		raw := filters[name]
+		attr, ok := schema.Attributes[name]
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid filter",
+				fmt.Sprintf("The resource type doesn't support filtering by %q.", name),
+			))
+			continue
+		}
+		ty := attr.ImpliedType()
+
+		val := cty.StringVal(raw)
+		if ty != cty.String {
+			expr, hclDiags := hclsyntax.ParseExpression([]byte(raw), "<filter>", hcl.InitialPos)
+			if !hclDiags.HasErrors() {
+				var valDiags hcl.Diagnostics
+				val, valDiags = expr.Value(nil)
+				hclDiags = append(hclDiags, valDiags...)
+			}
+			if hclDiags.HasErrors() {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid filter",
+					fmt.Sprintf("The value for filter %q must be a valid expression without any references: %s.", name, hclDiags.Error()),
+				))
+				continue
+			}
+		}
+
+		val, err := convert.Convert(val, ty)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid filter",
+				fmt.Sprintf("Unsuitable value for filter %q: %s.", name, tfdiags.FormatError(err)),
+			))
+			continue
+		}
+		attrs[name] = val
+	}
+
+	if len(attrs) == 0 {
+		return cty.EmptyObjectVal, diags
+	}
+	return cty.ObjectVal(attrs), diags
+}
+
+// discoverResourcesTransformer adds the given discovery node to the graph,
+// if any.
+type discoverResourcesTransformer struct {
+	Node *nodeDiscoverResources
+}
+
+func (t *discoverResourcesTransformer) Transform(_ context.Context, g *Graph) error {
+	if t.Node != nil {
+		g.Add(t.Node)
+	}
+	return nil
+}
@@ -773,6 +773,13 @@ func (n *NodePlannableResourceInstance) shouldImport(evalCtx EvalContext) bool {
 // generateHCLStringAttributes produces a string in HCL format for the given
 // resource state and schema without the surrounding block.
 func (n *NodePlannableResourceInstance) generateHCLStringAttributes(addr addrs.AbsResourceInstance, state *states.ResourceInstanceObject, schema *configschema.Block) (string, tfdiags.Diagnostics) {
+	return generateResourceConfig(addr, n.ResolvedProvider.ProviderConfig, state.Value, schema)
+}
+
+// generateResourceConfig produces a string in HCL format for the given
+// resource object value and schema without the surrounding block, omitting
+// the attributes that cannot be set in configuration.
+func generateResourceConfig(addr addrs.AbsResourceInstance, providerConfig addrs.AbsProviderConfig, val cty.Value, schema *configschema.Block) (string, tfdiags.Diagnostics) {
 	filteredSchema := schema.Filter(
 		configschema.FilterOr(
 			configschema.FilterReadOnlyAttribute,
@@ -794,11 +801,11 @@ func (n *NodePlannableResourceInstance) generateHCLStringAttributes(addr addrs.A
 	)
 
 	providerAddr := addrs.LocalProviderConfig{
-		LocalName: n.ResolvedProvider.ProviderConfig.Provider.Type,
-		Alias:     n.ResolvedProvider.ProviderConfig.Alias,
+		LocalName: providerConfig.Provider.Type,
+		Alias:     providerConfig.Alias,
 	}
 
-	return genconfig.GenerateResourceContents(addr, filteredSchema, providerAddr, state.Value)
+	return genconfig.GenerateResourceContents(addr, filteredSchema, providerAddr, val)
 }
 
 // mergeDeps returns the union of 2 sets of dependencies
@@ -9,10 +9,12 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"maps"
 
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/configs/hcl2shim"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -32,6 +34,14 @@ type providerForTest struct {
 	schema   providers.ProviderSchema
 
 	overrideValues map[string]cty.Value
+
+	// generators compute further override values for the resource instance
+	// at addr, if its mock provider declares any generators. instances
+	// returns all the instances of the same resource, which the generators
+	// use to number the instance.
+	generators *configs.MockGenerators
+	addr       addrs.AbsResourceInstance
+	instances  func() []addrs.AbsResourceInstance
 }
 
 func newProviderForTestWithSchema(internal providers.Interface, schema providers.ProviderSchema, overrideValues map[string]cty.Value) (providerForTest, error) {
@@ -46,6 +56,37 @@ func newProviderForTestWithSchema(internal providers.Interface, schema providers
 	}, nil
 }
 
+// withMockGenerators returns a copy of the provider that also uses the given
+// generators to compute the values of the resource instance at addr.
+func (p providerForTest) withMockGenerators(generators *configs.MockGenerators, addr addrs.AbsResourceInstance, instances func() []addrs.AbsResourceInstance) providerForTest {
+	p.generators = generators
+	p.addr = addr
+	p.instances = instances
+	return p
+}
+
+// overridesFor returns the override values to use when composing the value
+// of an object with the given schema and configuration, including any values
+// computed by the generators. The generators never produce values for
+// attributes that already have default values, so the two cannot conflict.
+func (p providerForTest) overridesFor(schema *configschema.Block, config cty.Value) (map[string]cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if p.generators == nil {
+		return p.overrideValues, diags
+	}
+
+	generated, genDiags := p.generators.Generate(p.addr, p.instances(), schema, config)
+	diags = diags.Append(genDiags)
+	if len(generated) == 0 {
+		return p.overrideValues, diags
+	}
+
+	overrides := make(map[string]cty.Value, len(p.overrideValues)+len(generated))
+	maps.Copy(overrides, p.overrideValues)
+	maps.Copy(overrides, generated)
+	return overrides, diags
+}
+
 func (p providerForTest) ReadResource(_ context.Context, r providers.ReadResourceRequest) providers.ReadResourceResponse {
 	var resp providers.ReadResourceResponse
 
@@ -76,8 +117,15 @@ func (p providerForTest) PlanResourceChange(_ context.Context, r providers.PlanR
 	filteredConfig := filterComputedOnlyAttributes(schema, r.Config)
 
 	var resp providers.PlanResourceChangeResponse
+	overrides, diags := p.overridesFor(schema, filteredConfig)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
 	resp.PlannedState, resp.Diagnostics = newMockValueComposer(r.TypeName).
-		ComposeBySchema(schema, filteredConfig, p.overrideValues)
+		ComposeBySchema(schema, filteredConfig, overrides)
+	resp.Diagnostics = diags.Append(resp.Diagnostics)
 
 	return resp
 }
@@ -111,31 +159,49 @@ func (p providerForTest) ReadDataSource(_ context.Context, r providers.ReadDataS
 	resSchema, _ := p.schema.SchemaForResourceType(addrs.DataResourceMode, r.TypeName)
 
 	var resp providers.ReadDataSourceResponse
-	resp.State, resp.Diagnostics = newMockValueComposer(r.TypeName).ComposeBySchema(resSchema.Block, r.Config, p.overrideValues)
+	overrides, diags := p.overridesFor(resSchema.Block, r.Config)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	resp.State, resp.Diagnostics = newMockValueComposer(r.TypeName).ComposeBySchema(resSchema.Block, r.Config, overrides)
+	resp.Diagnostics = diags.Append(resp.Diagnostics)
 
 	return resp
 }
 
+// OpenEphemeralResource composes the result of a mocked or overridden ephemeral
+// resource in the same way as ReadDataSource. The result never asks to be
+// renewed, since there are no real credentials behind it that could expire.
 func (p providerForTest) OpenEphemeralResource(_ context.Context, r providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
 	resSchema, _ := p.schema.SchemaForResourceType(addrs.EphemeralResourceMode, r.TypeName)
 
-	resp.Result, resp.Diagnostics = newMockValueComposer(r.TypeName).ComposeBySchema(resSchema.Block, r.Config, p.overrideValues)
+	overrides, diags := p.overridesFor(resSchema.Block, r.Config)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	resp.Result, resp.Diagnostics = newMockValueComposer(r.TypeName).ComposeBySchema(resSchema.Block, r.Config, overrides)
+	resp.Diagnostics = diags.Append(resp.Diagnostics)
 	return resp
 }
 
-func (p providerForTest) RenewEphemeralResource(_ context.Context, _ providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
-	// TODO ephemeral testing support - implement me when adding testing support
-
-	// In order to fix the issue reported in https://github.com/opentofu/opentofu/issues/4251, OpenEphemeralResource and CloseEphemeralResource
-	// had their `panic` call removed and implemented properly to ensure that `tofu test` can be executed against a
-	// configuration containing `ephemeral` blocks. The fix provided just fixed the panic without implementing any
-	// testing functionality for ephemeral resources. Therefore, RenewEphemeralResource has no reason to be implemented
-	// because it cannot be reached as it relies on OpenEphemeralResource to return a specific value in the RenewAt to
-	// have this called. Without any testing functionality to mock the value for RenewAt, this will never be called so
-	// we want to have the panic in place.
-	panic("implement me")
+// RenewEphemeralResource is not supported for a mocked ephemeral resource, since
+// OpenEphemeralResource never requests a renewal and there is nothing behind the
+// mocked values to renew. Rather than pretending to succeed, we reject the call so
+// that any test relying on renewal fails with an explanation.
+func (p providerForTest) RenewEphemeralResource(_ context.Context, r providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Cannot renew a mocked ephemeral resource",
+		fmt.Sprintf("The ephemeral resource type %q is mocked or overridden in this test, and mocked ephemeral resources cannot be renewed. Test the renewal behavior against the real provider instead.", r.TypeName),
+	))
+	return resp
 }
 
+// CloseEphemeralResource has nothing to release for a mocked ephemeral resource.
 func (p providerForTest) CloseEphemeralResource(_ context.Context, _ providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
 	return resp
 }
@@ -167,6 +233,10 @@ func (p providerForTest) ImportResourceState(context.Context, providers.ImportRe
 	panic("Importing is not supported in testing context. providerForTest must not be used to call ImportResourceState")
 }
 
+func (p providerForTest) ListResource(context.Context, providers.ListResourceRequest) providers.ListResourceResponse {
+	panic("Listing is not supported in testing context. providerForTest must not be used to call ListResource")
+}
+
 func (p providerForTest) MoveResourceState(context.Context, providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
 	panic("Moving is not supported in testing context. providerForTest must not be used to call MoveResourceState")
 }
@@ -155,3 +155,76 @@ func TestFilterComputedOnlyAttributes(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderForTest_ephemeralLifecycle(t *testing.T) {
+	schema := providers.ProviderSchema{
+		EphemeralResources: map[string]providers.Schema{
+			"test_ephemeral": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":     {Type: cty.String, Required: true},
+						"secret": {Type: cty.String, Computed: true},
+						"token":  {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+	}
+	underlying := &MockProvider{}
+
+	provider, err := newProviderForTestWithSchema(underlying, schema, map[string]cty.Value{
+		"secret": cty.StringVal("hunter2"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	openResp := provider.OpenEphemeralResource(t.Context(), providers.OpenEphemeralResourceRequest{
+		TypeName: "test_ephemeral",
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"id":     cty.StringVal("a"),
+			"secret": cty.NullVal(cty.String),
+			"token":  cty.NullVal(cty.String),
+		}),
+	})
+	if openResp.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected error: %s", openResp.Diagnostics.Err())
+	}
+	if got, want := openResp.Result.GetAttr("id"), cty.StringVal("a"); !got.RawEquals(want) {
+		t.Errorf("wrong id %#v; want %#v", got, want)
+	}
+	if got, want := openResp.Result.GetAttr("secret"), cty.StringVal("hunter2"); !got.RawEquals(want) {
+		t.Errorf("wrong secret %#v; want %#v", got, want)
+	}
+	if token := openResp.Result.GetAttr("token"); token.IsNull() || !token.IsKnown() {
+		t.Errorf("expected a generated token, got %#v", token)
+	}
+	if openResp.RenewAt != nil {
+		t.Errorf("mocked ephemeral resources should not request renewal, got %s", openResp.RenewAt)
+	}
+
+	renewResp := provider.RenewEphemeralResource(t.Context(), providers.RenewEphemeralResourceRequest{
+		TypeName: "test_ephemeral",
+		Private:  []byte("private"),
+	})
+	if !renewResp.Diagnostics.HasErrors() {
+		t.Fatal("expected an error when renewing a mocked ephemeral resource")
+	}
+	if got, want := renewResp.Diagnostics.Err().Error(), "Cannot renew a mocked ephemeral resource"; !strings.Contains(got, want) {
+		t.Errorf("wrong error %q; want it to contain %q", got, want)
+	}
+	if renewResp.RenewAt != nil {
+		t.Errorf("mocked ephemeral resources should not request renewal, got %s", renewResp.RenewAt)
+	}
+
+	closeResp := provider.CloseEphemeralResource(t.Context(), providers.CloseEphemeralResourceRequest{
+		TypeName: "test_ephemeral",
+	})
+	if closeResp.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected error: %s", closeResp.Diagnostics.Err())
+	}
+
+	if underlying.OpenEphemeralResourceCalled || underlying.RenewEphemeralResourceCalled || underlying.CloseEphemeralResourceCalled {
+		t.Error("the underlying provider should not have been called")
+	}
+}
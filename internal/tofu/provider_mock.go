@@ -101,6 +101,11 @@ type MockProvider struct {
 	ImportResourceStateRequest  providers.ImportResourceStateRequest
 	ImportResourceStateFn       func(providers.ImportResourceStateRequest) providers.ImportResourceStateResponse
 
+	ListResourceCalled   bool
+	ListResourceResponse *providers.ListResourceResponse
+	ListResourceRequest  providers.ListResourceRequest
+	ListResourceFn       func(providers.ListResourceRequest) providers.ListResourceResponse
+
 	ReadDataSourceCalled   bool
 	ReadDataSourceResponse *providers.ReadDataSourceResponse
 	ReadDataSourceRequest  providers.ReadDataSourceRequest
@@ -650,6 +655,29 @@ func (p *MockProvider) ImportResourceState(ctx context.Context, r providers.Impo
 	return resp
 }
 
+func (p *MockProvider) ListResource(ctx context.Context, r providers.ListResourceRequest) (resp providers.ListResourceResponse) {
+	tracing.ContextProbeReport(ctx, 0)
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.ConfigureProviderCalled {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Configure not called before ListResource %q", r.TypeName))
+		return resp
+	}
+
+	p.ListResourceCalled = true
+	p.ListResourceRequest = r
+	if p.ListResourceFn != nil {
+		return p.ListResourceFn(r)
+	}
+
+	if p.ListResourceResponse != nil {
+		resp = *p.ListResourceResponse
+	}
+
+	return resp
+}
+
 func (p *MockProvider) ReadDataSource(ctx context.Context, r providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
 	tracing.ContextProbeReport(ctx, 0)
 	p.Lock()
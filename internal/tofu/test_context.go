@@ -100,8 +100,10 @@ func (tc *TestContext) evaluate(state *states.SyncState, changes *plans.ChangesS
 			// The GetModule function will fall back to using state/changes when it's nil
 			InstanceExpander: nil,
 		},
-		ModulePath:      nil, // nil for the root module
-		InstanceKeyData: EvalDataForNoInstanceKey,
+		ModulePath: nil, // nil for the root module
+		// Run blocks expanded from for_each or a matrix block can refer to
+		// each.key and each.value in their assertions.
+		InstanceKeyData: run.Config.Each,
 		Operation:       operation,
 	}
 
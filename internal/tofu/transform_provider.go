@@ -65,7 +65,7 @@ type GraphNodeProvider interface {
 	// Call close for all provider instances within this GraphNodeProvider
 	Close(ctx context.Context) error
 	// For test framework
-	MocksAndOverrides() (IsMocked bool, MockResources []*configs.MockResource, OverrideResources []*configs.OverrideResource)
+	MocksAndOverrides() (IsMocked bool, MockResources []*configs.MockResource, OverrideResources []*configs.OverrideResource, MockGenerators *configs.MockGenerators)
 }
 
 // GraphNodeCloseProvider is an interface that nodes that can be a close
@@ -99,6 +99,7 @@ type ResolvedProvider struct {
 	IsMocked          bool
 	MockResources     []*configs.MockResource
 	OverrideResources []*configs.OverrideResource
+	MockGenerators    *configs.MockGenerators
 }
 
 // GraphNodeProviderConsumer is an interface that nodes that require
@@ -201,7 +202,7 @@ func (t *ProviderTransformer) Transform(_ context.Context, g *Graph) error {
 				KeyResource:   req.KeyResource,
 				KeyExact:      req.KeyExact,
 			}
-			resolved.IsMocked, resolved.MockResources, resolved.OverrideResources = target.MocksAndOverrides()
+			resolved.IsMocked, resolved.MockResources, resolved.OverrideResources, resolved.MockGenerators = target.MocksAndOverrides()
 			pv.SetProvider(resolved)
 
 			g.Connect(dag.BasicEdge(v, target))
@@ -273,7 +274,7 @@ func (t *ProviderTransformer) Transform(_ context.Context, g *Graph) error {
 			resolved.Instance = target.Instance
 
 			// Include test mocking and override extensions
-			resolved.IsMocked, resolved.MockResources, resolved.OverrideResources = target.MocksAndOverrides()
+			resolved.IsMocked, resolved.MockResources, resolved.OverrideResources, resolved.MockGenerators = target.MocksAndOverrides()
 
 			log.Printf("[DEBUG] ProviderTransformer: %q (%T) needs %s", dag.VertexName(v), v, dag.VertexName(target))
 			pv.SetProvider(resolved)
@@ -754,7 +755,7 @@ func (n *graphNodeProxyProvider) Target() GraphNodeProvider {
 	}
 }
 
-func (n *graphNodeProxyProvider) MocksAndOverrides() (IsMocked bool, MockResources []*configs.MockResource, OverrideResources []*configs.OverrideResource) {
+func (n *graphNodeProxyProvider) MocksAndOverrides() (IsMocked bool, MockResources []*configs.MockResource, OverrideResources []*configs.OverrideResource, MockGenerators *configs.MockGenerators) {
 	return n.Target().MocksAndOverrides()
 }
 
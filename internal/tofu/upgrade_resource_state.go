@@ -25,9 +25,12 @@ type stateTransformArgs struct {
 	// currentAddr is the current/latest address of the resource
 	currentAddr addrs.AbsResourceInstance
 	// prevAddr is the previous address of the resource
-	prevAddr  addrs.AbsResourceInstance
-	provider  providers.Interface
-	objectSrc *states.ResourceInstanceObjectSrc
+	prevAddr addrs.AbsResourceInstance
+	// prevProvider is the provider that managed the object at prevAddr in
+	// the previous run state, or the zero value if it isn't known.
+	prevProvider addrs.Provider
+	provider     providers.Interface
+	objectSrc    *states.ResourceInstanceObjectSrc
 	// currentSchema is the latest schema of the resource
 	currentSchema *configschema.Block
 	// currentSchemaVersion is the latest schema version of the resource
@@ -132,8 +135,14 @@ func moveResourceState(params stateTransformArgs) (*states.ResourceInstanceObjec
 // moveResourceStateTransform is a providerStateTransform that moves the state via provider move logic
 func moveResourceStateTransform(args stateTransformArgs) (cty.Value, []byte, tfdiags.Diagnostics) {
 	log.Printf("[TRACE] moveResourceStateTransform: new address: %s, previous address: %s", args.currentAddr, args.prevAddr)
+	sourceProvider := args.prevProvider
+	if sourceProvider.IsZero() {
+		// Without any record of the previous provider we fall back to the
+		// default provider implied by the previous resource type.
+		sourceProvider = addrs.ImpliedProviderForUnqualifiedType(args.prevAddr.Resource.Resource.ImpliedProvider())
+	}
 	req := providers.MoveResourceStateRequest{
-		SourceProviderAddress: args.prevAddr.Resource.Resource.ImpliedProvider(),
+		SourceProviderAddress: sourceProvider.String(),
 		SourceTypeName:        args.prevAddr.Resource.Resource.Type,
 		SourceSchemaVersion:   args.objectSrc.SchemaVersion,
 		SourceStateJSON:       args.objectSrc.AttrsJSON,
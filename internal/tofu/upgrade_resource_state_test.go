@@ -227,7 +227,26 @@ func TestMoveResourceStateTransform(t *testing.T) {
 			name: "Move check request",
 			args: getMoveStateArgs(),
 			wantRequest: &providers.MoveResourceStateRequest{
-				SourceProviderAddress: "foo",
+				SourceProviderAddress: "registry.opentofu.org/hashicorp/foo",
+				SourceTypeName:        "foo_instance",
+				SourceSchemaVersion:   2,
+				SourceStateJSON:       []byte(`{"foo":"bar"}`),
+				SourceStateFlatmap:    map[string]string{"foo": "bar"},
+				SourcePrivate:         []byte("private"),
+				TargetTypeName:        "foo2_instance",
+			},
+		},
+		// The provider recorded in the previous run state takes precedence
+		// over the one implied by the previous resource type.
+		{
+			name: "Move check request with previous provider",
+			args: func() stateTransformArgs {
+				args := getMoveStateArgs()
+				args.prevProvider = addrs.NewProvider(addrs.DefaultProviderRegistryHost, "acme", "foo")
+				return args
+			}(),
+			wantRequest: &providers.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.opentofu.org/acme/foo",
 				SourceTypeName:        "foo_instance",
 				SourceSchemaVersion:   2,
 				SourceStateJSON:       []byte(`{"foo":"bar"}`),